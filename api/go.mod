@@ -2,7 +2,10 @@ module github.com/openmcp-project/controller-utils/api
 
 go 1.26.5
 
-require github.com/fluxcd/pkg/apis/kustomize v1.20.0
+require (
+	github.com/fluxcd/pkg/apis/kustomize v1.20.0
+	k8s.io/apiextensions-apiserver v0.36.2
+)
 
 require (
 	github.com/fxamacker/cbor/v2 v2.9.0 // indirect
@@ -16,7 +19,6 @@ require (
 	golang.org/x/net v0.55.0 // indirect
 	golang.org/x/text v0.38.0 // indirect
 	gopkg.in/inf.v0 v0.9.1 // indirect
-	k8s.io/apiextensions-apiserver v0.36.2 // indirect
 	k8s.io/apimachinery v0.36.2 // indirect
 	k8s.io/klog/v2 v2.140.0 // indirect
 	k8s.io/kube-openapi v0.0.0-20260317180543-43fb72c5454a // indirect
@@ -2,7 +2,10 @@
 package jsonpatch
 
 import (
+	"encoding/json"
+
 	"github.com/fluxcd/pkg/apis/kustomize"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 )
 
 // JSONPatch represents a JSON patch operation.
@@ -14,6 +17,38 @@ type JSONPatch = kustomize.JSON6902
 // This is technically a 'JSON patch' as defined in RFC 6902.
 type JSONPatches []JSONPatch
 
+// UnmarshalJSON implements json.Unmarshaler.
+// Opposed to the default behavior, an explicit 'null' value of an operation results in a non-nil Value containing 'null',
+// so that it can be distinguished from an operation without a value.
+func (p *JSONPatches) UnmarshalJSON(data []byte) error {
+	var raw []struct {
+		Op    string          `json:"op"`
+		Path  string          `json:"path"`
+		From  string          `json:"from,omitempty"`
+		Value json.RawMessage `json:"value,omitempty"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw == nil {
+		*p = nil
+		return nil
+	}
+	res := make(JSONPatches, len(raw))
+	for i, r := range raw {
+		res[i] = JSONPatch{
+			Op:   r.Op,
+			Path: r.Path,
+			From: r.From,
+		}
+		if r.Value != nil {
+			res[i].Value = &apiextensionsv1.JSON{Raw: r.Value}
+		}
+	}
+	*p = res
+	return nil
+}
+
 const (
 	// ADD is the constant for the JSONPatch 'add' operation.
 	ADD = "add"
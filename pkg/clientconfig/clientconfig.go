@@ -3,6 +3,7 @@ package clientconfig
 import (
 	"context"
 	"encoding/base64"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"os"
@@ -11,6 +12,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -29,6 +31,11 @@ var (
 
 	ErrInvalidConnectionMethod      = errors.New("exactly one connection method has to be specified")
 	ErrServiceAccountNamespaceEmpty = errors.New("service account namespace must be specified")
+	ErrServiceAccountNameEmpty      = errors.New("service account name must be specified")
+	ErrInvalidServiceAccountName    = errors.New("service account name and namespace must be valid k8s names")
+	ErrCAFileAndCAData              = errors.New("only one of caFile and caData may be specified")
+	ErrInvalidCAData                = errors.New("caData must contain PEM-encoded or base64-encoded PEM data")
+	ErrInvalidTokenFile             = errors.New("tokenFile must point to a readable file")
 
 	reloadNoOp ReloadFunc = func() error { return nil }
 )
@@ -45,6 +52,18 @@ func New(target api.Target) *Config {
 
 type Config struct {
 	api.Target
+
+	rawKubeconfig []byte
+}
+
+// WithRawKubeconfig configures the Config to use the given kubeconfig bytes.
+// This is useful if the kubeconfig has been obtained at runtime, e.g. from a secret that is already in memory.
+// This is a connection method on its own and therefore mutually exclusive with the ones from the API target.
+// Passing in nil removes a previously configured raw kubeconfig.
+// Returns the receiver for chaining.
+func (c *Config) WithRawKubeconfig(kubeconfig []byte) *Config {
+	c.rawKubeconfig = kubeconfig
+	return c
 }
 
 func (c *Config) validate() error {
@@ -61,6 +80,9 @@ func (c *Config) validate() error {
 	if c.ServiceAccount != nil {
 		methods++
 	}
+	if c.rawKubeconfig != nil {
+		methods++
+	}
 
 	if methods != 1 {
 		return ErrInvalidConnectionMethod
@@ -71,7 +93,7 @@ func (c *Config) validate() error {
 
 // GetRESTConfig creates a *rest.Config for the given API target.
 // The second return value is a function which can be used to reload the config.
-// This reload func is a no-op for "Kubeconfig" and "ServiceAccount" target types.
+// This reload func is a no-op for "Kubeconfig" and "ServiceAccount" target types, as well as for raw kubeconfigs configured via WithRawKubeconfig.
 func (c *Config) GetRESTConfig() (*rest.Config, ReloadFunc, error) {
 	if err := c.validate(); err != nil {
 		return nil, nil, err
@@ -93,6 +115,10 @@ func (c *Config) GetRESTConfig() (*rest.Config, ReloadFunc, error) {
 		return c.handleServiceAccount()
 	}
 
+	if c.rawKubeconfig != nil {
+		return c.handleRawKubeconfig()
+	}
+
 	return nil, nil, ErrInvalidConnectionMethod
 }
 
@@ -101,6 +127,11 @@ func (c *Config) handleKubeconfig() (*rest.Config, ReloadFunc, error) {
 	return config, reloadNoOp, err
 }
 
+func (c *Config) handleRawKubeconfig() (*rest.Config, ReloadFunc, error) {
+	config, err := clientcmd.RESTConfigFromKubeConfig(c.rawKubeconfig)
+	return config, reloadNoOp, err
+}
+
 func (c *Config) handleKubeconfigFile() (*rest.Config, ReloadFunc, error) {
 	remoteConfig := &rest.Config{}
 
@@ -172,13 +203,13 @@ func (c *Config) handleServiceAccount() (*rest.Config, ReloadFunc, error) {
 		cfg.BearerTokenFile = c.ServiceAccount.TokenFile
 	}
 
-	if c.ServiceAccount.Name != "" {
-		if c.ServiceAccount.Namespace == "" {
-			return nil, nil, ErrServiceAccountNamespaceEmpty
-		}
-
+	userName, err := ServiceAccountUserName(c.ServiceAccount)
+	if err != nil {
+		return nil, nil, err
+	}
+	if userName != "" {
 		cfg.Impersonate = rest.ImpersonationConfig{
-			UserName: fmt.Sprintf("system:serviceaccount:%s:%s", c.ServiceAccount.Name, c.ServiceAccount.Namespace),
+			UserName: userName,
 		}
 	}
 
@@ -207,9 +238,83 @@ func (c *Config) handleServiceAccount() (*rest.Config, ReloadFunc, error) {
 	return cfg, reloadNoOp, nil
 }
 
+// ServiceAccountUserName returns the username that is used to impersonate the ServiceAccount from the given configuration.
+// The username has the format 'system:serviceaccount:<namespace>:<name>'.
+// Returns the empty string if neither name nor namespace are set, which means that no impersonation is requested.
+// The returned error wraps ErrServiceAccountNameEmpty or ErrServiceAccountNamespaceEmpty if only one of them is set,
+// or ErrInvalidServiceAccountName if the name is not a valid DNS subdomain or the namespace is not a valid DNS label.
+func ServiceAccountUserName(sa *api.ServiceAccountConfig) (string, error) {
+	if sa == nil || (sa.Name == "" && sa.Namespace == "") {
+		return "", nil
+	}
+	if sa.Name == "" {
+		return "", ErrServiceAccountNameEmpty
+	}
+	if sa.Namespace == "" {
+		return "", ErrServiceAccountNamespaceEmpty
+	}
+	if errs := validation.IsDNS1123Label(sa.Namespace); len(errs) > 0 {
+		return "", fmt.Errorf("%w: invalid namespace '%s': %s", ErrInvalidServiceAccountName, sa.Namespace, strings.Join(errs, "; "))
+	}
+	if errs := validation.IsDNS1123Subdomain(sa.Name); len(errs) > 0 {
+		return "", fmt.Errorf("%w: invalid name '%s': %s", ErrInvalidServiceAccountName, sa.Name, strings.Join(errs, "; "))
+	}
+	return fmt.Sprintf("system:serviceaccount:%s:%s", sa.Namespace, sa.Name), nil
+}
+
+// ValidateServiceAccountConfig validates the given service account configuration.
+// It checks that not both CAFile and CAData are specified, that CAData (if set and not empty) contains PEM or base64-encoded PEM data,
+// that TokenFile (if set) points to a readable file, and that name and namespace (if set) result in a valid impersonation username, see ServiceAccountUserName.
+// The returned error wraps one of the ErrCAFileAndCAData, ErrInvalidCAData, ErrInvalidTokenFile, ErrServiceAccountNameEmpty, ErrServiceAccountNamespaceEmpty, or ErrInvalidServiceAccountName errors.
+// A nil config is considered valid.
+func ValidateServiceAccountConfig(sa *api.ServiceAccountConfig) error {
+	if sa == nil {
+		return nil
+	}
+
+	if sa.CAFile != nil && sa.CAData != nil {
+		return ErrCAFileAndCAData
+	}
+
+	if sa.CAData != nil && *sa.CAData != "" {
+		data := []byte(*sa.CAData)
+		if !strings.HasPrefix(strings.TrimSpace(*sa.CAData), pemPrefix) {
+			decoded, err := base64.StdEncoding.DecodeString(*sa.CAData)
+			if err != nil {
+				return fmt.Errorf("%w: %w", ErrInvalidCAData, err)
+			}
+			data = decoded
+		}
+		if block, _ := pem.Decode(data); block == nil {
+			return fmt.Errorf("%w: no PEM block found", ErrInvalidCAData)
+		}
+	}
+
+	if sa.TokenFile != "" {
+		fi, err := os.Stat(sa.TokenFile)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidTokenFile, err)
+		}
+		if fi.IsDir() {
+			return fmt.Errorf("%w: '%s' is a directory", ErrInvalidTokenFile, sa.TokenFile)
+		}
+		f, err := os.Open(sa.TokenFile)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidTokenFile, err)
+		}
+		_ = f.Close()
+	}
+
+	if _, err := ServiceAccountUserName(sa); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // GetClient creates a client.Client for the given API target.
 // The second return value is a function which can be used to reload the config.
-// This reload func is a no-op for "Kubeconfig" and "ServiceAccount" target types.
+// This reload func is a no-op for "Kubeconfig" and "ServiceAccount" target types, as well as for raw kubeconfigs configured via WithRawKubeconfig.
 func (c *Config) GetClient(options client.Options) (client.Client, ReloadFunc, error) {
 	restConfig, reloadFunc, err := c.GetRESTConfig()
 	if err != nil {
@@ -1,8 +1,12 @@
 package clientconfig
 
 import (
+	"context"
+	"encoding/base64"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -16,6 +20,7 @@ import (
 type test_input struct {
 	config         api.Target
 	kubeconfigFile string
+	rawKubeconfig  []byte
 }
 
 type test_want struct {
@@ -60,6 +65,85 @@ l0nUqSl1FdVvsCo8hUS8XZuciDluhp4Lq6fXx5002SWC1jP4rOvZvs7F
 	}
 )
 
+func mustReadFile(path string) []byte {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+// serviceAccountNameTestCases lock down the format of the impersonation username for ServiceAccount targets.
+var serviceAccountNameTestCases = []struct {
+	desc  string
+	input test_input
+	want  test_want
+}{
+	{
+		desc: "should impersonate the service account as system:serviceaccount:<namespace>:<name>",
+		input: test_input{
+			kubeconfigFile: "testdata/valid.yaml",
+			config: api.Target{
+				ServiceAccount: &api.ServiceAccountConfig{
+					Name:      "my-sa",
+					Namespace: "my-ns",
+				},
+			},
+		},
+		want: test_want{
+			host:  noerror.host,
+			token: noerror.token,
+			impersonation: rest.ImpersonationConfig{
+				UserName: "system:serviceaccount:my-ns:my-sa",
+			},
+			caData: noerror.caData,
+		},
+	},
+	{
+		desc: "should fail if the service account namespace is empty",
+		input: test_input{
+			kubeconfigFile: "testdata/valid.yaml",
+			config: api.Target{
+				ServiceAccount: &api.ServiceAccountConfig{
+					Name: "my-sa",
+				},
+			},
+		},
+		want: test_want{
+			err: ErrServiceAccountNamespaceEmpty,
+		},
+	},
+	{
+		desc: "should fail if the service account name is empty",
+		input: test_input{
+			kubeconfigFile: "testdata/valid.yaml",
+			config: api.Target{
+				ServiceAccount: &api.ServiceAccountConfig{
+					Namespace: "my-ns",
+				},
+			},
+		},
+		want: test_want{
+			err: ErrServiceAccountNameEmpty,
+		},
+	},
+	{
+		desc: "should fail if the service account namespace is invalid",
+		input: test_input{
+			kubeconfigFile: "testdata/valid.yaml",
+			config: api.Target{
+				ServiceAccount: &api.ServiceAccountConfig{
+					Name:      "my-sa",
+					Namespace: "my:ns",
+				},
+			},
+		},
+		want: test_want{
+			err: ErrInvalidServiceAccountName,
+		},
+	},
+}
+
 func Test_GetRESTConfig(t *testing.T) {
 	testCases := []struct {
 		desc  string
@@ -96,6 +180,25 @@ users:
 			},
 			want: noerror,
 		},
+		{
+			desc: "should read raw kubeconfig bytes",
+			input: test_input{
+				rawKubeconfig: mustReadFile("testdata/valid.yaml"),
+			},
+			want: noerror,
+		},
+		{
+			desc: "should fail because raw kubeconfig bytes and another method are configured",
+			input: test_input{
+				config: api.Target{
+					KubeconfigFile: ptr.To("testdata/valid.yaml"),
+				},
+				rawKubeconfig: mustReadFile("testdata/valid.yaml"),
+			},
+			want: test_want{
+				err: ErrInvalidConnectionMethod,
+			},
+		},
 		{
 			desc: "should fail because multiple methods are configured",
 			input: test_input{
@@ -156,7 +259,7 @@ users:
 				err:  nil,
 				host: "https://custom-api.example.com",
 				impersonation: rest.ImpersonationConfig{
-					UserName: "system:serviceaccount:myuser:mynamespace",
+					UserName: "system:serviceaccount:mynamespace:myuser",
 				},
 				token:     "",
 				tokenFile: "testdata/token",
@@ -177,7 +280,7 @@ users:
 			want: noerror,
 		},
 	}
-	for _, tC := range testCases {
+	for _, tC := range append(testCases, serviceAccountNameTestCases...) {
 		t.Run(tC.desc, func(t *testing.T) {
 			if tC.input.kubeconfigFile != "" {
 				os.Setenv("KUBECONFIG", tC.input.kubeconfigFile)
@@ -186,6 +289,9 @@ users:
 			}
 
 			wrapped := New(tC.input.config)
+			if tC.input.rawKubeconfig != nil {
+				wrapped.WithRawKubeconfig(tC.input.rawKubeconfig)
+			}
 			conf, reloadFunc, err := wrapped.GetRESTConfig()
 			client, reloadFunc2, clienterr := wrapped.GetClient(client.Options{})
 
@@ -235,3 +341,200 @@ func Test_KubeconfigFile_Reload(t *testing.T) {
 	assert.Equal(t, "https://api.example.org", conf.Host)
 	assert.Equal(t, "vp98rIsJJZ3qcoHAsUhg", conf.BearerToken)
 }
+
+func Test_ValidateServiceAccountConfig(t *testing.T) {
+	testCases := []struct {
+		desc string
+		sa   *api.ServiceAccountConfig
+		err  error
+	}{
+		{
+			desc: "should accept a nil config",
+			sa:   nil,
+		},
+		{
+			desc: "should accept a valid config with base64-encoded CA data",
+			sa: &api.ServiceAccountConfig{
+				Name:      "myuser",
+				Namespace: "mynamespace",
+				Host:      "https://custom-api.example.com",
+				CAData:    ptr.To(base64.StdEncoding.EncodeToString([]byte(noerror.caData))),
+				TokenFile: "testdata/token",
+			},
+		},
+		{
+			desc: "should accept a valid config with pem-encoded CA data",
+			sa: &api.ServiceAccountConfig{
+				CAData: ptr.To(noerror.caData),
+			},
+		},
+		{
+			desc: "should accept empty CA data",
+			sa: &api.ServiceAccountConfig{
+				CAData: ptr.To(""),
+			},
+		},
+		{
+			desc: "should accept a CA file",
+			sa: &api.ServiceAccountConfig{
+				CAFile: ptr.To("/etc/custom/ca.crt"),
+			},
+		},
+		{
+			desc: "should fail if both CA file and CA data are specified",
+			sa: &api.ServiceAccountConfig{
+				CAFile: ptr.To("/etc/custom/ca.crt"),
+				CAData: ptr.To(noerror.caData),
+			},
+			err: ErrCAFileAndCAData,
+		},
+		{
+			desc: "should fail if CA data is neither base64 nor PEM",
+			sa: &api.ServiceAccountConfig{
+				CAData: ptr.To("this is not base64!"),
+			},
+			err: ErrInvalidCAData,
+		},
+		{
+			desc: "should fail if base64-decoded CA data is not PEM",
+			sa: &api.ServiceAccountConfig{
+				CAData: ptr.To(base64.StdEncoding.EncodeToString([]byte("not a certificate"))),
+			},
+			err: ErrInvalidCAData,
+		},
+		{
+			desc: "should fail if CA data has a PEM prefix but is malformed",
+			sa: &api.ServiceAccountConfig{
+				CAData: ptr.To("-----BEGIN CERTIFICATE-----\nfoo"),
+			},
+			err: ErrInvalidCAData,
+		},
+		{
+			desc: "should fail if the token file does not exist",
+			sa: &api.ServiceAccountConfig{
+				TokenFile: "testdata/doesnotexist",
+			},
+			err: ErrInvalidTokenFile,
+		},
+		{
+			desc: "should fail if the token file is a directory",
+			sa: &api.ServiceAccountConfig{
+				TokenFile: "testdata",
+			},
+			err: ErrInvalidTokenFile,
+		},
+		{
+			desc: "should fail if only the namespace is specified",
+			sa: &api.ServiceAccountConfig{
+				Namespace: "mynamespace",
+			},
+			err: ErrServiceAccountNameEmpty,
+		},
+		{
+			desc: "should fail if the name is invalid",
+			sa: &api.ServiceAccountConfig{
+				Name:      "My_User",
+				Namespace: "mynamespace",
+			},
+			err: ErrInvalidServiceAccountName,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			err := ValidateServiceAccountConfig(tC.sa)
+			if tC.err == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tC.err)
+		})
+	}
+}
+
+func Test_GetRESTConfigWithWatch(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "kubeconfig")
+	assert.NoError(t, os.WriteFile(path, mustReadFile("testdata/valid.yaml"), 0o600))
+
+	configs := make(chan *rest.Config, 10)
+	wrapped := New(api.Target{
+		KubeconfigFile: ptr.To(path),
+	})
+	conf, stop, err := wrapped.GetRESTConfigWithWatch(context.Background(), func(cfg *rest.Config, err error) {
+		if assert.NoError(t, err) {
+			configs <- cfg
+		}
+	})
+	assert.NoError(t, err)
+	defer stop()
+	assert.Equal(t, "https://api.example.com", conf.Host)
+
+	// replace the file atomically
+	tmp := filepath.Join(dir, "kubeconfig.tmp")
+	assert.NoError(t, os.WriteFile(tmp, mustReadFile("testdata/valid2.yaml"), 0o600))
+	assert.NoError(t, os.Rename(tmp, path))
+
+	select {
+	case cfg := <-configs:
+		assert.Equal(t, "https://api.example.org", cfg.Host)
+		assert.Equal(t, "vp98rIsJJZ3qcoHAsUhg", cfg.BearerToken)
+		assert.NotSame(t, conf, cfg)
+	case <-time.After(10 * time.Second):
+		t.Fatal("callback has not been called after the kubeconfig file changed")
+	}
+	// the returned config must not be modified
+	assert.Equal(t, "https://api.example.com", conf.Host)
+
+	stop()
+	stop()
+}
+
+func Test_GetRESTConfigWithWatch_SymlinkSwap(t *testing.T) {
+	// mimic the layout of a k8s secret volume: kubeconfig -> ..data/kubeconfig, ..data -> ..<timestamp>
+	dir := t.TempDir()
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "..2026_01_01_00_00_00.1"), 0o700))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "..2026_01_01_00_00_00.1", "kubeconfig"), mustReadFile("testdata/valid.yaml"), 0o600))
+	assert.NoError(t, os.Symlink("..2026_01_01_00_00_00.1", filepath.Join(dir, "..data")))
+	path := filepath.Join(dir, "kubeconfig")
+	assert.NoError(t, os.Symlink(filepath.Join("..data", "kubeconfig"), path))
+
+	configs := make(chan *rest.Config, 10)
+	wrapped := New(api.Target{
+		KubeconfigFile: ptr.To(path),
+	})
+	conf, stop, err := wrapped.GetRESTConfigWithWatch(context.Background(), func(cfg *rest.Config, err error) {
+		if assert.NoError(t, err) {
+			configs <- cfg
+		}
+	})
+	assert.NoError(t, err)
+	defer stop()
+	assert.Equal(t, "https://api.example.com", conf.Host)
+
+	// swap the '..data' symlink, the same way the kubelet does it
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "..2026_01_01_00_00_00.2"), 0o700))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "..2026_01_01_00_00_00.2", "kubeconfig"), mustReadFile("testdata/valid2.yaml"), 0o600))
+	assert.NoError(t, os.Symlink("..2026_01_01_00_00_00.2", filepath.Join(dir, "..data_tmp")))
+	assert.NoError(t, os.Rename(filepath.Join(dir, "..data_tmp"), filepath.Join(dir, "..data")))
+	assert.NoError(t, os.RemoveAll(filepath.Join(dir, "..2026_01_01_00_00_00.1")))
+
+	select {
+	case cfg := <-configs:
+		assert.Equal(t, "https://api.example.org", cfg.Host)
+	case <-time.After(3 * time.Second):
+		// shorter than the poll interval, so that this doesn't succeed by polling
+		t.Fatal("callback has not been called after the '..data' symlink has been swapped")
+	}
+}
+
+func Test_GetRESTConfigWithWatch_NoFile(t *testing.T) {
+	wrapped := New(api.Target{
+		Kubeconfig: &v1.JSON{Raw: mustReadFile("testdata/valid.yaml")},
+	})
+	conf, stop, err := wrapped.GetRESTConfigWithWatch(context.Background(), func(cfg *rest.Config, err error) {
+		t.Error("callback must not be called for non-file targets")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "https://api.example.com", conf.Host)
+	stop()
+}
@@ -0,0 +1,121 @@
+package clientconfig
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// watchPollInterval is the interval in which the kubeconfig file is checked for changes if fsnotify is not available.
+const watchPollInterval = 5 * time.Second
+
+// OnChangeFunc is called by GetRESTConfigWithWatch whenever the watched kubeconfig file has changed.
+// cfg is a new *rest.Config which has been built from the changed file. It is owned by the callback and not modified afterwards.
+// If the changed file could not be loaded, err is not nil and cfg is nil.
+type OnChangeFunc func(cfg *rest.Config, err error)
+
+// StopFunc stops watching the kubeconfig file.
+// It blocks until the watch has been stopped and can safely be called multiple times.
+type StopFunc func()
+
+// GetRESTConfigWithWatch works like GetRESTConfig, but instead of returning a reload func, it watches the kubeconfig file for the "KubeconfigFile" target type.
+// Whenever the content of the file changes, a new *rest.Config is built from it and passed to onChange.
+// The returned *rest.Config is never modified, so it is safe to use it concurrently. Callers which want to use the changed config have to switch to the one passed to onChange.
+// The file is watched via fsnotify. If that is not possible, the file is polled for changes instead.
+// The watch is stopped when the given context is cancelled or the returned StopFunc is called.
+// To avoid onChange being called for partially written files, the file should be replaced atomically, e.g. by writing a temporary file and renaming it.
+// Files which are symlinks into the same directory are supported as well, e.g. kubeconfigs mounted from k8s secrets, which are updated by swapping the '..data' symlink.
+// For all other target types, the config is returned as it would be by GetRESTConfig, onChange is never called and the returned StopFunc is a no-op.
+func (c *Config) GetRESTConfigWithWatch(ctx context.Context, onChange OnChangeFunc) (*rest.Config, StopFunc, error) {
+	if c.KubeconfigFile == nil {
+		cfg, _, err := c.GetRESTConfig()
+		if err != nil {
+			return nil, nil, err
+		}
+		return cfg, func() {}, nil
+	}
+
+	// read the file before loading the config, so that changes between both are detected by the first check
+	path := filepath.Clean(*c.KubeconfigFile)
+	last, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	cfg, _, err := c.GetRESTConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	check := func() {
+		data, err := os.ReadFile(path)
+		if err != nil || bytes.Equal(data, last) {
+			// the file might be in the process of being replaced, it will be checked again on the next event
+			return
+		}
+		last = data
+		newCfg, err := clientcmd.RESTConfigFromKubeConfig(data)
+		if err != nil {
+			newCfg = nil
+		}
+		if onChange != nil {
+			onChange(newCfg, err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	wg := &sync.WaitGroup{}
+	watcher, err := fsnotify.NewWatcher()
+	if err == nil {
+		// watch the directory instead of the file itself, so that replacing the file is detected as well
+		if err = watcher.Add(filepath.Dir(path)); err != nil {
+			_ = watcher.Close()
+		}
+	}
+	if err == nil {
+		wg.Go(func() {
+			defer watcher.Close()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case _, ok := <-watcher.Events:
+					if !ok {
+						return
+					}
+					// check on every event in the directory, because the file might be a symlink whose target is swapped,
+					// e.g. for k8s secret and configmap volumes, which only produce events for the '..data' symlink
+					check()
+				case _, ok := <-watcher.Errors:
+					if !ok {
+						return
+					}
+				}
+			}
+		})
+	} else {
+		// fall back to polling
+		wg.Go(func() {
+			ticker := time.NewTicker(watchPollInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					check()
+				}
+			}
+		})
+	}
+
+	return cfg, func() {
+		cancel()
+		wg.Wait()
+	}, nil
+}
@@ -3,6 +3,7 @@ package clusteraccess
 import (
 	"context"
 	"fmt"
+	"os"
 	"time"
 
 	"sigs.k8s.io/yaml"
@@ -29,44 +30,57 @@ type Label = pairs.Pair[string, string]
 // The namespace holds the serviceaccount and, if namespaceScoped is true, the role and rolebinding.
 // If namespaceScoped is false, clusterrole and clusterrolebinding are used.
 func GetTokenBasedAccess(ctx context.Context, c client.Client, restCfg *rest.Config, name, namespace string, namespaceScoped bool, rolePrefix string, rules []rbacv1.PolicyRule, expectedLabels ...Label) ([]byte, *ServiceAccountToken, error) {
+	kcfg, sat, _, err := ReconcileTokenBasedAccess(ctx, c, restCfg, name, namespace, namespaceScoped, rolePrefix, rules, nil, expectedLabels...)
+	return kcfg, sat, err
+}
+
+// ReconcileTokenBasedAccess works like GetTokenBasedAccess, but takes the previously issued token into account.
+// A new token is only created if no previous token is given or if the renewal time of the given token (as computed by ComputeTokenRenewalTime) has been reached.
+// Otherwise, the existing token is returned unchanged and only the kubeconfig is generated from it.
+// The namespace, serviceaccount, and (cluster)role(binding) are ensured in both cases.
+// The returned duration specifies the time until the returned token should be renewed, which can be used to requeue the reconciled object.
+func ReconcileTokenBasedAccess(ctx context.Context, c client.Client, restCfg *rest.Config, name, namespace string, namespaceScoped bool, rolePrefix string, rules []rbacv1.PolicyRule, existing *ServiceAccountToken, expectedLabels ...Label) ([]byte, *ServiceAccountToken, time.Duration, error) {
 	if namespace == "" {
-		return nil, nil, fmt.Errorf("no namespace provided for ServiceAccount")
+		return nil, nil, 0, fmt.Errorf("no namespace provided for ServiceAccount")
 	}
 
 	_, err := EnsureNamespace(ctx, c, namespace, expectedLabels...)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, 0, err
 	}
 
 	sa, err := EnsureServiceAccount(ctx, c, name, namespace, expectedLabels...)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, 0, err
 	}
 
 	subjects := []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: name, Namespace: namespace}}
 	if namespaceScoped {
 		_, _, err = EnsureRoleAndBinding(ctx, c, rolePrefix+name, namespace, subjects, rules, expectedLabels...)
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, 0, err
 		}
 	} else {
 		_, _, err = EnsureClusterRoleAndBinding(ctx, c, rolePrefix+name, subjects, rules, expectedLabels...)
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, 0, err
 		}
 	}
 
-	sat, err := CreateTokenForServiceAccount(ctx, c, sa, nil)
-	if err != nil {
-		return nil, nil, err
+	sat := existing
+	if sat == nil || !time.Now().Before(ComputeTokenRenewalTime(sat.CreationTimestamp, sat.ExpirationTimestamp)) {
+		sat, err = CreateTokenForServiceAccount(ctx, c, sa, nil)
+		if err != nil {
+			return nil, nil, 0, err
+		}
 	}
 
 	kcfg, err := CreateTokenKubeconfig(name, restCfg.Host, restCfg.CAData, sat.Token)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, 0, err
 	}
 
-	return kcfg, sat, nil
+	return kcfg, sat, max(time.Until(ComputeTokenRenewalTime(sat.CreationTimestamp, sat.ExpirationTimestamp)), 0), nil
 }
 
 // EnsureNamespace ensures that the specified Namespace exists.
@@ -147,8 +161,13 @@ func EnsureClusterRoleAndBinding(ctx context.Context, c client.Client, name stri
 // If it exists, but does not have the expected labels, a ResourceNotManagedError is returned.
 // The ClusterRole is returned.
 func EnsureClusterRole(ctx context.Context, c client.Client, name string, rules []rbacv1.PolicyRule, expectedLabels ...Label) (*rbacv1.ClusterRole, error) {
+	return EnsureClusterRoleWithMerge(ctx, c, name, rules, RuleMergeModeReplace, expectedLabels...)
+}
+
+// EnsureClusterRoleWithMerge works like EnsureClusterRole, but allows to specify how the given rules are combined with the rules of an already existing ClusterRole.
+// See RuleMergeMode for the available options.
+func EnsureClusterRoleWithMerge(ctx context.Context, c client.Client, name string, rules []rbacv1.PolicyRule, mergeMode RuleMergeMode, expectedLabels ...Label) (*rbacv1.ClusterRole, error) {
 	crm := resources.NewClusterRoleMutator(name, rules)
-	crm.MetadataMutator().WithLabels(pairs.PairsToMap(expectedLabels))
 	cr := crm.Empty()
 	found := true
 	if err := c.Get(ctx, client.ObjectKeyFromObject(cr), cr); err != nil {
@@ -161,7 +180,9 @@ func EnsureClusterRole(ctx context.Context, c client.Client, name string, rules
 		if err := FailIfNotManaged(cr, expectedLabels...); err != nil {
 			return nil, err
 		}
+		crm = resources.NewClusterRoleMutator(name, mergeMode.merge(cr.Rules, rules))
 	}
+	crm.MetadataMutator().WithLabels(pairs.PairsToMap(expectedLabels))
 	if err := resources.CreateOrUpdateResource(ctx, c, crm); err != nil {
 		return nil, fmt.Errorf("error creating/updating ClusterRole '%s': %w", cr.Name, err)
 	}
@@ -234,8 +255,13 @@ func EnsureRoleAndBinding(ctx context.Context, c client.Client, name, namespace
 // If it exists, but does not have the expected labels, a ResourceNotManagedError is returned.
 // The Role is returned.
 func EnsureRole(ctx context.Context, c client.Client, name, namespace string, rules []rbacv1.PolicyRule, expectedLabels ...Label) (*rbacv1.Role, error) {
+	return EnsureRoleWithMerge(ctx, c, name, namespace, rules, RuleMergeModeReplace, expectedLabels...)
+}
+
+// EnsureRoleWithMerge works like EnsureRole, but allows to specify how the given rules are combined with the rules of an already existing Role.
+// See RuleMergeMode for the available options.
+func EnsureRoleWithMerge(ctx context.Context, c client.Client, name, namespace string, rules []rbacv1.PolicyRule, mergeMode RuleMergeMode, expectedLabels ...Label) (*rbacv1.Role, error) {
 	rm := resources.NewRoleMutator(name, namespace, rules)
-	rm.MetadataMutator().WithLabels(pairs.PairsToMap(expectedLabels))
 	r := rm.Empty()
 	found := true
 	if err := c.Get(ctx, client.ObjectKeyFromObject(r), r); err != nil {
@@ -248,7 +274,9 @@ func EnsureRole(ctx context.Context, c client.Client, name, namespace string, ru
 		if err := FailIfNotManaged(r, expectedLabels...); err != nil {
 			return nil, err
 		}
+		rm = resources.NewRoleMutator(name, namespace, mergeMode.merge(r.Rules, rules))
 	}
+	rm.MetadataMutator().WithLabels(pairs.PairsToMap(expectedLabels))
 	if err := resources.CreateOrUpdateResource(ctx, c, rm); err != nil {
 		return nil, fmt.Errorf("error creating/updating Role '%s/%s': %w", r.Namespace, r.Name, err)
 	}
@@ -304,11 +332,35 @@ func EnsureRoleBinding(ctx context.Context, c client.Client, name, namespace, ro
 
 // CreateTokenForServiceAccount generates a token for the given ServiceAccount.
 func CreateTokenForServiceAccount(ctx context.Context, c client.Client, sa *corev1.ServiceAccount, desiredDuration *time.Duration) (*ServiceAccountToken, error) {
+	return createToken(ctx, c, sa, newTokenRequest(desiredDuration))
+}
+
+// CreateBoundTokenForSecret generates a token for the given ServiceAccount which is bound to the given Secret.
+// The token becomes invalid when the Secret is deleted.
+// The Secret has to exist and must be in the same namespace as the ServiceAccount.
+func CreateBoundTokenForSecret(ctx context.Context, c client.Client, sa *corev1.ServiceAccount, secret *corev1.Secret, desiredDuration *time.Duration) (*ServiceAccountToken, error) {
+	if secret.Namespace != sa.Namespace {
+		return nil, fmt.Errorf("secret '%s/%s' must be in the same namespace as ServiceAccount '%s/%s'", secret.Namespace, secret.Name, sa.Namespace, sa.Name)
+	}
+	tr := newTokenRequest(desiredDuration)
+	tr.Spec.BoundObjectRef = &authenticationv1.BoundObjectReference{
+		APIVersion: "v1",
+		Kind:       "Secret",
+		Name:       secret.Name,
+		UID:        secret.UID,
+	}
+	return createToken(ctx, c, sa, tr)
+}
+
+func newTokenRequest(desiredDuration *time.Duration) *authenticationv1.TokenRequest {
 	tr := &authenticationv1.TokenRequest{}
 	if desiredDuration != nil {
 		tr.Spec.ExpirationSeconds = new((int64)(desiredDuration.Seconds()))
 	}
+	return tr
+}
 
+func createToken(ctx context.Context, c client.Client, sa *corev1.ServiceAccount, tr *authenticationv1.TokenRequest) (*ServiceAccountToken, error) {
 	sat := &ServiceAccountToken{
 		CreationTimestamp: time.Now(),
 	}
@@ -362,6 +414,41 @@ func CreateTokenKubeconfig(user, host string, caData []byte, token string) ([]by
 	return kcfgBytes, nil
 }
 
+// CreateClientCertKubeconfig generates a kubeconfig which uses the given client certificate and key for authentication.
+// The 'user' arg is used as key for the auth configuration and can be chosen freely.
+func CreateClientCertKubeconfig(user, host string, caData, certData, keyData []byte) ([]byte, error) {
+	id := "cluster"
+	kcfg := clientcmdapi.Config{
+		APIVersion: "v1",
+		Kind:       "Config",
+		Clusters: map[string]*clientcmdapi.Cluster{
+			id: {
+				Server:                   host,
+				CertificateAuthorityData: caData,
+			},
+		},
+		Contexts: map[string]*clientcmdapi.Context{
+			id: {
+				Cluster:  id,
+				AuthInfo: user,
+			},
+		},
+		CurrentContext: id,
+		AuthInfos: map[string]*clientcmdapi.AuthInfo{
+			user: {
+				ClientCertificateData: certData,
+				ClientKeyData:         keyData,
+			},
+		},
+	}
+
+	kcfgBytes, err := clientcmd.Write(kcfg)
+	if err != nil {
+		return nil, fmt.Errorf("error converting generated kubeconfig into yaml: %w", err)
+	}
+	return kcfgBytes, nil
+}
+
 // ComputeTokenRenewalTime computes the time for the renewal of a token, given its creation and expiration time.
 // Returns the zero time if either of the given times is zero.
 // The returned time is when 80% of the validity duration is reached.
@@ -386,6 +473,30 @@ func ComputeTokenRenewalTimeWithRatio(creationTime, expirationTime time.Time, ra
 	return renewalAt
 }
 
+// EarliestTokenRenewal returns the duration from now until the earliest renewal time of the given tokens, computed via ComputeTokenRenewalTimeWithRatio.
+// Nil tokens and tokens without expiration timestamp are ignored. If no token remains, 0 is returned.
+// If the renewal time of a token has already passed, 0 is returned as well, so overdue tokens should be renewed before calling this function.
+// The result is suitable to be used as 'RequeueAfter' in a reconcile result.
+func EarliestTokenRenewal(now time.Time, ratio float64, tokens ...*ServiceAccountToken) time.Duration {
+	var earliest time.Time
+	for _, sat := range tokens {
+		if sat == nil {
+			continue
+		}
+		renewalAt := ComputeTokenRenewalTimeWithRatio(sat.CreationTimestamp, sat.ExpirationTimestamp, ratio)
+		if renewalAt.IsZero() {
+			continue
+		}
+		if earliest.IsZero() || renewalAt.Before(earliest) {
+			earliest = renewalAt
+		}
+	}
+	if earliest.IsZero() {
+		return 0
+	}
+	return max(earliest.Sub(now), 0)
+}
+
 // CreateOIDCKubeconfig creates a kubeconfig that uses the oidc-login plugin for authentication.
 // The 'user' arg is used as key for the auth configuration and can be chosen freely.
 // Note that this kubeconfig is meant for human users, controllers can usually not execute 'kubectl oidc-login get-token'.
@@ -611,8 +722,29 @@ func WriteOIDCConfigFromRESTConfig(restConfig *rest.Config) ([]byte, error) {
 	return configMarshaled, nil
 }
 
+// ResolveCAData returns the CA data of the given RESTConfig.
+// If the config contains inline CA data, it is returned. Otherwise, if a CA file is set, its content is read and returned.
+// Returns nil if neither is set.
+func ResolveCAData(cfg *rest.Config) ([]byte, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	if len(cfg.CAData) > 0 {
+		return cfg.CAData, nil
+	}
+	if cfg.CAFile != "" {
+		data, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading CA file '%s': %w", cfg.CAFile, err)
+		}
+		return data, nil
+	}
+	return nil, nil
+}
+
 // WriteKubeconfigFromRESTConfig converts the RESTConfig to a kubeconfig format.
 // Supported authentication methods are Bearer Token, Username/Password and Client Certificate.
+// The CA data is always embedded into the kubeconfig, if the RESTConfig references a CA file, it is read from disk.
 func WriteKubeconfigFromRESTConfig(restConfig *rest.Config) ([]byte, error) {
 	var authInfo *clientcmdapi.AuthInfo
 
@@ -661,6 +793,11 @@ func WriteKubeconfigFromRESTConfig(restConfig *rest.Config) ([]byte, error) {
 		}
 	}
 
+	caData, err := ResolveCAData(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
 	server := restConfig.Host
 	if restConfig.APIPath != "" {
 		server = fmt.Sprint(server, "/", restConfig.APIPath)
@@ -677,7 +814,7 @@ func WriteKubeconfigFromRESTConfig(restConfig *rest.Config) ([]byte, error) {
 		Clusters: map[string]*clientcmdapi.Cluster{
 			id: {
 				Server:                   server,
-				CertificateAuthorityData: restConfig.CAData,
+				CertificateAuthorityData: caData,
 			},
 		},
 		AuthInfos: map[string]*clientcmdapi.AuthInfo{
@@ -2,17 +2,32 @@ package clusteraccess_test
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
 	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 	"sigs.k8s.io/yaml"
 
+	authenticationv1 "k8s.io/api/authentication/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
@@ -54,6 +69,17 @@ var _ = Describe("ClusterAccess", func() {
 			Expect(ns.Labels).To(BeEmpty())
 			_, err := clusteraccess.EnsureNamespace(env.Ctx, env.Client(), ns.Name, testLabelsList...)
 			Expect(err).To(HaveOccurred())
+			Expect(clusteraccess.IsResourceNotManagedError(err)).To(BeTrue())
+			Expect(clusteraccess.IsResourceNotManagedError(fmt.Errorf("wrapped: %w", err))).To(BeTrue())
+			var rnmErr *clusteraccess.ResourceNotManagedError
+			Expect(errors.As(fmt.Errorf("wrapped: %w", err), &rnmErr)).To(BeTrue())
+			Expect(rnmErr.Obj.GetName()).To(Equal(ns.Name))
+		})
+
+		It("should not identify unrelated errors as ResourceNotManagedError", func() {
+			Expect(clusteraccess.IsResourceNotManagedError(nil)).To(BeFalse())
+			Expect(clusteraccess.IsResourceNotManagedError(fmt.Errorf("some other error"))).To(BeFalse())
+			Expect(clusteraccess.IsResourceNotManagedError(apierrors.NewNotFound(corev1.Resource("namespaces"), "testns"))).To(BeFalse())
 		})
 
 		It("should not fail if the namespace exists and has the expected labels", func() {
@@ -220,6 +246,80 @@ var _ = Describe("ClusterAccess", func() {
 			Expect(cr.Rules).To(BeEquivalentTo(expectedRules()))
 		})
 
+		Context("with merge mode", func() {
+
+			existingRule := rbacv1.PolicyRule{
+				Verbs:     []string{"get", "list"},
+				APIGroups: []string{""},
+				Resources: []string{"secrets"},
+			}
+			newRule := rbacv1.PolicyRule{
+				Verbs:     []string{"create"},
+				APIGroups: []string{"apps"},
+				Resources: []string{"deployments"},
+			}
+
+			It("should add rules to an existing role in Union mode", func() {
+				r := &rbacv1.Role{}
+				r.SetName("testr")
+				r.SetNamespace("testns")
+				r.SetLabels(testLabelsMap)
+				r.Rules = []rbacv1.PolicyRule{existingRule}
+				env := testutils.NewEnvironmentBuilder().WithFakeClient(nil).WithInitObjects(r).Build()
+				_, err := clusteraccess.EnsureRoleWithMerge(env.Ctx, env.Client(), r.Name, r.Namespace, []rbacv1.PolicyRule{newRule, existingRule}, clusteraccess.RuleMergeModeUnion, testLabelsList...)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(env.Client().Get(env.Ctx, client.ObjectKeyFromObject(r), r)).To(Succeed())
+				Expect(r.Rules).To(ConsistOf(existingRule, newRule))
+			})
+
+			It("should replace the rules of an existing role in Replace mode", func() {
+				r := &rbacv1.Role{}
+				r.SetName("testr")
+				r.SetNamespace("testns")
+				r.SetLabels(testLabelsMap)
+				r.Rules = []rbacv1.PolicyRule{existingRule}
+				env := testutils.NewEnvironmentBuilder().WithFakeClient(nil).WithInitObjects(r).Build()
+				_, err := clusteraccess.EnsureRoleWithMerge(env.Ctx, env.Client(), r.Name, r.Namespace, []rbacv1.PolicyRule{newRule}, clusteraccess.RuleMergeModeReplace, testLabelsList...)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(env.Client().Get(env.Ctx, client.ObjectKeyFromObject(r), r)).To(Succeed())
+				Expect(r.Rules).To(ConsistOf(newRule))
+			})
+
+			It("should create a role with the given rules in Union mode if it does not exist", func() {
+				env := testutils.NewEnvironmentBuilder().WithFakeClient(nil).Build()
+				r, err := clusteraccess.EnsureRoleWithMerge(env.Ctx, env.Client(), "testr", "testns", []rbacv1.PolicyRule{newRule}, clusteraccess.RuleMergeModeUnion, testLabelsList...)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(env.Client().Get(env.Ctx, client.ObjectKeyFromObject(r), r)).To(Succeed())
+				Expect(r.Labels).To(BeEquivalentTo(testLabelsMap))
+				Expect(r.Rules).To(ConsistOf(newRule))
+			})
+
+			It("should add rules to an existing clusterrole in Union mode", func() {
+				cr := &rbacv1.ClusterRole{}
+				cr.SetName("testcr")
+				cr.SetLabels(testLabelsMap)
+				cr.Rules = []rbacv1.PolicyRule{existingRule}
+				env := testutils.NewEnvironmentBuilder().WithFakeClient(nil).WithInitObjects(cr).Build()
+				_, err := clusteraccess.EnsureClusterRoleWithMerge(env.Ctx, env.Client(), cr.Name, []rbacv1.PolicyRule{newRule}, clusteraccess.RuleMergeModeUnion, testLabelsList...)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(env.Client().Get(env.Ctx, client.ObjectKeyFromObject(cr), cr)).To(Succeed())
+				Expect(cr.Rules).To(ConsistOf(existingRule, newRule))
+			})
+
+			It("should replace the rules of an existing clusterrole in Replace mode", func() {
+				cr := &rbacv1.ClusterRole{}
+				cr.SetName("testcr")
+				cr.SetLabels(testLabelsMap)
+				cr.Rules = []rbacv1.PolicyRule{existingRule}
+				env := testutils.NewEnvironmentBuilder().WithFakeClient(nil).WithInitObjects(cr).Build()
+				_, err := clusteraccess.EnsureClusterRoleWithMerge(env.Ctx, env.Client(), cr.Name, []rbacv1.PolicyRule{newRule}, clusteraccess.RuleMergeModeReplace, testLabelsList...)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(env.Client().Get(env.Ctx, client.ObjectKeyFromObject(cr), cr)).To(Succeed())
+				Expect(cr.Rules).To(ConsistOf(newRule))
+			})
+
+		})
+
 	})
 
 	Context("EnsureRoleBinding and EnsureClusterRoleBinding", func() {
@@ -530,6 +630,523 @@ var _ = Describe("ClusterAccess", func() {
 
 	})
 
+	Context("RoleDrifted and ClusterRoleDrifted", func() {
+
+		desiredRules := func() []rbacv1.PolicyRule {
+			return []rbacv1.PolicyRule{
+				{
+					Verbs:     []string{"get", "list"},
+					APIGroups: []string{"apps"},
+					Resources: []string{"deployments"},
+				},
+				{
+					Verbs:     []string{"*"},
+					APIGroups: []string{""},
+					Resources: []string{"namespaces"},
+				},
+			}
+		}
+
+		// reorderedRules contains the same permissions as desiredRules, but in a different order and with duplicates
+		reorderedRules := func() []rbacv1.PolicyRule {
+			return []rbacv1.PolicyRule{
+				{
+					Verbs:     []string{"*"},
+					APIGroups: []string{""},
+					Resources: []string{"namespaces"},
+				},
+				{
+					Verbs:     []string{"list", "get", "list"},
+					APIGroups: []string{"apps"},
+					Resources: []string{"deployments"},
+				},
+				{
+					Verbs:     []string{"*"},
+					APIGroups: []string{""},
+					Resources: []string{"namespaces"},
+				},
+			}
+		}
+
+		It("should not detect drift if the role's rules match the desired ones", func() {
+			r := &rbacv1.Role{}
+			r.SetName("testr")
+			r.SetNamespace("testns")
+			r.Rules = reorderedRules()
+			env := testutils.NewEnvironmentBuilder().WithFakeClient(nil).WithInitObjects(r).Build()
+			drifted, err := clusteraccess.RoleDrifted(env.Ctx, env.Client(), r.Name, r.Namespace, desiredRules())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(drifted).To(BeFalse())
+		})
+
+		It("should detect drift if the role's rules have been tampered with", func() {
+			r := &rbacv1.Role{}
+			r.SetName("testr")
+			r.SetNamespace("testns")
+			r.Rules = desiredRules()
+			r.Rules[0].Verbs = append(r.Rules[0].Verbs, "delete")
+			env := testutils.NewEnvironmentBuilder().WithFakeClient(nil).WithInitObjects(r).Build()
+			drifted, err := clusteraccess.RoleDrifted(env.Ctx, env.Client(), r.Name, r.Namespace, desiredRules())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(drifted).To(BeTrue())
+		})
+
+		It("should detect drift if the role does not exist", func() {
+			env := testutils.NewEnvironmentBuilder().WithFakeClient(nil).Build()
+			drifted, err := clusteraccess.RoleDrifted(env.Ctx, env.Client(), "testr", "testns", desiredRules())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(drifted).To(BeTrue())
+		})
+
+		It("should not detect drift if the clusterrole's rules match the desired ones", func() {
+			cr := &rbacv1.ClusterRole{}
+			cr.SetName("testcr")
+			cr.Rules = reorderedRules()
+			env := testutils.NewEnvironmentBuilder().WithFakeClient(nil).WithInitObjects(cr).Build()
+			drifted, err := clusteraccess.ClusterRoleDrifted(env.Ctx, env.Client(), cr.Name, desiredRules())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(drifted).To(BeFalse())
+		})
+
+		It("should detect drift if the clusterrole's rules have been tampered with", func() {
+			cr := &rbacv1.ClusterRole{}
+			cr.SetName("testcr")
+			cr.Rules = desiredRules()[:1]
+			env := testutils.NewEnvironmentBuilder().WithFakeClient(nil).WithInitObjects(cr).Build()
+			drifted, err := clusteraccess.ClusterRoleDrifted(env.Ctx, env.Client(), cr.Name, desiredRules())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(drifted).To(BeTrue())
+		})
+
+	})
+
+	Context("UnionRules", func() {
+
+		It("should combine overlapping rule sets into a minimal set", func() {
+			reconcilerA := []rbacv1.PolicyRule{
+				{
+					Verbs:     []string{"get", "list"},
+					APIGroups: []string{""},
+					Resources: []string{"secrets"},
+				},
+				{
+					Verbs:     []string{"get"},
+					APIGroups: []string{"apps"},
+					Resources: []string{"deployments"},
+				},
+			}
+			reconcilerB := []rbacv1.PolicyRule{
+				{
+					Verbs:     []string{"watch", "get"},
+					APIGroups: []string{""},
+					Resources: []string{"secrets"},
+				},
+				{
+					Verbs:         []string{"get"},
+					APIGroups:     []string{""},
+					Resources:     []string{"secrets"},
+					ResourceNames: []string{"my-secret"},
+				},
+				{
+					Verbs:     []string{"*"},
+					APIGroups: []string{"apps"},
+					Resources: []string{"deployments", "statefulsets"},
+				},
+			}
+			reconcilerC := []rbacv1.PolicyRule{
+				{
+					Verbs:           []string{"get"},
+					NonResourceURLs: []string{"/healthz"},
+				},
+				{
+					Verbs:     []string{"get", "list"},
+					APIGroups: []string{""},
+					Resources: []string{"secrets"},
+				},
+			}
+			Expect(clusteraccess.UnionRules(reconcilerA, reconcilerB, reconcilerC)).To(Equal([]rbacv1.PolicyRule{
+				{
+					Verbs:           []string{"get"},
+					NonResourceURLs: []string{"/healthz"},
+				},
+				{
+					Verbs:     []string{"get", "list", "watch"},
+					APIGroups: []string{""},
+					Resources: []string{"secrets"},
+				},
+				{
+					Verbs:     []string{"*"},
+					APIGroups: []string{"apps"},
+					Resources: []string{"deployments", "statefulsets"},
+				},
+			}))
+		})
+
+		It("should keep rules which are not completely covered by other rules", func() {
+			rules := []rbacv1.PolicyRule{
+				{
+					Verbs:         []string{"delete"},
+					APIGroups:     []string{""},
+					Resources:     []string{"secrets"},
+					ResourceNames: []string{"my-secret"},
+				},
+				{
+					Verbs:     []string{"get"},
+					APIGroups: []string{""},
+					Resources: []string{"secrets"},
+				},
+			}
+			Expect(clusteraccess.UnionRules(rules)).To(Equal(clusteraccess.NormalizeRules(rules)))
+			Expect(clusteraccess.UnionRules()).To(BeEmpty())
+		})
+
+	})
+
+	Context("ReconcileTokenBasedAccess", func() {
+
+		var tokenRequests int
+		var env *testutils.Environment
+		var c client.Client
+		var restCfg *rest.Config
+		rules := []rbacv1.PolicyRule{
+			{
+				Verbs:     []string{"get"},
+				APIGroups: []string{""},
+				Resources: []string{"configmaps"},
+			},
+		}
+
+		BeforeEach(func() {
+			tokenRequests = 0
+			env = testutils.NewEnvironmentBuilder().WithFakeClient(nil).Build()
+			c = interceptor.NewClient(env.Client().(client.WithWatch), interceptor.Funcs{
+				SubResourceCreate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+					if subResourceName == "token" {
+						tokenRequests++
+					}
+					return c.SubResource(subResourceName).Create(ctx, obj, subResource, opts...)
+				},
+			})
+			restCfg = &rest.Config{Host: "https://api.example.com"}
+		})
+
+		It("should create a new token if no existing token is given", func() {
+			kcfg, sat, renewIn, err := clusteraccess.ReconcileTokenBasedAccess(env.Ctx, c, restCfg, "testsa", "testns", true, "prefix-", rules, nil, testLabelsList...)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tokenRequests).To(Equal(1))
+			Expect(sat).ToNot(BeNil())
+			Expect(sat.Token).ToNot(BeEmpty())
+			Expect(renewIn).To(BeNumerically(">", 0))
+			cfg, err := clientcmd.RESTConfigFromKubeConfig(kcfg)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cfg.BearerToken).To(Equal(sat.Token))
+
+			r := &rbacv1.Role{}
+			Expect(c.Get(env.Ctx, client.ObjectKey{Name: "prefix-testsa", Namespace: "testns"}, r)).To(Succeed())
+			Expect(r.Rules).To(Equal(rules))
+		})
+
+		It("should not reissue a token that is not due for renewal", func() {
+			existing := &clusteraccess.ServiceAccountToken{
+				Token:               "existing-token",
+				CreationTimestamp:   time.Now().Add(-time.Hour),
+				ExpirationTimestamp: time.Now().Add(9 * time.Hour),
+			}
+			kcfg, sat, renewIn, err := clusteraccess.ReconcileTokenBasedAccess(env.Ctx, c, restCfg, "testsa", "testns", false, "", rules, existing, testLabelsList...)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tokenRequests).To(BeZero())
+			Expect(sat).To(Equal(existing))
+			Expect(renewIn).To(BeNumerically("~", 7*time.Hour, time.Minute))
+			cfg, err := clientcmd.RESTConfigFromKubeConfig(kcfg)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cfg.BearerToken).To(Equal("existing-token"))
+		})
+
+		It("should create a new token if the existing one is due for renewal", func() {
+			existing := &clusteraccess.ServiceAccountToken{
+				Token:               "existing-token",
+				CreationTimestamp:   time.Now().Add(-9 * time.Hour),
+				ExpirationTimestamp: time.Now().Add(time.Hour),
+			}
+			kcfg, sat, renewIn, err := clusteraccess.ReconcileTokenBasedAccess(env.Ctx, c, restCfg, "testsa", "testns", false, "", rules, existing, testLabelsList...)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tokenRequests).To(Equal(1))
+			Expect(sat.Token).ToNot(Equal("existing-token"))
+			Expect(renewIn).To(BeNumerically(">", 0))
+			cfg, err := clientcmd.RESTConfigFromKubeConfig(kcfg)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cfg.BearerToken).To(Equal(sat.Token))
+		})
+
+	})
+
+	Context("GetTokenBasedAccessDryRun", func() {
+
+		rules := []rbacv1.PolicyRule{
+			{
+				Verbs:     []string{"get"},
+				APIGroups: []string{""},
+				Resources: []string{"configmaps"},
+			},
+		}
+
+		gvksOf := func(c client.Client, objs []client.Object) []string {
+			res := make([]string, 0, len(objs))
+			for _, obj := range objs {
+				gvk, err := c.GroupVersionKindFor(obj)
+				Expect(err).ToNot(HaveOccurred())
+				res = append(res, gvk.String())
+			}
+			return res
+		}
+
+		It("should return the objects that would be created without persisting them (namespace-scoped)", func() {
+			env := testutils.NewEnvironmentBuilder().WithFakeClient(nil).Build()
+			objs, err := clusteraccess.GetTokenBasedAccessDryRun(env.Ctx, env.Client(), "testsa", "testns", true, "prefix-", rules, testLabelsList...)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(gvksOf(env.Client(), objs)).To(Equal([]string{
+				corev1.SchemeGroupVersion.WithKind("Namespace").String(),
+				corev1.SchemeGroupVersion.WithKind("ServiceAccount").String(),
+				rbacv1.SchemeGroupVersion.WithKind("Role").String(),
+				rbacv1.SchemeGroupVersion.WithKind("RoleBinding").String(),
+			}))
+			for _, obj := range objs {
+				Expect(obj.GetLabels()).To(Equal(testLabelsMap))
+				err := env.Client().Get(env.Ctx, client.ObjectKeyFromObject(obj), obj)
+				Expect(apierrors.IsNotFound(err)).To(BeTrue(), "object %s should not have been persisted", client.ObjectKeyFromObject(obj).String())
+			}
+		})
+
+		It("should return the objects that would be created without persisting them (cluster-scoped)", func() {
+			env := testutils.NewEnvironmentBuilder().WithFakeClient(nil).Build()
+			objs, err := clusteraccess.GetTokenBasedAccessDryRun(env.Ctx, env.Client(), "testsa", "testns", false, "prefix-", rules, testLabelsList...)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(gvksOf(env.Client(), objs)).To(Equal([]string{
+				corev1.SchemeGroupVersion.WithKind("Namespace").String(),
+				corev1.SchemeGroupVersion.WithKind("ServiceAccount").String(),
+				rbacv1.SchemeGroupVersion.WithKind("ClusterRole").String(),
+				rbacv1.SchemeGroupVersion.WithKind("ClusterRoleBinding").String(),
+			}))
+			for _, obj := range objs {
+				err := env.Client().Get(env.Ctx, client.ObjectKeyFromObject(obj), obj)
+				Expect(apierrors.IsNotFound(err)).To(BeTrue(), "object %s should not have been persisted", client.ObjectKeyFromObject(obj).String())
+			}
+		})
+
+		It("should not send requests for objects in a namespace that would only be created", func() {
+			env := testutils.NewEnvironmentBuilder().WithFakeClient(nil).Build()
+			namespacedRequests := 0
+			// simulate the NamespaceLifecycle admission plugin, which rejects objects in non-existing namespaces
+			c := interceptor.NewClient(env.Client().(client.WithWatch), interceptor.Funcs{
+				Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+					if key.Namespace != "" {
+						namespacedRequests++
+					}
+					return c.Get(ctx, key, obj, opts...)
+				},
+				Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+					if ns := obj.GetNamespace(); ns != "" {
+						namespacedRequests++
+						if err := c.Get(ctx, client.ObjectKey{Name: ns}, &corev1.Namespace{}); err != nil {
+							return err
+						}
+					}
+					return c.Create(ctx, obj, opts...)
+				},
+			})
+			objs, err := clusteraccess.GetTokenBasedAccessDryRun(env.Ctx, c, "testsa", "testns", true, "prefix-", rules, testLabelsList...)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(namespacedRequests).To(BeZero())
+			Expect(gvksOf(env.Client(), objs)).To(Equal([]string{
+				corev1.SchemeGroupVersion.WithKind("Namespace").String(),
+				corev1.SchemeGroupVersion.WithKind("ServiceAccount").String(),
+				rbacv1.SchemeGroupVersion.WithKind("Role").String(),
+				rbacv1.SchemeGroupVersion.WithKind("RoleBinding").String(),
+			}))
+			for _, obj := range objs {
+				Expect(obj.GetLabels()).To(Equal(testLabelsMap))
+			}
+			Expect(objs[2].(*rbacv1.Role).Rules).To(Equal(rules))
+			Expect(objs[3].(*rbacv1.RoleBinding).Subjects).To(ConsistOf(rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "testsa", Namespace: "testns"}))
+		})
+
+		It("should only return objects that would be changed and not modify existing ones", func() {
+			env := testutils.NewEnvironmentBuilder().WithFakeClient(nil).Build()
+			_, _, err := clusteraccess.GetTokenBasedAccess(env.Ctx, env.Client(), &rest.Config{Host: "https://api.example.com"}, "testsa", "testns", true, "prefix-", rules, testLabelsList...)
+			Expect(err).ToNot(HaveOccurred())
+
+			objs, err := clusteraccess.GetTokenBasedAccessDryRun(env.Ctx, env.Client(), "testsa", "testns", true, "prefix-", rules, testLabelsList...)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(objs).To(BeEmpty())
+
+			newRules := append([]rbacv1.PolicyRule{}, rules...)
+			newRules = append(newRules, rbacv1.PolicyRule{
+				Verbs:     []string{"list"},
+				APIGroups: []string{""},
+				Resources: []string{"secrets"},
+			})
+			objs, err = clusteraccess.GetTokenBasedAccessDryRun(env.Ctx, env.Client(), "testsa", "testns", true, "prefix-", newRules, testLabelsList...)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(gvksOf(env.Client(), objs)).To(Equal([]string{rbacv1.SchemeGroupVersion.WithKind("Role").String()}))
+			Expect(objs[0].(*rbacv1.Role).Rules).To(Equal(newRules))
+			r := &rbacv1.Role{}
+			Expect(env.Client().Get(env.Ctx, client.ObjectKey{Name: "prefix-testsa", Namespace: "testns"}, r)).To(Succeed())
+			Expect(r.Rules).To(Equal(rules))
+		})
+
+		It("should fail if an existing object is not managed", func() {
+			ns := &corev1.Namespace{}
+			ns.SetName("testns")
+			env := testutils.NewEnvironmentBuilder().WithFakeClient(nil).WithInitObjects(ns).Build()
+			_, err := clusteraccess.GetTokenBasedAccessDryRun(env.Ctx, env.Client(), "testsa", "testns", true, "prefix-", rules, testLabelsList...)
+			Expect(err).To(HaveOccurred())
+			Expect(clusteraccess.IsResourceNotManagedError(err)).To(BeTrue())
+		})
+
+	})
+
+	Context("CreateBoundTokenForSecret", func() {
+
+		It("should create a token that is bound to the given secret", func() {
+			sa := &corev1.ServiceAccount{}
+			sa.SetName("testsa")
+			sa.SetNamespace("testns")
+			secret := &corev1.Secret{}
+			secret.SetName("kubeconfig")
+			secret.SetNamespace("testns")
+			env := testutils.NewEnvironmentBuilder().WithFakeClient(nil).WithInitObjects(sa, secret).WithUIDs().Build()
+			Expect(env.Client().Get(env.Ctx, client.ObjectKeyFromObject(secret), secret)).To(Succeed())
+			Expect(secret.UID).ToNot(BeEmpty())
+
+			var tr *authenticationv1.TokenRequest
+			c := interceptor.NewClient(env.Client().(client.WithWatch), interceptor.Funcs{
+				SubResourceCreate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+					tr, _ = subResource.(*authenticationv1.TokenRequest)
+					return c.SubResource(subResourceName).Create(ctx, obj, subResource, opts...)
+				},
+			})
+			duration := time.Hour
+			sat, err := clusteraccess.CreateBoundTokenForSecret(env.Ctx, c, sa, secret, &duration)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sat.Token).ToNot(BeEmpty())
+			Expect(tr).ToNot(BeNil())
+			Expect(tr.Spec.ExpirationSeconds).To(PointTo(BeEquivalentTo(3600)))
+			Expect(tr.Spec.BoundObjectRef).To(PointTo(Equal(authenticationv1.BoundObjectReference{
+				APIVersion: "v1",
+				Kind:       "Secret",
+				Name:       secret.Name,
+				UID:        secret.UID,
+			})))
+		})
+
+		It("should fail if the secret is in a different namespace", func() {
+			sa := &corev1.ServiceAccount{}
+			sa.SetName("testsa")
+			sa.SetNamespace("testns")
+			secret := &corev1.Secret{}
+			secret.SetName("kubeconfig")
+			secret.SetNamespace("otherns")
+			env := testutils.NewEnvironmentBuilder().WithFakeClient(nil).WithInitObjects(sa, secret).Build()
+			_, err := clusteraccess.CreateBoundTokenForSecret(env.Ctx, env.Client(), sa, secret, nil)
+			Expect(err).To(HaveOccurred())
+		})
+
+	})
+
+	Context("GetOrCreateSAToken", func() {
+
+		var sa *corev1.ServiceAccount
+		tokenRequestUnavailable := interceptor.Funcs{
+			SubResourceCreate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+				if subResourceName == "token" {
+					return apierrors.NewNotFound(corev1.Resource("serviceaccounts/token"), obj.GetName())
+				}
+				return c.SubResource(subResourceName).Create(ctx, obj, subResource, opts...)
+			},
+		}
+
+		BeforeEach(func() {
+			sa = &corev1.ServiceAccount{}
+			sa.SetName("testsa")
+			sa.SetNamespace("testns")
+		})
+
+		It("should use the TokenRequest API if available", func() {
+			env := testutils.NewEnvironmentBuilder().WithFakeClient(nil).WithInitObjects(sa).Build()
+			duration := time.Hour
+			sat, err := clusteraccess.GetOrCreateSAToken(env.Ctx, env.Client(), sa, &duration)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sat.Token).ToNot(BeEmpty())
+			Expect(sat.ExpirationTimestamp).ToNot(BeZero())
+		})
+
+		It("should read the token from a referenced secret if the TokenRequest API is not available", func() {
+			secret := &corev1.Secret{}
+			secret.SetName("testsa-token-abcde")
+			secret.SetNamespace("testns")
+			secret.SetAnnotations(map[string]string{corev1.ServiceAccountNameKey: "testsa"})
+			secret.Type = corev1.SecretTypeServiceAccountToken
+			secret.Data = map[string][]byte{corev1.ServiceAccountTokenKey: []byte("legacy-token")}
+			sa.Secrets = []corev1.ObjectReference{{Name: secret.Name}}
+			env := testutils.NewEnvironmentBuilder().WithFakeClient(nil).WithInitObjects(sa, secret).Build()
+			c := interceptor.NewClient(env.Client().(client.WithWatch), tokenRequestUnavailable)
+
+			sat, err := clusteraccess.GetOrCreateSAToken(env.Ctx, c, sa, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sat.Token).To(Equal("legacy-token"))
+			Expect(sat.ExpirationTimestamp).To(BeZero())
+		})
+
+		It("should not modify the passed in ServiceAccount", func() {
+			secret := &corev1.Secret{}
+			secret.SetName("testsa-token-abcde")
+			secret.SetNamespace("testns")
+			secret.SetAnnotations(map[string]string{corev1.ServiceAccountNameKey: "testsa"})
+			secret.Type = corev1.SecretTypeServiceAccountToken
+			secret.Data = map[string][]byte{corev1.ServiceAccountTokenKey: []byte("legacy-token")}
+			sa.Secrets = []corev1.ObjectReference{{Name: secret.Name}}
+			env := testutils.NewEnvironmentBuilder().WithFakeClient(nil).WithInitObjects(sa, secret).Build()
+			c := interceptor.NewClient(env.Client().(client.WithWatch), tokenRequestUnavailable)
+
+			callerSA := &corev1.ServiceAccount{}
+			callerSA.SetName("testsa")
+			callerSA.SetNamespace("testns")
+			callerSA.SetLabels(map[string]string{"foo": "bar"})
+			expected := callerSA.DeepCopy()
+			sat, err := clusteraccess.GetOrCreateSAToken(env.Ctx, c, callerSA, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sat.Token).To(Equal("legacy-token"))
+			Expect(callerSA).To(Equal(expected))
+		})
+
+		It("should create a token secret if none exists and the TokenRequest API is not available", func() {
+			env := testutils.NewEnvironmentBuilder().WithFakeClient(nil).WithInitObjects(sa).Build()
+			c := interceptor.NewClient(env.Client().(client.WithWatch), tokenRequestUnavailable)
+
+			_, err := clusteraccess.GetOrCreateSAToken(env.Ctx, c, sa, nil)
+			Expect(err).To(MatchError(clusteraccess.ErrTokenSecretNotPopulated))
+			secret := &corev1.Secret{}
+			Expect(env.Client().Get(env.Ctx, client.ObjectKey{Name: "testsa-token", Namespace: "testns"}, secret)).To(Succeed())
+			Expect(secret.Type).To(Equal(corev1.SecretTypeServiceAccountToken))
+			Expect(secret.Annotations).To(HaveKeyWithValue(corev1.ServiceAccountNameKey, "testsa"))
+
+			By("returning the token once the secret has been populated")
+			secret.Data = map[string][]byte{corev1.ServiceAccountTokenKey: []byte("populated-token")}
+			Expect(env.Client().Update(env.Ctx, secret)).To(Succeed())
+			sat, err := clusteraccess.GetOrCreateSAToken(env.Ctx, c, sa, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sat.Token).To(Equal("populated-token"))
+		})
+
+		It("should fail if the ServiceAccount does not exist", func() {
+			env := testutils.NewEnvironmentBuilder().WithFakeClient(nil).Build()
+			c := interceptor.NewClient(env.Client().(client.WithWatch), tokenRequestUnavailable)
+			_, err := clusteraccess.GetOrCreateSAToken(env.Ctx, c, sa, nil)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+
+	})
+
 	Context("Marshal RESTConfig", func() {
 		readRESTConfigFromKubeconfig := func(kubeconfig string) *rest.Config {
 			data, err := os.ReadFile(fmt.Sprint("./testdata/kubeconfig/", kubeconfig))
@@ -596,6 +1213,199 @@ var _ = Describe("ClusterAccess", func() {
 			Expect(config.TLSClientConfig.CertData).ToNot(BeEmpty())
 			Expect(config.TLSClientConfig.KeyData).ToNot(BeEmpty())
 		})
+
+		It("should embed the CA data if the RESTConfig references a CA file", func() {
+			restConfig := readRESTConfigFromKubeconfig("kubeconfig-token.yaml")
+			caData := restConfig.CAData
+			caFile := filepath.Join(GinkgoT().TempDir(), "ca.crt")
+			Expect(os.WriteFile(caFile, caData, 0o600)).To(Succeed())
+			restConfig.CAData = nil
+			restConfig.CAFile = caFile
+
+			kubeconfigRaw, err := clusteraccess.WriteKubeconfigFromRESTConfig(restConfig)
+			Expect(err).ToNot(HaveOccurred())
+			config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfigRaw)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(config.TLSClientConfig.CAData).To(Equal(caData))
+			Expect(config.TLSClientConfig.CAFile).To(BeEmpty())
+		})
+	})
+
+	Context("ResolveCAData", func() {
+
+		It("should return the inline CA data", func() {
+			caData, err := clusteraccess.ResolveCAData(&rest.Config{TLSClientConfig: rest.TLSClientConfig{CAData: []byte("inline"), CAFile: "/does/not/exist"}})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(caData).To(Equal([]byte("inline")))
+		})
+
+		It("should read the CA file if no inline CA data is set", func() {
+			caFile := filepath.Join(GinkgoT().TempDir(), "ca.crt")
+			Expect(os.WriteFile(caFile, []byte("from-file"), 0o600)).To(Succeed())
+			caData, err := clusteraccess.ResolveCAData(&rest.Config{TLSClientConfig: rest.TLSClientConfig{CAFile: caFile}})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(caData).To(Equal([]byte("from-file")))
+
+			_, err = clusteraccess.ResolveCAData(&rest.Config{TLSClientConfig: rest.TLSClientConfig{CAFile: filepath.Join(GinkgoT().TempDir(), "missing.crt")}})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should return nil if neither CA data nor CA file are set", func() {
+			caData, err := clusteraccess.ResolveCAData(&rest.Config{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(caData).To(BeNil())
+		})
+
+	})
+
+	Context("EarliestTokenRenewal", func() {
+
+		now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+		It("should return the duration until the earliest renewal of multiple tokens", func() {
+			tokens := []*clusteraccess.ServiceAccountToken{
+				{CreationTimestamp: now.Add(-time.Hour), ExpirationTimestamp: now.Add(9 * time.Hour)},  // renewal at now+7h
+				{CreationTimestamp: now, ExpirationTimestamp: now.Add(time.Hour)},                      // renewal at now+48m
+				{CreationTimestamp: now.Add(-time.Hour), ExpirationTimestamp: now.Add(24 * time.Hour)}, // renewal at now+19h
+			}
+			Expect(clusteraccess.EarliestTokenRenewal(now, 0.8, tokens...)).To(Equal(48 * time.Minute))
+			Expect(clusteraccess.EarliestTokenRenewal(now, 0.5, tokens...)).To(Equal(30 * time.Minute))
+		})
+
+		It("should ignore nil tokens and tokens without expiration", func() {
+			tokens := []*clusteraccess.ServiceAccountToken{
+				nil,
+				{CreationTimestamp: now.Add(-time.Hour)},
+				{CreationTimestamp: now, ExpirationTimestamp: now.Add(10 * time.Hour)},
+			}
+			Expect(clusteraccess.EarliestTokenRenewal(now, 0.8, tokens...)).To(Equal(8 * time.Hour))
+		})
+
+		It("should return 0 if no token has an expiration or a renewal is overdue", func() {
+			Expect(clusteraccess.EarliestTokenRenewal(now, 0.8)).To(BeZero())
+			Expect(clusteraccess.EarliestTokenRenewal(now, 0.8, &clusteraccess.ServiceAccountToken{CreationTimestamp: now})).To(BeZero())
+			Expect(clusteraccess.EarliestTokenRenewal(now, 0.8,
+				&clusteraccess.ServiceAccountToken{CreationTimestamp: now.Add(-2 * time.Hour), ExpirationTimestamp: now.Add(time.Hour)},
+				&clusteraccess.ServiceAccountToken{CreationTimestamp: now.Add(-time.Hour), ExpirationTimestamp: now.Add(-time.Minute)},
+			)).To(BeZero())
+		})
+
+	})
+
+	Context("ValidateKubeconfig", func() {
+
+		It("should return a malformed error if the kubeconfig cannot be parsed", func() {
+			err := clusteraccess.ValidateKubeconfig(context.Background(), []byte("foo: [bar"), time.Second)
+			Expect(err).To(MatchError(clusteraccess.ErrKubeconfigMalformed))
+			Expect(err).ToNot(MatchError(clusteraccess.ErrKubeconfigUnreachable))
+		})
+
+		It("should return an unreachable error if the server cannot be reached", func() {
+			// grab a free port and close the listener again, so that nothing is listening on it
+			l, err := net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).ToNot(HaveOccurred())
+			host := "http://" + l.Addr().String()
+			Expect(l.Close()).To(Succeed())
+			kcfg, err := clusteraccess.CreateTokenKubeconfig("test", host, nil, "token")
+			Expect(err).ToNot(HaveOccurred())
+			err = clusteraccess.ValidateKubeconfig(context.Background(), kcfg, time.Second)
+			Expect(err).To(MatchError(clusteraccess.ErrKubeconfigUnreachable))
+		})
+
+		It("should distinguish between unauthorized and successful requests", func() {
+			// credentials are only sent via TLS
+			srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Authorization") != "Bearer valid" {
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(http.StatusUnauthorized)
+					_, _ = w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"Unauthorized","code":401}`))
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"major":"1","minor":"36","gitVersion":"v1.36.0"}`))
+			}))
+			defer srv.Close()
+			caData := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+
+			kcfg, err := clusteraccess.CreateTokenKubeconfig("test", srv.URL, caData, "invalid")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(clusteraccess.ValidateKubeconfig(context.Background(), kcfg, time.Second)).To(MatchError(clusteraccess.ErrKubeconfigUnauthorized))
+
+			kcfg, err = clusteraccess.CreateTokenKubeconfig("test", srv.URL, caData, "valid")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(clusteraccess.ValidateKubeconfig(context.Background(), kcfg, time.Second)).To(Succeed())
+		})
+
+		It("should return a TLS error if the server certificate is not signed by the configured CA", func() {
+			srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"major":"1","minor":"36","gitVersion":"v1.36.0"}`))
+			}))
+			defer srv.Close()
+			// generate an unrelated CA, all httptest servers share the same certificate
+			key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			Expect(err).ToNot(HaveOccurred())
+			tmpl := &x509.Certificate{
+				SerialNumber:          big.NewInt(1),
+				Subject:               pkix.Name{CommonName: "other-ca"},
+				NotBefore:             time.Now().Add(-time.Hour),
+				NotAfter:              time.Now().Add(time.Hour),
+				IsCA:                  true,
+				KeyUsage:              x509.KeyUsageCertSign,
+				BasicConstraintsValid: true,
+			}
+			caCert, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+			Expect(err).ToNot(HaveOccurred())
+			caData := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caCert})
+
+			kcfg, err := clusteraccess.CreateTokenKubeconfig("test", srv.URL, caData, "token")
+			Expect(err).ToNot(HaveOccurred())
+			err = clusteraccess.ValidateKubeconfig(context.Background(), kcfg, time.Second)
+			Expect(err).To(MatchError(clusteraccess.ErrKubeconfigTLS))
+			Expect(err).ToNot(MatchError(clusteraccess.ErrKubeconfigUnreachable))
+			var x509Err x509.UnknownAuthorityError
+			Expect(errors.As(err, &x509Err)).To(BeTrue())
+		})
+
+		It("should return an unexpected response error if the server answers with an error other than 401 or 403", func() {
+			srv := httptest.NewTLSServer(http.NotFoundHandler())
+			defer srv.Close()
+			caData := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+
+			kcfg, err := clusteraccess.CreateTokenKubeconfig("test", srv.URL, caData, "token")
+			Expect(err).ToNot(HaveOccurred())
+			err = clusteraccess.ValidateKubeconfig(context.Background(), kcfg, time.Second)
+			Expect(err).To(MatchError(clusteraccess.ErrKubeconfigUnexpectedResponse))
+			Expect(err).ToNot(MatchError(clusteraccess.ErrKubeconfigUnreachable))
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+
+	})
+
+	Context("CreateClientCertKubeconfig", func() {
+
+		It("should create a kubeconfig with client certificate authentication", func() {
+			kcfgBytes, err := clusteraccess.CreateClientCertKubeconfig("testuser", "https://api.example.com", []byte("test-ca"), []byte("test-cert"), []byte("test-key"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(kcfgBytes).ToNot(BeEmpty())
+
+			kcfg, err := clientcmd.Load(kcfgBytes)
+			Expect(err).ToNot(HaveOccurred())
+			id := "cluster"
+			Expect(kcfg.CurrentContext).To(Equal(id))
+			Expect(kcfg.Contexts).To(HaveKey(id))
+			Expect(kcfg.Contexts[id].Cluster).To(Equal(id))
+			Expect(kcfg.Contexts[id].AuthInfo).To(Equal("testuser"))
+
+			config, err := clientcmd.RESTConfigFromKubeConfig(kcfgBytes)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(config.Host).To(Equal("https://api.example.com"))
+			Expect(config.TLSClientConfig.CAData).To(Equal([]byte("test-ca")))
+			Expect(config.TLSClientConfig.CertData).To(Equal([]byte("test-cert")))
+			Expect(config.TLSClientConfig.KeyData).To(Equal([]byte("test-key")))
+			Expect(config.BearerToken).To(BeEmpty())
+		})
+
 	})
 
 	Context("CreateOIDCKubeconfig", func() {
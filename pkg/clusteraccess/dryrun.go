@@ -0,0 +1,144 @@
+package clusteraccess
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openmcp-project/controller-utils/pkg/pairs"
+	"github.com/openmcp-project/controller-utils/pkg/resources"
+)
+
+// GetTokenBasedAccessDryRun runs the same flow as GetTokenBasedAccess, but does not persist any changes.
+// All create and update calls are sent with client.DryRunAll and no token is created.
+// It returns the objects that would be created or updated, in the state they would have afterwards.
+// Objects that already exist in the desired state are not part of the returned list.
+// Note that (cluster)rolebindings whose roleRef would change are only returned, but not sent to the server, because the actual flow would delete and recreate them.
+// The same applies to namespaced objects if their namespace does not exist yet, because the server rejects requests for objects in non-existing namespaces, even in dry-run mode.
+func GetTokenBasedAccessDryRun(ctx context.Context, c client.Client, name, namespace string, namespaceScoped bool, rolePrefix string, rules []rbacv1.PolicyRule, expectedLabels ...Label) ([]client.Object, error) {
+	if namespace == "" {
+		return nil, fmt.Errorf("no namespace provided for ServiceAccount")
+	}
+	labels := pairs.PairsToMap(expectedLabels)
+	res := []client.Object{}
+	simulated := map[string]bool{}
+	collect := func(obj client.Object, mutated bool, err error) error {
+		if err != nil {
+			return err
+		}
+		if mutated {
+			res = append(res, obj)
+		}
+		return nil
+	}
+
+	nsm := resources.NewNamespaceMutator(namespace)
+	nsm.MetadataMutator().WithLabels(labels)
+	if err := collect(dryRunCreateOrUpdate(ctx, c, simulated, nsm, expectedLabels...)); err != nil {
+		return nil, err
+	}
+
+	sam := resources.NewServiceAccountMutator(name, namespace)
+	sam.MetadataMutator().WithLabels(labels)
+	if err := collect(dryRunCreateOrUpdate(ctx, c, simulated, sam, expectedLabels...)); err != nil {
+		return nil, err
+	}
+
+	subjects := []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: name, Namespace: namespace}}
+	roleName := rolePrefix + name
+	if namespaceScoped {
+		rm := resources.NewRoleMutator(roleName, namespace, rules)
+		rm.MetadataMutator().WithLabels(labels)
+		if err := collect(dryRunCreateOrUpdate(ctx, c, simulated, rm, expectedLabels...)); err != nil {
+			return nil, err
+		}
+		rbm := resources.NewRoleBindingMutator(roleName, namespace, subjects, resources.NewRoleRef(roleName))
+		rbm.MetadataMutator().WithLabels(labels)
+		if err := collect(dryRunCreateOrUpdate(ctx, c, simulated, rbm, expectedLabels...)); err != nil {
+			return nil, err
+		}
+	} else {
+		crm := resources.NewClusterRoleMutator(roleName, rules)
+		crm.MetadataMutator().WithLabels(labels)
+		if err := collect(dryRunCreateOrUpdate(ctx, c, simulated, crm, expectedLabels...)); err != nil {
+			return nil, err
+		}
+		crbm := resources.NewClusterRoleBindingMutator(roleName, subjects, resources.NewClusterRoleRef(roleName))
+		crbm.MetadataMutator().WithLabels(labels)
+		if err := collect(dryRunCreateOrUpdate(ctx, c, simulated, crbm, expectedLabels...)); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// dryRunCreateOrUpdate fetches the object described by the mutator and sends a dry-run create or update request, if required.
+// It returns the mutated object and whether it would have been created or updated.
+// If the object exists, but does not have the expected labels, a ResourceNotManagedError is returned.
+// simulated contains the names of namespaces which have only been created in dry-run mode.
+// Objects in these namespaces cannot exist, so they are mutated locally without sending any requests to the server.
+// If a namespace is created in dry-run mode, it is added to simulated.
+func dryRunCreateOrUpdate[K client.Object](ctx context.Context, c client.Client, simulated map[string]bool, m resources.Mutator[K], expectedLabels ...Label) (client.Object, bool, error) {
+	obj := m.Empty()
+	if ns := obj.GetNamespace(); ns != "" && simulated[ns] {
+		if err := m.Mutate(obj); err != nil {
+			return nil, false, fmt.Errorf("error mutating %s: %w", m.String(), err)
+		}
+		return obj, true, nil
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, false, fmt.Errorf("error getting %s: %w", m.String(), err)
+		}
+		if err := m.Mutate(obj); err != nil {
+			return nil, false, fmt.Errorf("error mutating %s: %w", m.String(), err)
+		}
+		if err := c.Create(ctx, obj, client.DryRunAll); err != nil {
+			return nil, false, fmt.Errorf("error creating %s (dry-run): %w", m.String(), err)
+		}
+		if _, ok := any(obj).(*corev1.Namespace); ok {
+			simulated[obj.GetName()] = true
+		}
+		return obj, true, nil
+	}
+	if err := FailIfNotManaged(obj, expectedLabels...); err != nil {
+		return nil, false, err
+	}
+	old, ok := obj.DeepCopyObject().(K)
+	if !ok {
+		return nil, false, fmt.Errorf("error copying %s", m.String())
+	}
+	if err := m.Mutate(obj); err != nil {
+		return nil, false, fmt.Errorf("error mutating %s: %w", m.String(), err)
+	}
+	if equality.Semantic.DeepEqual(old, obj) {
+		return obj, false, nil
+	}
+	if roleRefChanged(old, obj) {
+		// the roleRef is immutable, the actual flow would delete and recreate the binding
+		return obj, true, nil
+	}
+	if err := c.Update(ctx, obj, client.DryRunAll); err != nil {
+		return nil, false, fmt.Errorf("error updating %s (dry-run): %w", m.String(), err)
+	}
+	return obj, true, nil
+}
+
+// roleRefChanged returns true if both objects are (cluster)rolebindings with different roleRefs.
+func roleRefChanged(oldObj, newObj client.Object) bool {
+	switch o := oldObj.(type) {
+	case *rbacv1.RoleBinding:
+		n, ok := newObj.(*rbacv1.RoleBinding)
+		return ok && !equality.Semantic.DeepEqual(o.RoleRef, n.RoleRef)
+	case *rbacv1.ClusterRoleBinding:
+		n, ok := newObj.(*rbacv1.ClusterRoleBinding)
+		return ok && !equality.Semantic.DeepEqual(o.RoleRef, n.RoleRef)
+	}
+	return false
+}
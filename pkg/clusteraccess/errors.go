@@ -1,6 +1,7 @@
 package clusteraccess
 
 import (
+	"errors"
 	"fmt"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -61,15 +62,13 @@ func (e *ResourceNotManagedError) Error() string {
 	return fmt.Sprintf("%s '%s%s' exists but does not contain the expected management labels %v, its actual labels are %v", kind, nsMod, e.Obj.GetName(), e.ExpectedLabels, actualLabels)
 }
 
-// IsResourceNotManagedError returns true if the error is non-nil and of type *ResourceNotManagedError.
+// IsResourceNotManagedError returns true if the error is non-nil and of type *ResourceNotManagedError or wraps such an error.
 func IsResourceNotManagedError(err error) bool {
 	if err == nil {
 		return false
 	}
-	if _, ok := err.(*ResourceNotManagedError); ok {
-		return true
-	}
-	return false
+	var rnmErr *ResourceNotManagedError
+	return errors.As(err, &rnmErr)
 }
 
 // NewWaitingForRecreationError creates a new WaitingForRecreationError.
@@ -117,13 +116,11 @@ func (e *WaitingForRecreationError) Error() string {
 	return fmt.Sprintf("%s '%s%s' needs to be recreated, but the deletion is not completed yet. Please call this function again later.", kind, nsMod, name)
 }
 
-// IsWaitingForRecreationError returns true if the error is non-nil and of type *WaitingForRecreationError.
+// IsWaitingForRecreationError returns true if the error is non-nil and of type *WaitingForRecreationError or wraps such an error.
 func IsWaitingForRecreationError(err error) bool {
 	if err == nil {
 		return false
 	}
-	if _, ok := err.(*WaitingForRecreationError); ok {
-		return true
-	}
-	return false
+	var wfrErr *WaitingForRecreationError
+	return errors.As(err, &wfrErr)
 }
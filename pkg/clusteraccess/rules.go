@@ -0,0 +1,186 @@
+package clusteraccess
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RuleMergeMode specifies how desired rules are combined with the rules of an already existing (Cluster)Role.
+type RuleMergeMode string
+
+const (
+	// RuleMergeModeReplace replaces the existing rules with the desired ones.
+	RuleMergeModeReplace RuleMergeMode = "Replace"
+	// RuleMergeModeUnion adds the desired rules to the existing ones.
+	// The combined rules are reduced to a minimal set (see UnionRules), which removes duplicates.
+	RuleMergeModeUnion RuleMergeMode = "Union"
+)
+
+// merge combines the existing rules with the desired ones according to the merge mode.
+// Unknown merge modes are treated like RuleMergeModeReplace.
+func (m RuleMergeMode) merge(existing, desired []rbacv1.PolicyRule) []rbacv1.PolicyRule {
+	if m == RuleMergeModeUnion {
+		return UnionRules(existing, desired)
+	}
+	return desired
+}
+
+// RoleDrifted fetches the specified Role and checks whether its rules differ from the desired ones.
+// The rules are normalized before comparing them, so the order of rules and of the values within a rule does not matter, and neither do duplicates.
+// If the Role does not exist, it is considered to have drifted.
+func RoleDrifted(ctx context.Context, c client.Client, name, namespace string, desiredRules []rbacv1.PolicyRule) (bool, error) {
+	r := &rbacv1.Role{}
+	r.SetName(name)
+	r.SetNamespace(namespace)
+	if err := c.Get(ctx, client.ObjectKeyFromObject(r), r); err != nil {
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, fmt.Errorf("error getting Role '%s/%s': %w", r.Namespace, r.Name, err)
+	}
+	return !RulesEqual(r.Rules, desiredRules), nil
+}
+
+// ClusterRoleDrifted fetches the specified ClusterRole and checks whether its rules differ from the desired ones.
+// The rules are normalized before comparing them, so the order of rules and of the values within a rule does not matter, and neither do duplicates.
+// If the ClusterRole does not exist, it is considered to have drifted.
+func ClusterRoleDrifted(ctx context.Context, c client.Client, name string, desiredRules []rbacv1.PolicyRule) (bool, error) {
+	cr := &rbacv1.ClusterRole{}
+	cr.SetName(name)
+	if err := c.Get(ctx, client.ObjectKeyFromObject(cr), cr); err != nil {
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, fmt.Errorf("error getting ClusterRole '%s': %w", cr.Name, err)
+	}
+	return !RulesEqual(cr.Rules, desiredRules), nil
+}
+
+// RulesEqual returns true if both lists of rules are equal after normalization.
+func RulesEqual(a, b []rbacv1.PolicyRule) bool {
+	return equality.Semantic.DeepEqual(NormalizeRules(a), NormalizeRules(b))
+}
+
+// NormalizeRules returns a normalized copy of the given rules.
+// Within each rule, all lists are sorted and deduplicated. Afterwards, the rules themselves are sorted and duplicate rules are removed.
+// The given slice is not modified.
+func NormalizeRules(rules []rbacv1.PolicyRule) []rbacv1.PolicyRule {
+	res := make([]rbacv1.PolicyRule, 0, len(rules))
+	for _, rule := range rules {
+		res = append(res, rbacv1.PolicyRule{
+			Verbs:           normalizeStrings(rule.Verbs),
+			APIGroups:       normalizeStrings(rule.APIGroups),
+			Resources:       normalizeStrings(rule.Resources),
+			ResourceNames:   normalizeStrings(rule.ResourceNames),
+			NonResourceURLs: normalizeStrings(rule.NonResourceURLs),
+		})
+	}
+	slices.SortFunc(res, compareRules)
+	return slices.CompactFunc(res, func(a, b rbacv1.PolicyRule) bool {
+		return compareRules(a, b) == 0
+	})
+}
+
+// UnionRules combines the given rule sets into a minimal set of rules which grants the same permissions.
+// The rules are normalized (see NormalizeRules), rules which differ only in their verbs are merged into a single rule,
+// and rules whose permissions are completely covered by another rule are removed.
+// The '*' wildcard is respected for verbs, API groups, and resources. A rule without resource names applies to all resource names.
+// The given slices are not modified.
+func UnionRules(ruleSets ...[]rbacv1.PolicyRule) []rbacv1.PolicyRule {
+	combined := []rbacv1.PolicyRule{}
+	for _, rules := range ruleSets {
+		combined = append(combined, rules...)
+	}
+	combined = NormalizeRules(combined)
+
+	// merge rules which differ only in their verbs
+	merged := make([]rbacv1.PolicyRule, 0, len(combined))
+	for _, rule := range combined {
+		idx := slices.IndexFunc(merged, func(other rbacv1.PolicyRule) bool {
+			return slices.Equal(rule.APIGroups, other.APIGroups) && slices.Equal(rule.Resources, other.Resources) && slices.Equal(rule.ResourceNames, other.ResourceNames) && slices.Equal(rule.NonResourceURLs, other.NonResourceURLs)
+		})
+		if idx < 0 {
+			merged = append(merged, rule)
+			continue
+		}
+		merged[idx].Verbs = normalizeStrings(append(merged[idx].Verbs, rule.Verbs...))
+	}
+
+	// remove rules which are covered by other rules
+	res := make([]rbacv1.PolicyRule, 0, len(merged))
+	for i, rule := range merged {
+		covered := false
+		for j, other := range merged {
+			// if two rules cover each other, keep the first one
+			if i != j && ruleCovers(other, rule) && (j < i || !ruleCovers(rule, other)) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			res = append(res, rule)
+		}
+	}
+	return NormalizeRules(res)
+}
+
+// ruleCovers returns true if rule a grants all permissions that are granted by rule b.
+// Both rules are expected to be normalized.
+func ruleCovers(a, b rbacv1.PolicyRule) bool {
+	if !coversStrings(a.Verbs, b.Verbs, true) || !coversStrings(a.APIGroups, b.APIGroups, true) || !coversStrings(a.Resources, b.Resources, true) || !coversStrings(a.NonResourceURLs, b.NonResourceURLs, false) {
+		return false
+	}
+	if len(a.ResourceNames) == 0 {
+		// a applies to all resource names
+		return true
+	}
+	return len(b.ResourceNames) > 0 && coversStrings(a.ResourceNames, b.ResourceNames, false)
+}
+
+// coversStrings returns true if all values of b are contained in a.
+// If allowWildcard is true, a '*' in a covers everything.
+func coversStrings(a, b []string, allowWildcard bool) bool {
+	if allowWildcard && slices.Contains(a, rbacv1.ResourceAll) && len(b) > 0 {
+		return true
+	}
+	for _, v := range b {
+		if !slices.Contains(a, v) {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeStrings returns a sorted and deduplicated copy of the given list.
+// Returns nil for empty lists, so that nil and empty lists are treated the same.
+func normalizeStrings(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	res := slices.Clone(values)
+	slices.Sort(res)
+	return slices.Compact(res)
+}
+
+// compareRules compares two normalized rules.
+func compareRules(a, b rbacv1.PolicyRule) int {
+	if cmp := slices.Compare(a.APIGroups, b.APIGroups); cmp != 0 {
+		return cmp
+	}
+	if cmp := slices.Compare(a.Resources, b.Resources); cmp != 0 {
+		return cmp
+	}
+	if cmp := slices.Compare(a.ResourceNames, b.ResourceNames); cmp != 0 {
+		return cmp
+	}
+	if cmp := slices.Compare(a.NonResourceURLs, b.NonResourceURLs); cmp != 0 {
+		return cmp
+	}
+	return slices.Compare(a.Verbs, b.Verbs)
+}
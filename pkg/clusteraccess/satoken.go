@@ -0,0 +1,96 @@
+package clusteraccess
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ErrTokenSecretNotPopulated is returned by GetOrCreateSAToken if the token for a ServiceAccount is read from a token secret,
+// but the secret has not yet been populated by the token controller. Please call the function again later.
+var ErrTokenSecretNotPopulated = errors.New("service account token secret has not been populated yet")
+
+// GetOrCreateSAToken returns a token for the given ServiceAccount.
+// It prefers creating a token via the TokenRequest API. If that API is not available (the 'token' subresource returns a NotFound or MethodNotSupported error),
+// it falls back to reading the token from a secret of type 'kubernetes.io/service-account-token' which belongs to the ServiceAccount.
+// The secret is looked up via the ServiceAccount's 'secrets' field first, and via its annotation in the ServiceAccount's namespace afterwards.
+// If no such secret exists, one is created and an error wrapping ErrTokenSecretNotPopulated is returned, because the token controller has to populate the secret first.
+// The desired duration is only taken into account for the TokenRequest API. Tokens from secrets don't expire, so the ExpirationTimestamp of the returned token is zero.
+func GetOrCreateSAToken(ctx context.Context, c client.Client, sa *corev1.ServiceAccount, desiredDuration *time.Duration) (*ServiceAccountToken, error) {
+	sat, err := CreateTokenForServiceAccount(ctx, c, sa, desiredDuration)
+	if err == nil {
+		return sat, nil
+	}
+	if !apierrors.IsNotFound(err) && !apierrors.IsMethodNotSupported(err) {
+		return nil, err
+	}
+
+	// TokenRequest API is not available, fall back to token secret
+	// fetch the ServiceAccount, to distinguish a missing subresource from a missing ServiceAccount and to get the current list of secrets
+	// a copy is used to not overwrite the object passed in by the caller
+	sa = sa.DeepCopy()
+	if err := c.Get(ctx, client.ObjectKeyFromObject(sa), sa); err != nil {
+		return nil, fmt.Errorf("error getting ServiceAccount '%s/%s': %w", sa.Namespace, sa.Name, err)
+	}
+	secret, err := getTokenSecret(ctx, c, sa)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil {
+		secret = &corev1.Secret{}
+		secret.Name = sa.Name + "-token"
+		secret.Namespace = sa.Namespace
+		secret.Type = corev1.SecretTypeServiceAccountToken
+		secret.Annotations = map[string]string{
+			corev1.ServiceAccountNameKey: sa.Name,
+		}
+		if err := c.Create(ctx, secret); err != nil {
+			return nil, fmt.Errorf("error creating token secret '%s/%s' for ServiceAccount '%s/%s': %w", secret.Namespace, secret.Name, sa.Namespace, sa.Name, err)
+		}
+		return nil, fmt.Errorf("%w: secret '%s/%s' has been created for ServiceAccount '%s/%s'", ErrTokenSecretNotPopulated, secret.Namespace, secret.Name, sa.Namespace, sa.Name)
+	}
+	token := secret.Data[corev1.ServiceAccountTokenKey]
+	if len(token) == 0 {
+		return nil, fmt.Errorf("%w: secret '%s/%s' for ServiceAccount '%s/%s' does not contain a token", ErrTokenSecretNotPopulated, secret.Namespace, secret.Name, sa.Namespace, sa.Name)
+	}
+	return &ServiceAccountToken{
+		Token:             string(token),
+		CreationTimestamp: secret.CreationTimestamp.Time,
+	}, nil
+}
+
+// getTokenSecret returns the token secret belonging to the given ServiceAccount.
+// Returns nil if no such secret exists.
+func getTokenSecret(ctx context.Context, c client.Client, sa *corev1.ServiceAccount) (*corev1.Secret, error) {
+	for _, ref := range sa.Secrets {
+		secret := &corev1.Secret{}
+		if err := c.Get(ctx, client.ObjectKey{Name: ref.Name, Namespace: sa.Namespace}, secret); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("error getting secret '%s/%s': %w", sa.Namespace, ref.Name, err)
+		}
+		if isTokenSecretFor(secret, sa) {
+			return secret, nil
+		}
+	}
+	secrets := &corev1.SecretList{}
+	if err := c.List(ctx, secrets, client.InNamespace(sa.Namespace)); err != nil {
+		return nil, fmt.Errorf("error listing secrets in namespace '%s': %w", sa.Namespace, err)
+	}
+	for i := range secrets.Items {
+		if isTokenSecretFor(&secrets.Items[i], sa) {
+			return &secrets.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func isTokenSecretFor(secret *corev1.Secret, sa *corev1.ServiceAccount) bool {
+	return secret.Type == corev1.SecretTypeServiceAccountToken && secret.Annotations[corev1.ServiceAccountNameKey] == sa.Name
+}
@@ -0,0 +1,77 @@
+package clusteraccess
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+var (
+	// ErrKubeconfigMalformed is returned by ValidateKubeconfig if the kubeconfig cannot be parsed or does not result in a valid REST config.
+	ErrKubeconfigMalformed = errors.New("kubeconfig is malformed")
+	// ErrKubeconfigUnreachable is returned by ValidateKubeconfig if the server referenced by the kubeconfig cannot be reached.
+	ErrKubeconfigUnreachable = errors.New("server referenced by kubeconfig is unreachable")
+	// ErrKubeconfigUnauthorized is returned by ValidateKubeconfig if the server rejected the credentials from the kubeconfig.
+	ErrKubeconfigUnauthorized = errors.New("server rejected credentials from kubeconfig")
+	// ErrKubeconfigTLS is returned by ValidateKubeconfig if the TLS connection to the server failed, e.g. because its certificate is not signed by the configured CA.
+	ErrKubeconfigTLS = errors.New("TLS connection to server referenced by kubeconfig failed")
+	// ErrKubeconfigUnexpectedResponse is returned by ValidateKubeconfig if the server could be reached, but answered with an error other than 401 or 403, e.g. 404.
+	ErrKubeconfigUnexpectedResponse = errors.New("server referenced by kubeconfig returned an unexpected response")
+)
+
+// ValidateKubeconfig checks whether the given kubeconfig can be used to access the cluster it points to.
+// It parses the kubeconfig, creates a discovery client from it, and queries the server's '/version' endpoint.
+// If timeout is greater than zero, the request is aborted after the given duration.
+// The returned error wraps one of ErrKubeconfigMalformed, ErrKubeconfigUnreachable, ErrKubeconfigUnauthorized, ErrKubeconfigTLS, or ErrKubeconfigUnexpectedResponse,
+// which can be checked for via errors.Is, as well as the original error. It is nil if the server could be reached and accepted the credentials.
+func ValidateKubeconfig(ctx context.Context, kubeconfig []byte, timeout time.Duration) error {
+	cfg, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrKubeconfigMalformed, err)
+	}
+	if timeout > 0 {
+		cfg.Timeout = timeout
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	dc, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("%w: error creating discovery client: %w", ErrKubeconfigMalformed, err)
+	}
+	if _, err := dc.RESTClient().Get().AbsPath("/version").Do(ctx).Raw(); err != nil {
+		if apierrors.IsUnauthorized(err) || apierrors.IsForbidden(err) {
+			return fmt.Errorf("%w: %w", ErrKubeconfigUnauthorized, err)
+		}
+		if isTLSError(err) {
+			return fmt.Errorf("%w: %w", ErrKubeconfigTLS, err)
+		}
+		var status apierrors.APIStatus
+		if errors.As(err, &status) {
+			return fmt.Errorf("%w: %w", ErrKubeconfigUnexpectedResponse, err)
+		}
+		return fmt.Errorf("%w: %w", ErrKubeconfigUnreachable, err)
+	}
+	return nil
+}
+
+// isTLSError returns true if the error has been caused by a failed TLS handshake or certificate verification.
+func isTLSError(err error) bool {
+	var (
+		verificationErr *tls.CertificateVerificationError
+		recordHeaderErr tls.RecordHeaderError
+		alertErr        tls.AlertError
+		unknownAuthErr  x509.UnknownAuthorityError
+		invalidErr      x509.CertificateInvalidError
+		hostnameErr     x509.HostnameError
+	)
+	return errors.As(err, &verificationErr) || errors.As(err, &recordHeaderErr) || errors.As(err, &alertErr) ||
+		errors.As(err, &unknownAuthErr) || errors.As(err, &invalidErr) || errors.As(err, &hostnameErr)
+}
@@ -0,0 +1,61 @@
+package collections
+
+import "slices"
+
+// OrderedSet is a set which remembers the order in which its elements have been added.
+// Adding an element which is already contained does not change its position.
+// The zero value is not usable, use NewOrderedSet to create a new OrderedSet.
+type OrderedSet[T comparable] struct {
+	index    map[T]struct{}
+	elements []T
+}
+
+// NewOrderedSet creates a new OrderedSet containing the given elements in the given order.
+func NewOrderedSet[T comparable](elements ...T) *OrderedSet[T] {
+	res := &OrderedSet[T]{
+		index:    make(map[T]struct{}, len(elements)),
+		elements: make([]T, 0, len(elements)),
+	}
+	res.Add(elements...)
+	return res
+}
+
+// Add appends the given elements to the set, unless they are already contained.
+func (s *OrderedSet[T]) Add(elements ...T) {
+	for _, e := range elements {
+		if _, ok := s.index[e]; ok {
+			continue
+		}
+		s.index[e] = struct{}{}
+		s.elements = append(s.elements, e)
+	}
+}
+
+// Has returns true if the given element is contained in the set.
+func (s *OrderedSet[T]) Has(element T) bool {
+	_, ok := s.index[element]
+	return ok
+}
+
+// Remove removes the given elements from the set.
+// The order of the remaining elements is not changed.
+func (s *OrderedSet[T]) Remove(elements ...T) {
+	for _, e := range elements {
+		if _, ok := s.index[e]; !ok {
+			continue
+		}
+		delete(s.index, e)
+		s.elements = slices.DeleteFunc(s.elements, func(x T) bool { return x == e })
+	}
+}
+
+// Len returns the number of elements in the set.
+func (s *OrderedSet[T]) Len() int {
+	return len(s.elements)
+}
+
+// Slice returns the elements of the set in insertion order.
+// The returned slice is a copy, modifying it does not affect the set.
+func (s *OrderedSet[T]) Slice() []T {
+	return slices.Clone(s.elements)
+}
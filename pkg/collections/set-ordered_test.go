@@ -0,0 +1,47 @@
+package collections_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/openmcp-project/controller-utils/pkg/collections"
+)
+
+var _ = Describe("OrderedSet", func() {
+
+	It("should preserve the insertion order", func() {
+		s := collections.NewOrderedSet("c", "a", "b", "a")
+		Expect(s.Len()).To(Equal(3))
+		Expect(s.Slice()).To(Equal([]string{"c", "a", "b"}))
+		s.Add("d", "c")
+		Expect(s.Slice()).To(Equal([]string{"c", "a", "b", "d"}))
+	})
+
+	It("should preserve the order of the remaining elements on removal", func() {
+		s := collections.NewOrderedSet(1, 2, 3, 4, 5)
+		s.Remove(2, 4, 6)
+		Expect(s.Slice()).To(Equal([]int{1, 3, 5}))
+		Expect(s.Has(2)).To(BeFalse())
+		Expect(s.Has(3)).To(BeTrue())
+
+		// re-adding a removed element appends it to the end
+		s.Add(2)
+		Expect(s.Slice()).To(Equal([]int{1, 3, 5, 2}))
+		Expect(s.Has(2)).To(BeTrue())
+	})
+
+	It("should return a copy from Slice", func() {
+		s := collections.NewOrderedSet("a", "b")
+		elements := s.Slice()
+		elements[0] = "x"
+		Expect(s.Slice()).To(Equal([]string{"a", "b"}))
+		Expect(s.Has("x")).To(BeFalse())
+	})
+
+	It("should return an empty slice for an empty set", func() {
+		s := collections.NewOrderedSet[string]()
+		Expect(s.Len()).To(Equal(0))
+		Expect(s.Slice()).To(BeEmpty())
+	})
+
+})
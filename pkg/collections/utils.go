@@ -74,6 +74,24 @@ func ProjectMapToMap[K1 comparable, V1 any, K2 comparable, V2 any](src map[K1]V1
 	return res
 }
 
+// Partition splits a slice into two by the given predicate.
+// The first returned slice contains all elements for which the predicate returns true, the second one all others.
+// The order of the elements is preserved and both returned slices are non-nil.
+// The original slice is not modified.
+// If the predicate is nil, all elements are considered non-matching.
+func Partition[T any](s []T, pred func(T) bool) (matching, nonMatching []T) {
+	matching = []T{}
+	nonMatching = []T{}
+	for _, x := range s {
+		if pred != nil && pred(x) {
+			matching = append(matching, x)
+		} else {
+			nonMatching = append(nonMatching, x)
+		}
+	}
+	return matching, nonMatching
+}
+
 // AggregateSlice takes a slice, an aggregation function and an initial value.
 // It applies the aggregation function to each element of the slice, also passing in the current result.
 // For the first element, it uses the initial value as the current result.
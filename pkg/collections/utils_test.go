@@ -120,6 +120,51 @@ var _ = Describe("Utils Tests", func() {
 
 	})
 
+	Context("Partition", func() {
+
+		isEven := func(i int) bool {
+			return i%2 == 0
+		}
+
+		It("should return empty non-nil slices for an empty or nil input slice", func() {
+			matching, nonMatching := collections.Partition(nil, isEven)
+			Expect(matching).ToNot(BeNil())
+			Expect(matching).To(BeEmpty())
+			Expect(nonMatching).ToNot(BeNil())
+			Expect(nonMatching).To(BeEmpty())
+			matching, nonMatching = collections.Partition([]int{}, isEven)
+			Expect(matching).To(And(Not(BeNil()), BeEmpty()))
+			Expect(nonMatching).To(And(Not(BeNil()), BeEmpty()))
+		})
+
+		It("should put all elements into the first slice if all match", func() {
+			matching, nonMatching := collections.Partition([]int{2, 4, 6}, isEven)
+			Expect(matching).To(Equal([]int{2, 4, 6}))
+			Expect(nonMatching).To(And(Not(BeNil()), BeEmpty()))
+		})
+
+		It("should put all elements into the second slice if none match", func() {
+			matching, nonMatching := collections.Partition([]int{1, 3, 5}, isEven)
+			Expect(matching).To(And(Not(BeNil()), BeEmpty()))
+			Expect(nonMatching).To(Equal([]int{1, 3, 5}))
+		})
+
+		It("should split a mixed slice and preserve the order", func() {
+			src := []int{1, 2, 3, 4, 5, 6}
+			matching, nonMatching := collections.Partition(src, isEven)
+			Expect(matching).To(Equal([]int{2, 4, 6}))
+			Expect(nonMatching).To(Equal([]int{1, 3, 5}))
+			Expect(src).To(Equal([]int{1, 2, 3, 4, 5, 6}), "original slice should not be modified")
+		})
+
+		It("should consider all elements non-matching for a nil predicate", func() {
+			matching, nonMatching := collections.Partition([]int{1, 2}, nil)
+			Expect(matching).To(And(Not(BeNil()), BeEmpty()))
+			Expect(nonMatching).To(Equal([]int{1, 2}))
+		})
+
+	})
+
 	Context("AggregateSlice", func() {
 
 		sum := func(val, s int) int {
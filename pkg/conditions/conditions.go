@@ -1,8 +1,12 @@
 package conditions
 
 import (
+	"fmt"
+	"strings"
+
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 )
 
 // GetCondition is an alias for apimeta.FindStatusCondition.
@@ -11,6 +15,34 @@ func GetCondition(conditions []metav1.Condition, conditionType string) *metav1.C
 	return apimeta.FindStatusCondition(conditions, conditionType)
 }
 
+// GetConditionCopy returns a copy of the condition of the specified type from the given conditions slice.
+// In contrast to GetCondition, modifying the returned condition does not affect the given slice.
+// The second return value is false if the condition does not exist.
+func GetConditionCopy(conditions []metav1.Condition, conditionType string) (metav1.Condition, bool) {
+	con := GetCondition(conditions, conditionType)
+	if con == nil {
+		return metav1.Condition{}, false
+	}
+	return *con, true
+}
+
+// GetConditionStatus returns the status of the condition with the specified type from the given conditions slice.
+// The second return value is false if the condition does not exist.
+func GetConditionStatus(conditions []metav1.Condition, conditionType string) (metav1.ConditionStatus, bool) {
+	con := GetCondition(conditions, conditionType)
+	if con == nil {
+		return "", false
+	}
+	return con.Status, true
+}
+
+// IsConditionTrue returns true if the condition with the specified type exists in the given conditions slice and has status True.
+// It returns false if the condition does not exist or has any other status.
+func IsConditionTrue(conditions []metav1.Condition, conditionType string) bool {
+	status, ok := GetConditionStatus(conditions, conditionType)
+	return ok && status == metav1.ConditionTrue
+}
+
 // FromBoolPointer returns the metav1.ConditionStatus that matches the given bool pointer.
 // nil = ConditionUnknown
 // true = ConditionTrue
@@ -32,6 +64,13 @@ func FromBool(status bool) metav1.ConditionStatus {
 	return FromBoolPointer(&status)
 }
 
+// FromError returns the metav1.ConditionStatus that matches the given error.
+// nil = ConditionTrue
+// non-nil = ConditionFalse
+func FromError(err error) metav1.ConditionStatus {
+	return FromBool(err == nil)
+}
+
 // ToBoolPointer is the inverse of FromBoolPointer.
 // It returns a pointer to a bool that matches the given ConditionStatus.
 // If the status is ConditionTrue, it returns a pointer to true.
@@ -59,3 +98,101 @@ func AllConditionsHaveStatus(status metav1.ConditionStatus, conditions ...metav1
 	}
 	return true
 }
+
+// ImportConditions returns copies of the given conditions with their types prefixed with '<prefix>/'.
+// All other fields of the conditions are preserved.
+// This is useful for aggregating the conditions of child resources into the status of a parent resource.
+// If the prefix is empty, the conditions are copied without modification.
+func ImportConditions(child []metav1.Condition, prefix string) []metav1.Condition {
+	res := make([]metav1.Condition, len(child))
+	for i, con := range child {
+		res[i] = *con.DeepCopy()
+		if prefix != "" {
+			res[i].Type = prefix + "/" + con.Type
+		}
+	}
+	return res
+}
+
+// StampObservedGeneration returns copies of the given conditions with their ObservedGeneration set to the given generation.
+// All other fields of the conditions are preserved, the given list is not modified.
+// Returns nil if the given list is nil.
+func StampObservedGeneration(cons []metav1.Condition, gen int64) []metav1.Condition {
+	if cons == nil {
+		return nil
+	}
+	res := make([]metav1.Condition, len(cons))
+	for i, con := range cons {
+		res[i] = *con.DeepCopy()
+		res[i].ObservedGeneration = gen
+	}
+	return res
+}
+
+// EnsureTypes compares the types of the given conditions against the required ones.
+// It returns the required types for which no condition exists (in the order of requiredTypes)
+// and the types of conditions which are not required (in the order of the conditions).
+// Both returned lists are free of duplicates and nil if there are no missing or extra types, respectively.
+func EnsureTypes(cons []metav1.Condition, requiredTypes ...string) (missing []string, extra []string) {
+	required := sets.New(requiredTypes...)
+	present := sets.New[string]()
+	for _, con := range cons {
+		if !required.Has(con.Type) && !present.Has(con.Type) {
+			extra = append(extra, con.Type)
+		}
+		present.Insert(con.Type)
+	}
+	for _, t := range requiredTypes {
+		if !present.Has(t) {
+			missing = append(missing, t)
+			present.Insert(t)
+		}
+	}
+	return missing, extra
+}
+
+const (
+	// ReasonAllConditionsTrue is the reason of the condition returned by AggregateReady if all aggregated conditions are True.
+	ReasonAllConditionsTrue = "AllConditionsTrue"
+	// ReasonConditionsUnknown is the reason of the condition returned by AggregateReady if at least one aggregated condition is Unknown.
+	ReasonConditionsUnknown = "ConditionsUnknown"
+	// ReasonConditionsNotTrue is the reason of the condition returned by AggregateReady if at least one aggregated condition is False and none is Unknown.
+	ReasonConditionsNotTrue = "ConditionsNotTrue"
+)
+
+// AggregateReady returns a condition of type readyType which aggregates the status of all other given conditions.
+// The condition with type readyType itself as well as all conditions whose type is contained in ignore are not taken into account.
+// The status of the returned condition is True if all aggregated conditions are True (which is also the case if there are none),
+// Unknown if any of them is Unknown, and False otherwise. Conditions with any other status are treated as False.
+// The message lists the conditions which are not True, in the order in which they appear in the given list.
+// ObservedGeneration and LastTransitionTime are not set, the returned condition is meant to be used as a template for the ConditionUpdater.
+func AggregateReady(cons []metav1.Condition, readyType string, ignore ...string) metav1.Condition {
+	ignored := sets.New(ignore...).Insert(readyType)
+	unknown := false
+	offending := []string{}
+	for _, con := range cons {
+		if ignored.Has(con.Type) || con.Status == metav1.ConditionTrue {
+			continue
+		}
+		if con.Status == metav1.ConditionUnknown {
+			unknown = true
+		}
+		offending = append(offending, fmt.Sprintf("%s (%s)", con.Type, con.Status))
+	}
+	res := metav1.Condition{
+		Type:    readyType,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonAllConditionsTrue,
+		Message: "All conditions are True",
+	}
+	if len(offending) > 0 {
+		res.Status = metav1.ConditionFalse
+		res.Reason = ReasonConditionsNotTrue
+		if unknown {
+			res.Status = metav1.ConditionUnknown
+			res.Reason = ReasonConditionsUnknown
+		}
+		res.Message = fmt.Sprintf("The following conditions are not True: %s", strings.Join(offending, ", "))
+	}
+	return res
+}
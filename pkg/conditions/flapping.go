@@ -0,0 +1,103 @@
+package conditions
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FlapDetector tracks status transitions of conditions across successive observations of objects.
+// It can be used to detect conditions which change their status repeatedly within a short time frame, which usually indicates an unstable state.
+// Objects are identified by their UID. A FlapDetector is safe for concurrent use.
+type FlapDetector struct {
+	retention time.Duration
+	objects   map[string]map[string]*flapState
+	mu        sync.Mutex
+}
+
+type flapState struct {
+	status      metav1.ConditionStatus
+	transitions []time.Time
+}
+
+// NewFlapDetector creates a new FlapDetector.
+// Transitions older than the given retention are discarded, so IsFlapping cannot look further back than that.
+// Defaults to one hour if not positive.
+func NewFlapDetector(retention time.Duration) *FlapDetector {
+	if retention <= 0 {
+		retention = time.Hour
+	}
+	return &FlapDetector{
+		retention: retention,
+		objects:   map[string]map[string]*flapState{},
+	}
+}
+
+// Observe records the current conditions of the object with the given UID.
+// For each condition whose status differs from the previously observed status of the same type, a transition is recorded.
+// The transition time is taken from the condition's lastTransitionTime, or the current time if that is not set.
+// The first observation of a condition type does not count as transition. Conditions which are missing from the list are ignored.
+func (d *FlapDetector) Observe(uid string, cons []metav1.Condition) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	states, ok := d.objects[uid]
+	if !ok {
+		states = map[string]*flapState{}
+		d.objects[uid] = states
+	}
+	for _, con := range cons {
+		state, ok := states[con.Type]
+		if !ok {
+			states[con.Type] = &flapState{status: con.Status}
+			continue
+		}
+		if state.status != con.Status {
+			at := con.LastTransitionTime.Time
+			if at.IsZero() {
+				at = now
+			}
+			state.status = con.Status
+			state.transitions = append(state.transitions, at)
+		}
+		state.prune(now.Add(-d.retention))
+	}
+}
+
+// IsFlapping returns true if the condition with the given type of the object with the given UID
+// has changed its status at least threshold times within the given window, counting back from now.
+func (d *FlapDetector) IsFlapping(uid, conType string, threshold int, window time.Duration) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	state, ok := d.objects[uid][conType]
+	if !ok {
+		return false
+	}
+	since := time.Now().Add(-window)
+	count := 0
+	for _, t := range state.transitions {
+		if !t.Before(since) {
+			count++
+		}
+	}
+	return count >= threshold
+}
+
+// Forget removes all recorded information about the object with the given UID.
+// It should be called when the object is deleted.
+func (d *FlapDetector) Forget(uid string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.objects, uid)
+}
+
+// prune removes all transitions before the given time.
+func (s *flapState) prune(before time.Time) {
+	s.transitions = slices.DeleteFunc(s.transitions, func(t time.Time) bool {
+		return t.Before(before)
+	})
+}
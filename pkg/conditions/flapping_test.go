@@ -0,0 +1,82 @@
+package conditions_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openmcp-project/controller-utils/pkg/conditions"
+)
+
+var _ = Describe("FlapDetector", func() {
+
+	readyCondition := func(status bool, at time.Time) []metav1.Condition {
+		return []metav1.Condition{
+			{
+				Type:               "Ready",
+				Status:             conditions.FromBool(status),
+				LastTransitionTime: metav1.NewTime(at),
+			},
+		}
+	}
+
+	It("should detect flapping once the threshold is reached", func() {
+		fd := conditions.NewFlapDetector(time.Hour)
+		now := time.Now()
+		fd.Observe("uid1", readyCondition(true, now.Add(-10*time.Minute)))
+		for i := range 2 {
+			fd.Observe("uid1", readyCondition(i%2 == 1, now.Add(time.Duration(i-2)*time.Minute)))
+			Expect(fd.IsFlapping("uid1", "Ready", 3, 5*time.Minute)).To(BeFalse(), "should not be flapping after %d transitions", i+1)
+		}
+		fd.Observe("uid1", readyCondition(false, now))
+		Expect(fd.IsFlapping("uid1", "Ready", 3, 5*time.Minute)).To(BeTrue())
+		Expect(fd.IsFlapping("uid1", "Ready", 5, 5*time.Minute)).To(BeFalse())
+	})
+
+	It("should not count observations without status change", func() {
+		fd := conditions.NewFlapDetector(time.Hour)
+		for range 5 {
+			fd.Observe("uid1", []metav1.Condition{{Type: "Ready", Status: metav1.ConditionTrue}})
+		}
+		Expect(fd.IsFlapping("uid1", "Ready", 1, time.Hour)).To(BeFalse())
+	})
+
+	It("should only count transitions within the window", func() {
+		fd := conditions.NewFlapDetector(time.Hour)
+		now := time.Now()
+		fd.Observe("uid1", readyCondition(true, now.Add(-50*time.Minute)))
+		fd.Observe("uid1", readyCondition(false, now.Add(-40*time.Minute)))
+		fd.Observe("uid1", readyCondition(true, now.Add(-30*time.Minute)))
+		fd.Observe("uid1", readyCondition(false, now.Add(-time.Minute)))
+		Expect(fd.IsFlapping("uid1", "Ready", 3, time.Hour)).To(BeTrue())
+		Expect(fd.IsFlapping("uid1", "Ready", 2, 10*time.Minute)).To(BeFalse())
+		Expect(fd.IsFlapping("uid1", "Ready", 1, 10*time.Minute)).To(BeTrue())
+	})
+
+	It("should discard transitions older than the retention", func() {
+		fd := conditions.NewFlapDetector(10 * time.Minute)
+		now := time.Now()
+		fd.Observe("uid1", readyCondition(true, now.Add(-time.Hour)))
+		fd.Observe("uid1", readyCondition(false, now.Add(-50*time.Minute)))
+		fd.Observe("uid1", readyCondition(true, now.Add(-40*time.Minute)))
+		Expect(fd.IsFlapping("uid1", "Ready", 1, 2*time.Hour)).To(BeFalse())
+	})
+
+	It("should track objects and condition types separately", func() {
+		fd := conditions.NewFlapDetector(time.Hour)
+		fd.Observe("uid1", []metav1.Condition{{Type: "Ready", Status: metav1.ConditionTrue}, {Type: "Healthy", Status: metav1.ConditionTrue}})
+		fd.Observe("uid2", []metav1.Condition{{Type: "Ready", Status: metav1.ConditionTrue}})
+		fd.Observe("uid1", []metav1.Condition{{Type: "Ready", Status: metav1.ConditionFalse}, {Type: "Healthy", Status: metav1.ConditionTrue}})
+		Expect(fd.IsFlapping("uid1", "Ready", 1, time.Minute)).To(BeTrue())
+		Expect(fd.IsFlapping("uid1", "Healthy", 1, time.Minute)).To(BeFalse())
+		Expect(fd.IsFlapping("uid2", "Ready", 1, time.Minute)).To(BeFalse())
+		Expect(fd.IsFlapping("uid3", "Ready", 1, time.Minute)).To(BeFalse())
+
+		fd.Forget("uid1")
+		Expect(fd.IsFlapping("uid1", "Ready", 1, time.Minute)).To(BeFalse())
+	})
+
+})
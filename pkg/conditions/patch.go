@@ -0,0 +1,65 @@
+package conditions
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	jpapi "github.com/openmcp-project/controller-utils/api/jsonpatch"
+)
+
+// ConditionPatch returns the JSON patch operations that upsert the given condition in the conditions list at the given path.
+// The path is expected to be a JSON pointer (e.g. '/status/conditions') to an existing list of conditions.
+// Since a JSON patch cannot look up list items by a field value, the currently existing conditions can be passed in.
+// If they contain a condition with the same type, the patch replaces the condition at its index,
+// guarded by a 'test' operation, so that applying the patch fails if the condition has been moved in the meantime.
+// Otherwise, the condition is appended to the list.
+// Returns an error if the condition cannot be marshalled to JSON.
+func ConditionPatch(conditionsPath string, con metav1.Condition, existing ...metav1.Condition) (jpapi.JSONPatches, error) {
+	conValue, err := patchValue(con)
+	if err != nil {
+		return nil, err
+	}
+	conditionsPath = strings.TrimSuffix(conditionsPath, "/")
+	for i, ex := range existing {
+		if ex.Type != con.Type {
+			continue
+		}
+		typeValue, err := patchValue(con.Type)
+		if err != nil {
+			return nil, err
+		}
+		conPath := fmt.Sprintf("%s/%d", conditionsPath, i)
+		return jpapi.JSONPatches{
+			{
+				Op:    jpapi.TEST,
+				Path:  conPath + "/type",
+				Value: typeValue,
+			},
+			{
+				Op:    jpapi.REPLACE,
+				Path:  conPath,
+				Value: conValue,
+			},
+		}, nil
+	}
+	return jpapi.JSONPatches{
+		{
+			Op:    jpapi.ADD,
+			Path:  conditionsPath + "/-",
+			Value: conValue,
+		},
+	}, nil
+}
+
+// patchValue converts the given value into a JSON patch value.
+func patchValue(value any) (*apiextensionsv1.JSON, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling JSON patch value: %w", err)
+	}
+	return &apiextensionsv1.JSON{Raw: data}, nil
+}
@@ -0,0 +1,96 @@
+package conditions_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/openmcp-project/controller-utils/pkg/testing/matchers"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	jpapi "github.com/openmcp-project/controller-utils/api/jsonpatch"
+	"github.com/openmcp-project/controller-utils/pkg/conditions"
+	"github.com/openmcp-project/controller-utils/pkg/jsonpatch"
+)
+
+type patchTestObject struct {
+	Status patchTestStatus `json:"status"`
+}
+
+type patchTestStatus struct {
+	Conditions []metav1.Condition `json:"conditions"`
+}
+
+var _ = Describe("ConditionPatch", func() {
+
+	var obj patchTestObject
+	var now metav1.Time
+
+	BeforeEach(func() {
+		// JSON serialization of metav1.Time has second precision
+		now = metav1.NewTime(time.Now().Truncate(time.Second))
+		obj = patchTestObject{
+			Status: patchTestStatus{
+				Conditions: testConditionSet(),
+			},
+		}
+		for i := range obj.Status.Conditions {
+			obj.Status.Conditions[i].LastTransitionTime = metav1.NewTime(obj.Status.Conditions[i].LastTransitionTime.Truncate(time.Second))
+		}
+	})
+
+	It("should append a condition with a new type", func() {
+		con := TestConditionFromValues("new", conditions.FromBool(true), 0, "newReason", "newMessage", now).ToCondition()
+		patches, err := conditions.ConditionPatch("/status/conditions", con, obj.Status.Conditions...)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(patches).To(HaveLen(1))
+		Expect(patches[0].Op).To(Equal(jpapi.ADD))
+
+		res, err := jsonpatch.NewTyped[patchTestObject](patches...).Apply(obj)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.Status.Conditions).To(HaveLen(len(obj.Status.Conditions) + 1))
+		Expect(res.Status.Conditions[:len(obj.Status.Conditions)]).To(Equal(obj.Status.Conditions))
+		Expect(res.Status.Conditions[len(obj.Status.Conditions)]).To(MatchCondition(TestConditionFromCondition(con)))
+	})
+
+	It("should append the condition if no existing conditions are given", func() {
+		con := TestConditionFromValues("new", conditions.FromBool(true), 0, "newReason", "newMessage", now).ToCondition()
+		patches, err := conditions.ConditionPatch("/status/conditions/", con)
+		Expect(err).ToNot(HaveOccurred())
+
+		res, err := jsonpatch.NewTyped[patchTestObject](patches...).Apply(obj)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.Status.Conditions).To(HaveLen(len(obj.Status.Conditions) + 1))
+		Expect(res.Status.Conditions[len(obj.Status.Conditions)]).To(MatchCondition(TestConditionFromCondition(con)))
+	})
+
+	It("should replace an existing condition with the same type", func() {
+		con := TestConditionFromValues("false", conditions.FromBool(true), 0, "newReason", "newMessage", now).ToCondition()
+		patches, err := conditions.ConditionPatch("/status/conditions", con, obj.Status.Conditions...)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(patches).To(HaveLen(2))
+		Expect(patches[0].Op).To(Equal(jpapi.TEST))
+		Expect(patches[1].Op).To(Equal(jpapi.REPLACE))
+
+		res, err := jsonpatch.NewTyped[patchTestObject](patches...).Apply(obj)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.Status.Conditions).To(HaveLen(len(obj.Status.Conditions)))
+		Expect(res.Status.Conditions[0]).To(Equal(obj.Status.Conditions[0]))
+		Expect(res.Status.Conditions[1]).To(MatchCondition(TestConditionFromCondition(con)))
+		Expect(res.Status.Conditions[2]).To(Equal(obj.Status.Conditions[2]))
+	})
+
+	It("should fail to apply if the existing condition has been moved", func() {
+		con := TestConditionFromValues("false", conditions.FromBool(true), 0, "newReason", "newMessage", now).ToCondition()
+		patches, err := conditions.ConditionPatch("/status/conditions", con, obj.Status.Conditions...)
+		Expect(err).ToNot(HaveOccurred())
+
+		obj.Status.Conditions = obj.Status.Conditions[1:]
+		_, err = jsonpatch.NewTyped[patchTestObject](patches...).Apply(obj)
+		Expect(err).To(HaveOccurred())
+		Expect(jsonpatch.IsTestFailure(err)).To(BeTrue())
+	})
+
+})
@@ -48,6 +48,12 @@ type conditionUpdater struct {
 	eventVerbosity  EventVerbosity
 	updates         map[string]metav1.ConditionStatus
 	removeUntouched bool
+	sortFunc        func(a, b metav1.Condition) int
+	warnOnNegative  bool
+	minTransition   *metav1.Time
+	stampGen        *int64
+	maxConditions   int
+	evict           func([]metav1.Condition) string
 }
 
 // ConditionUpdater creates a builder-like helper struct for updating a list of Conditions.
@@ -87,6 +93,113 @@ func (c *conditionUpdater) WithEventRecorder(recorder events.EventRecorder, verb
 	return c
 }
 
+// WithWarningOnNegative controls the type of the events recorded for condition changes.
+// If enabled, events for conditions that changed to False or Unknown are recorded with type Warning instead of Normal.
+// This only affects the EventPerChange and EventPerNewStatus verbosities, all other events are still recorded with type Normal.
+// Disabled by default.
+func (c *conditionUpdater) WithWarningOnNegative(enabled bool) *conditionUpdater {
+	c.warnOnNegative = enabled
+	return c
+}
+
+// eventTypeForStatus returns the event type that should be used for a condition that reached the given status.
+func (c *conditionUpdater) eventTypeForStatus(status metav1.ConditionStatus) string {
+	if c.warnOnNegative && status != metav1.ConditionTrue {
+		return corev1.EventTypeWarning
+	}
+	return corev1.EventTypeNormal
+}
+
+// WithMonotonicTransitionTime ensures that the LastTransitionTime of changed conditions is never earlier than the latest transition time known so far.
+// This avoids transition times jumping backwards, e.g. if multiple controller instances with slightly different clocks update the same conditions.
+// The lower bound is the later one of previousMax and the latest LastTransitionTime of the conditions the updater was initialized with.
+// If a condition's status changes and the updater's Now is earlier than this bound, the bound is used as LastTransitionTime instead.
+// previousMax may be zero, in which case only the existing conditions are taken into account.
+func (c *conditionUpdater) WithMonotonicTransitionTime(previousMax metav1.Time) *conditionUpdater {
+	bound := previousMax
+	for _, con := range c.original {
+		if bound.Before(&con.LastTransitionTime) {
+			bound = con.LastTransitionTime
+		}
+	}
+	c.minTransition = &bound
+	return c
+}
+
+// WithObservedGeneration causes the ObservedGeneration of all conditions returned by Conditions() to be set to the given generation.
+// This also affects conditions which have not been updated, but are kept because removeUntouched is false.
+// The LastTransitionTime of the conditions is not affected by this, as it only depends on the conditions' status.
+// See also StampObservedGeneration.
+func (c *conditionUpdater) WithObservedGeneration(gen int64) *conditionUpdater {
+	c.stampGen = &gen
+	return c
+}
+
+// WithMaxConditions limits the number of conditions returned by Conditions() to n.
+// If there are more conditions after all updates, the evict function is called repeatedly with the conditions that may be evicted
+// and returns the type of the condition to remove next, until the limit is reached.
+// Only conditions which have not been updated can be evicted, so the limit may still be exceeded if more than n conditions have been updated.
+// Eviction also stops early if the evict function returns a type which is not among the given conditions, e.g. the empty string.
+// If evict is nil, EvictOldest is used. A value of n <= 0 disables the limit.
+// Evicted conditions are treated like removed ones, also regarding recorded events.
+func (c *conditionUpdater) WithMaxConditions(n int, evict func([]metav1.Condition) string) *conditionUpdater {
+	if evict == nil {
+		evict = EvictOldest
+	}
+	c.maxConditions = n
+	c.evict = evict
+	return c
+}
+
+// EvictOldest can be used as evict function for WithMaxConditions.
+// It returns the type of the condition with the oldest LastTransitionTime, with ties being broken by the alphabetical order of the types.
+// Returns the empty string if the given list is empty.
+func EvictOldest(cons []metav1.Condition) string {
+	if len(cons) == 0 {
+		return ""
+	}
+	oldest := slices.MinFunc(cons, func(a, b metav1.Condition) int {
+		if res := a.LastTransitionTime.Compare(b.LastTransitionTime.Time); res != 0 {
+			return res
+		}
+		return strings.Compare(a.Type, b.Type)
+	})
+	return oldest.Type
+}
+
+// WithSortOrder overwrites the comparison function that is used to sort the conditions returned by Conditions().
+// The function must follow the semantics of the comparison functions used by the slices package.
+// If nil, the default order (alphabetically by type) is used.
+func (c *conditionUpdater) WithSortOrder(cmp func(a, b metav1.Condition) int) *conditionUpdater {
+	c.sortFunc = cmp
+	return c
+}
+
+// WithPriorityOrder is a convenience wrapper around WithSortOrder.
+// Conditions with one of the given types are sorted first, in the order in which the types are specified.
+// All other conditions follow afterwards, sorted alphabetically by their type.
+func (c *conditionUpdater) WithPriorityOrder(types ...string) *conditionUpdater {
+	prio := make(map[string]int, len(types))
+	for i, t := range types {
+		if _, ok := prio[t]; !ok {
+			prio[t] = i
+		}
+	}
+	return c.WithSortOrder(func(a, b metav1.Condition) int {
+		aPrio, aOk := prio[a.Type]
+		bPrio, bOk := prio[b.Type]
+		switch {
+		case aOk && bOk:
+			return aPrio - bPrio
+		case aOk:
+			return -1
+		case bOk:
+			return 1
+		}
+		return strings.Compare(a.Type, b.Type)
+	})
+}
+
 // UpdateCondition updates or creates the condition with the specified type.
 // All fields of the condition are updated with the values given in the arguments, but the condition's LastTransitionTime is only updated (with the timestamp contained in the receiver struct) if the status changed.
 // Returns the receiver for easy chaining.
@@ -113,6 +226,15 @@ func (c *conditionUpdater) UpdateConditionFromTemplate(con metav1.Condition) *co
 	return c.UpdateCondition(con.Type, con.Status, con.ObservedGeneration, con.Reason, con.Message)
 }
 
+// UpdateConditionsFromTemplates calls UpdateConditionFromTemplate for each of the given conditions.
+// Returns the receiver for easy chaining.
+func (c *conditionUpdater) UpdateConditionsFromTemplates(cons ...metav1.Condition) *conditionUpdater {
+	for _, con := range cons {
+		c.UpdateConditionFromTemplate(con)
+	}
+	return c
+}
+
 // HasCondition returns true if a condition with the given type exists in the updated condition list.
 func (c *conditionUpdater) HasCondition(conType string) bool {
 	_, ok := c.conditions[conType]
@@ -133,19 +255,29 @@ func (c *conditionUpdater) RemoveCondition(conType string) *conditionUpdater {
 // Conditions returns the updated condition list.
 // If the condition updater was initialized with removeUntouched=true, this list will only contain the conditions which have been updated
 // in between the condition updater creation and this method call. Otherwise, it will potentially also contain old conditions.
-// The conditions are returned sorted by their type.
+// The conditions are returned sorted by their type, unless a different order has been configured via WithSortOrder or WithPriorityOrder.
 // The second return value indicates whether the condition list has actually changed.
 func (c *conditionUpdater) Conditions() ([]metav1.Condition, bool) {
 	res := collections.ProjectSliceToSlice(c.updatedConditions(), func(con metav1.Condition) metav1.Condition {
 		if c.original[con.Type].Status == con.Status {
 			// if the status has not changed, reset the LastTransitionTime to the original value
 			con.LastTransitionTime = c.original[con.Type].LastTransitionTime
+		} else if c.minTransition != nil && con.LastTransitionTime.Before(c.minTransition) {
+			// the status has changed, but the transition time would be earlier than an already known one
+			con.LastTransitionTime = *c.minTransition
 		}
 		return con
 	})
-	slices.SortStableFunc(res, func(a, b metav1.Condition) int {
-		return strings.Compare(a.Type, b.Type)
-	})
+	if c.stampGen != nil {
+		res = StampObservedGeneration(res, *c.stampGen)
+	}
+	cmp := c.sortFunc
+	if cmp == nil {
+		cmp = func(a, b metav1.Condition) int {
+			return strings.Compare(a.Type, b.Type)
+		}
+	}
+	slices.SortStableFunc(res, cmp)
 	return res, c.changed(res)
 }
 
@@ -156,7 +288,28 @@ func (c *conditionUpdater) updatedConditions() []metav1.Condition {
 			res = append(res, con)
 		}
 	}
-	return res
+	return c.evictExceeding(res)
+}
+
+// evictExceeding removes untouched conditions from the given list until it does not exceed the configured maximum anymore.
+func (c *conditionUpdater) evictExceeding(cons []metav1.Condition) []metav1.Condition {
+	if c.maxConditions <= 0 || len(cons) <= c.maxConditions {
+		return cons
+	}
+	_, candidates := collections.Partition(cons, func(con metav1.Condition) bool {
+		_, updated := c.updates[con.Type]
+		return updated
+	})
+	for len(cons) > c.maxConditions && len(candidates) > 0 {
+		conType := c.evict(slices.Clone(candidates))
+		idx := slices.IndexFunc(candidates, func(con metav1.Condition) bool { return con.Type == conType })
+		if idx < 0 {
+			break
+		}
+		candidates = slices.Delete(candidates, idx, idx+1)
+		cons = slices.DeleteFunc(cons, func(con metav1.Condition) bool { return con.Type == conType })
+	}
+	return cons
 }
 
 func (c *conditionUpdater) changed(newCons []metav1.Condition) bool {
@@ -200,11 +353,11 @@ func (c *conditionUpdater) Record(obj runtime.Object) *conditionUpdater {
 		for _, con := range updatedCons {
 			oldCon, found := c.original[con.Type]
 			if !found {
-				c.eventRecoder.Eventf(obj, nil, corev1.EventTypeNormal, EventReasonConditionChanged, EventActionUpdateStatus, "Condition '%s' added with status '%s'", con.Type, con.Status)
+				c.eventRecoder.Eventf(obj, nil, c.eventTypeForStatus(con.Status), EventReasonConditionChanged, EventActionUpdateStatus, "Condition '%s' added with status '%s'", con.Type, con.Status)
 				continue
 			}
 			if con.Status != oldCon.Status {
-				c.eventRecoder.Eventf(obj, nil, corev1.EventTypeNormal, EventReasonConditionChanged, EventActionUpdateStatus, "Condition '%s' changed from '%s' to '%s'", con.Type, oldCon.Status, con.Status)
+				c.eventRecoder.Eventf(obj, nil, c.eventTypeForStatus(con.Status), EventReasonConditionChanged, EventActionUpdateStatus, "Condition '%s' changed from '%s' to '%s'", con.Type, oldCon.Status, con.Status)
 				continue
 			}
 		}
@@ -236,10 +389,10 @@ func (c *conditionUpdater) Record(obj runtime.Object) *conditionUpdater {
 			c.eventRecoder.Eventf(obj, nil, corev1.EventTypeNormal, EventReasonConditionChanged, EventActionUpdateStatus, "The following conditions changed to 'True': %s", strings.Join(sets.List(trueCons), ", "))
 		}
 		if falseCons.Len() > 0 {
-			c.eventRecoder.Eventf(obj, nil, corev1.EventTypeNormal, EventReasonConditionChanged, EventActionUpdateStatus, "The following conditions changed to 'False': %s", strings.Join(sets.List(falseCons), ", "))
+			c.eventRecoder.Eventf(obj, nil, c.eventTypeForStatus(metav1.ConditionFalse), EventReasonConditionChanged, EventActionUpdateStatus, "The following conditions changed to 'False': %s", strings.Join(sets.List(falseCons), ", "))
 		}
 		if unknownCons.Len() > 0 {
-			c.eventRecoder.Eventf(obj, nil, corev1.EventTypeNormal, EventReasonConditionChanged, EventActionUpdateStatus, "The following conditions changed to 'Unknown': %s", strings.Join(sets.List(unknownCons), ", "))
+			c.eventRecoder.Eventf(obj, nil, c.eventTypeForStatus(metav1.ConditionUnknown), EventReasonConditionChanged, EventActionUpdateStatus, "The following conditions changed to 'Unknown': %s", strings.Join(sets.List(unknownCons), ", "))
 		}
 		if len(lostCons) > 0 {
 			c.eventRecoder.Eventf(obj, nil, corev1.EventTypeNormal, EventReasonConditionChanged, EventActionUpdateStatus, "The following conditions were removed: %s", strings.Join(sets.List(sets.KeySet(lostCons)), ", "))
@@ -1,6 +1,7 @@
 package conditions_test
 
 import (
+	"errors"
 	"slices"
 	"strings"
 	"time"
@@ -10,10 +11,13 @@ import (
 
 	. "github.com/openmcp-project/controller-utils/pkg/testing/matchers"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
 
+	"github.com/openmcp-project/controller-utils/pkg/collections"
 	"github.com/openmcp-project/controller-utils/pkg/conditions"
 )
 
@@ -85,6 +89,193 @@ var _ = Describe("Conditions", func() {
 
 	})
 
+	Context("GetConditionCopy", func() {
+
+		It("should return a copy of the requested condition", func() {
+			cons := testConditionSet()
+
+			con, ok := conditions.GetConditionCopy(cons, "false")
+			Expect(ok).To(BeTrue())
+			Expect(con).To(Equal(cons[1]))
+
+			_, ok = conditions.GetConditionCopy(cons, "doesNotExist")
+			Expect(ok).To(BeFalse())
+		})
+
+		It("should return a copy that is independent of the source slice", func() {
+			cons := testConditionSet()
+
+			con, ok := conditions.GetConditionCopy(cons, "true")
+			Expect(ok).To(BeTrue())
+			con.Status = metav1.ConditionFalse
+			con.Reason = "changedReason"
+			Expect(cons[0].Status).To(Equal(metav1.ConditionTrue))
+			Expect(cons[0].Reason).To(Equal("reason"))
+
+			cons[0].Message = "changedMessage"
+			Expect(con.Message).To(Equal("message"))
+		})
+
+	})
+
+	Context("EnsureTypes", func() {
+
+		It("should report missing condition types", func() {
+			missing, extra := conditions.EnsureTypes(testConditionSet(), "true", "false", "alsoTrue", "missing", "alsoMissing", "missing")
+			Expect(missing).To(Equal([]string{"missing", "alsoMissing"}))
+			Expect(extra).To(BeEmpty())
+		})
+
+		It("should report unexpected condition types", func() {
+			cons := append(testConditionSet(), TestConditionFromValues("true", metav1.ConditionTrue, 0, "reason", "message", metav1.Now()).ToCondition())
+			missing, extra := conditions.EnsureTypes(cons, "false")
+			Expect(missing).To(BeEmpty())
+			Expect(extra).To(Equal([]string{"true", "alsoTrue"}))
+		})
+
+		It("should report nothing if the condition types match exactly", func() {
+			missing, extra := conditions.EnsureTypes(testConditionSet(), "alsoTrue", "true", "false")
+			Expect(missing).To(BeNil())
+			Expect(extra).To(BeNil())
+
+			missing, extra = conditions.EnsureTypes(nil)
+			Expect(missing).To(BeNil())
+			Expect(extra).To(BeNil())
+		})
+
+	})
+
+	Context("GetConditionStatus and IsConditionTrue", func() {
+
+		It("should return the status of existing conditions", func() {
+			cons := append(testConditionSet(), TestConditionFromValues("unknown", metav1.ConditionUnknown, 0, "reason", "message", metav1.Now()).ToCondition())
+
+			status, ok := conditions.GetConditionStatus(cons, "true")
+			Expect(ok).To(BeTrue())
+			Expect(status).To(Equal(metav1.ConditionTrue))
+			Expect(conditions.IsConditionTrue(cons, "true")).To(BeTrue())
+
+			status, ok = conditions.GetConditionStatus(cons, "false")
+			Expect(ok).To(BeTrue())
+			Expect(status).To(Equal(metav1.ConditionFalse))
+			Expect(conditions.IsConditionTrue(cons, "false")).To(BeFalse())
+
+			status, ok = conditions.GetConditionStatus(cons, "unknown")
+			Expect(ok).To(BeTrue())
+			Expect(status).To(Equal(metav1.ConditionUnknown))
+			Expect(conditions.IsConditionTrue(cons, "unknown")).To(BeFalse())
+		})
+
+		It("should handle missing conditions", func() {
+			cons := testConditionSet()
+
+			status, ok := conditions.GetConditionStatus(cons, "doesNotExist")
+			Expect(ok).To(BeFalse())
+			Expect(status).To(BeEmpty())
+			Expect(conditions.IsConditionTrue(cons, "doesNotExist")).To(BeFalse())
+			Expect(conditions.IsConditionTrue(nil, "true")).To(BeFalse())
+		})
+
+	})
+
+	Context("ImportConditions", func() {
+
+		It("should prefix the condition types and preserve all other fields", func() {
+			cons := testConditionSet()
+			imported := conditions.ImportConditions(cons, "child")
+			Expect(imported).To(HaveLen(len(cons)))
+			for i, con := range cons {
+				Expect(imported[i].Type).To(Equal("child/" + con.Type))
+				Expect(imported[i].Status).To(Equal(con.Status))
+				Expect(imported[i].ObservedGeneration).To(Equal(con.ObservedGeneration))
+				Expect(imported[i].Reason).To(Equal(con.Reason))
+				Expect(imported[i].Message).To(Equal(con.Message))
+				Expect(imported[i].LastTransitionTime).To(Equal(con.LastTransitionTime))
+			}
+			// the original conditions must not be modified
+			Expect(cons[0].Type).To(Equal("true"))
+		})
+
+		It("should not modify the types if the prefix is empty", func() {
+			cons := testConditionSet()
+			Expect(conditions.ImportConditions(cons, "")).To(Equal(cons))
+		})
+
+	})
+
+	Context("FromBool, FromBoolPointer, and FromError", func() {
+
+		It("should map bools to True and False", func() {
+			Expect(conditions.FromBool(true)).To(Equal(metav1.ConditionTrue))
+			Expect(conditions.FromBool(false)).To(Equal(metav1.ConditionFalse))
+		})
+
+		It("should map bool pointers to True, False, and Unknown", func() {
+			Expect(conditions.FromBoolPointer(ptr.To(true))).To(Equal(metav1.ConditionTrue))
+			Expect(conditions.FromBoolPointer(ptr.To(false))).To(Equal(metav1.ConditionFalse))
+			Expect(conditions.FromBoolPointer(nil)).To(Equal(metav1.ConditionUnknown))
+		})
+
+		It("should map errors to True and False", func() {
+			Expect(conditions.FromError(nil)).To(Equal(metav1.ConditionTrue))
+			Expect(conditions.FromError(errors.New("error"))).To(Equal(metav1.ConditionFalse))
+		})
+
+	})
+
+	Context("StampObservedGeneration", func() {
+
+		It("should set the observed generation on all conditions and preserve all other fields", func() {
+			cons := testConditionSet()
+			original := conditions.ImportConditions(cons, "")
+			stamped := conditions.StampObservedGeneration(cons, 7)
+			Expect(stamped).To(HaveLen(len(cons)))
+			for i, con := range stamped {
+				Expect(con.ObservedGeneration).To(BeEquivalentTo(7))
+				expected := cons[i]
+				expected.ObservedGeneration = 7
+				Expect(con).To(Equal(expected))
+			}
+			// the original conditions must not be modified
+			Expect(cons).To(Equal(original))
+			Expect(conditions.StampObservedGeneration(nil, 7)).To(BeNil())
+		})
+
+	})
+
+	Context("AggregateReady", func() {
+
+		It("should return True if all conditions are True", func() {
+			cons, _ := conditions.ConditionUpdater(testConditionSet(), false).UpdateCondition("false", metav1.ConditionTrue, 0, "reason", "message").UpdateCondition("Ready", metav1.ConditionFalse, 0, "reason", "message").Conditions()
+			ready := conditions.AggregateReady(cons, "Ready")
+			Expect(ready.Type).To(Equal("Ready"))
+			Expect(ready.Status).To(Equal(metav1.ConditionTrue))
+			Expect(ready.Reason).To(Equal(conditions.ReasonAllConditionsTrue))
+			Expect(conditions.AggregateReady(nil, "Ready").Status).To(Equal(metav1.ConditionTrue))
+		})
+
+		It("should return False if one condition is False", func() {
+			ready := conditions.AggregateReady(testConditionSet(), "Ready")
+			Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+			Expect(ready.Reason).To(Equal(conditions.ReasonConditionsNotTrue))
+			Expect(ready.Message).To(Equal("The following conditions are not True: false (False)"))
+		})
+
+		It("should return Unknown if one condition is Unknown", func() {
+			cons, _ := conditions.ConditionUpdater(testConditionSet(), false).UpdateCondition("unknown", metav1.ConditionUnknown, 0, "reason", "message").Conditions()
+			ready := conditions.AggregateReady(cons, "Ready")
+			Expect(ready.Status).To(Equal(metav1.ConditionUnknown))
+			Expect(ready.Reason).To(Equal(conditions.ReasonConditionsUnknown))
+			Expect(ready.Message).To(Equal("The following conditions are not True: false (False), unknown (Unknown)"))
+		})
+
+		It("should ignore the given condition types", func() {
+			ready := conditions.AggregateReady(testConditionSet(), "Ready", "false")
+			Expect(ready.Status).To(Equal(metav1.ConditionTrue))
+		})
+
+	})
+
 	Context("ConditionUpdater", func() {
 
 		It("should update the condition (same value, keep other cons)", func() {
@@ -171,6 +362,33 @@ var _ = Describe("Conditions", func() {
 			Expect(updated).To(ConsistOf(cons))
 		})
 
+		It("should import multiple conditions from templates", func() {
+			cons := testConditionSet()
+			oldCons := []metav1.Condition{
+				TestConditionFromValues("true", conditions.FromBool(true), 0, "reason", "message", metav1.NewTime(time.Now().Add((-48)*time.Hour))).ToCondition(),
+				TestConditionFromValues("false", conditions.FromBool(true), 0, "reason", "message", metav1.NewTime(time.Now().Add((-48)*time.Hour))).ToCondition(),
+				TestConditionFromValues("obsolete", conditions.FromBool(true), 0, "reason", "message", metav1.NewTime(time.Now().Add((-48)*time.Hour))).ToCondition(),
+			}
+			updater := conditions.ConditionUpdater(oldCons, true)
+			updated, changed := updater.UpdateConditionsFromTemplates(cons...).Conditions()
+			Expect(changed).To(BeTrue())
+			Expect(updated).To(HaveLen(len(cons)))
+			Expect(conditions.GetCondition(updated, "obsolete")).To(BeNil())
+			for _, con := range cons {
+				newCon := conditions.GetCondition(updated, con.Type)
+				Expect(newCon).ToNot(BeNil())
+				Expect(newCon.Status).To(Equal(con.Status))
+				Expect(newCon.Reason).To(Equal(con.Reason))
+				Expect(newCon.Message).To(Equal(con.Message))
+				oldCon := conditions.GetCondition(oldCons, con.Type)
+				if oldCon != nil && oldCon.Status == con.Status {
+					Expect(newCon.LastTransitionTime).To(Equal(oldCon.LastTransitionTime))
+				} else {
+					Expect(newCon.LastTransitionTime).To(Equal(updater.Now))
+				}
+			}
+		})
+
 		It("should sort the conditions by type", func() {
 			cons := []metav1.Condition{
 				TestConditionFromValues("c", conditions.FromBool(true), 0, "reason", "message", metav1.Now()).ToCondition(),
@@ -189,6 +407,41 @@ var _ = Describe("Conditions", func() {
 			Expect(slices.IsSortedFunc(updated, compareConditions)).To(BeTrue(), "conditions are not sorted")
 		})
 
+		It("should sort the conditions according to a custom sort order", func() {
+			cons := testConditionSet()
+			reverse := func(a, b metav1.Condition) int {
+				return strings.Compare(b.Type, a.Type)
+			}
+			updated, changed := conditions.ConditionUpdater(cons, false).WithSortOrder(reverse).Conditions()
+			Expect(changed).To(BeFalse())
+			Expect(updated).To(HaveLen(len(cons)))
+			Expect(slices.IsSortedFunc(updated, reverse)).To(BeTrue(), "conditions are not sorted in reverse order")
+
+			updated, changed = conditions.ConditionUpdater(cons, false).WithSortOrder(reverse).UpdateCondition("true", metav1.ConditionFalse, 1, "newReason", "newMessage").Conditions()
+			Expect(changed).To(BeTrue())
+			Expect(slices.IsSortedFunc(updated, reverse)).To(BeTrue(), "conditions are not sorted in reverse order")
+		})
+
+		It("should sort prioritized conditions first and the remaining ones alphabetically", func() {
+			cons := []metav1.Condition{
+				TestConditionFromValues("c", conditions.FromBool(true), 0, "reason", "message", metav1.Now()).ToCondition(),
+				TestConditionFromValues("Ready", conditions.FromBool(true), 0, "reason", "message", metav1.Now()).ToCondition(),
+				TestConditionFromValues("a", conditions.FromBool(true), 0, "reason", "message", metav1.Now()).ToCondition(),
+				TestConditionFromValues("z", conditions.FromBool(true), 0, "reason", "message", metav1.Now()).ToCondition(),
+				TestConditionFromValues("b", conditions.FromBool(true), 0, "reason", "message", metav1.Now()).ToCondition(),
+			}
+			updated, changed := conditions.ConditionUpdater(cons, false).WithPriorityOrder("Ready", "z", "doesNotExist").Conditions()
+			Expect(changed).To(BeFalse())
+			Expect(collections.ProjectSliceToSlice(updated, func(con metav1.Condition) string { return con.Type })).To(Equal([]string{"Ready", "z", "a", "b", "c"}))
+
+			updated, changed = conditions.ConditionUpdater(cons, true).WithPriorityOrder("Ready", "z").
+				UpdateCondition("a", metav1.ConditionTrue, 0, "reason", "message").
+				UpdateCondition("z", metav1.ConditionFalse, 0, "reason", "message").
+				Conditions()
+			Expect(changed).To(BeTrue())
+			Expect(collections.ProjectSliceToSlice(updated, func(con metav1.Condition) string { return con.Type })).To(Equal([]string{"z", "a"}))
+		})
+
 		It("should remove a condition", func() {
 			cons := testConditionSet()
 			updated, changed := conditions.ConditionUpdater(cons, false).RemoveCondition("true").Conditions()
@@ -243,6 +496,110 @@ var _ = Describe("Conditions", func() {
 			))
 		})
 
+		It("should never set a transition time earlier than the latest existing one, if configured", func() {
+			cons := testConditionSet()
+			now := metav1.NewTime(time.Now().Truncate(time.Second))
+			later := metav1.NewTime(now.Add(time.Minute))
+			cons[0].LastTransitionTime = later
+			updater := conditions.ConditionUpdater(cons, false).WithMonotonicTransitionTime(metav1.Time{})
+			updater.Now = now
+			updated, changed := updater.UpdateCondition("false", metav1.ConditionTrue, 0, "reason", "message").UpdateCondition("new", metav1.ConditionTrue, 0, "reason", "message").Conditions()
+			Expect(changed).To(BeTrue())
+			Expect(conditions.GetCondition(updated, "false").LastTransitionTime).To(Equal(later))
+			Expect(conditions.GetCondition(updated, "new").LastTransitionTime).To(Equal(later))
+			// conditions whose status did not change keep their transition time
+			Expect(conditions.GetCondition(updated, "alsoTrue").LastTransitionTime).To(Equal(cons[2].LastTransitionTime))
+
+			By("without the option, the current time is used")
+			updater = conditions.ConditionUpdater(cons, false)
+			updater.Now = now
+			updated, _ = updater.UpdateCondition("false", metav1.ConditionTrue, 0, "reason", "message").Conditions()
+			Expect(conditions.GetCondition(updated, "false").LastTransitionTime).To(Equal(now))
+		})
+
+		It("should respect the given previous maximum transition time", func() {
+			cons := testConditionSet()
+			now := metav1.NewTime(time.Now().Truncate(time.Second))
+			previousMax := metav1.NewTime(now.Add(time.Hour))
+			updater := conditions.ConditionUpdater(cons, false).WithMonotonicTransitionTime(previousMax)
+			updater.Now = now
+			updated, _ := updater.UpdateCondition("false", metav1.ConditionTrue, 0, "reason", "message").Conditions()
+			Expect(conditions.GetCondition(updated, "false").LastTransitionTime).To(Equal(previousMax))
+
+			By("the current time is used if it is later than all known transition times")
+			updater = conditions.ConditionUpdater(cons, false).WithMonotonicTransitionTime(metav1.NewTime(now.Add(-time.Hour)))
+			updater.Now = now
+			updated, _ = updater.UpdateCondition("false", metav1.ConditionTrue, 0, "reason", "message").Conditions()
+			Expect(conditions.GetCondition(updated, "false").LastTransitionTime).To(Equal(now))
+		})
+
+		It("should set the observed generation on all conditions, if configured", func() {
+			cons := testConditionSet()
+			now := metav1.NewTime(time.Now().Truncate(time.Second))
+			updater := conditions.ConditionUpdater(cons, false).WithObservedGeneration(3)
+			updater.Now = now
+			updated, changed := updater.UpdateCondition("true", metav1.ConditionTrue, 1, "reason", "message").UpdateCondition("false", metav1.ConditionTrue, 1, "reason", "message").Conditions()
+			Expect(changed).To(BeTrue())
+			Expect(updated).To(HaveLen(len(cons)))
+			for _, con := range updated {
+				Expect(con.ObservedGeneration).To(BeEquivalentTo(3))
+			}
+			// transition times are not affected
+			Expect(conditions.GetCondition(updated, "true").LastTransitionTime).To(Equal(conditions.GetCondition(cons, "true").LastTransitionTime))
+			Expect(conditions.GetCondition(updated, "alsoTrue").LastTransitionTime).To(Equal(conditions.GetCondition(cons, "alsoTrue").LastTransitionTime))
+			Expect(conditions.GetCondition(updated, "false").LastTransitionTime).To(Equal(now))
+		})
+
+		It("should evict the oldest untouched conditions if the maximum number of conditions is exceeded", func() {
+			now := time.Now().Truncate(time.Second)
+			cons := []metav1.Condition{
+				TestConditionFromValues("oldest", metav1.ConditionTrue, 0, "reason", "message", metav1.NewTime(now.Add(-3*time.Hour))).ToCondition(),
+				TestConditionFromValues("old", metav1.ConditionTrue, 0, "reason", "message", metav1.NewTime(now.Add(-2*time.Hour))).ToCondition(),
+				TestConditionFromValues("recent", metav1.ConditionTrue, 0, "reason", "message", metav1.NewTime(now.Add(-time.Hour))).ToCondition(),
+				TestConditionFromValues("touched", metav1.ConditionTrue, 0, "reason", "message", metav1.NewTime(now.Add(-4*time.Hour))).ToCondition(),
+			}
+			updated, changed := conditions.ConditionUpdater(cons, false).WithMaxConditions(3, nil).
+				UpdateCondition("touched", metav1.ConditionTrue, 0, "reason", "message").
+				UpdateCondition("new", metav1.ConditionTrue, 0, "reason", "message").
+				Conditions()
+			Expect(changed).To(BeTrue())
+			Expect(collections.ProjectSliceToSlice(updated, func(con metav1.Condition) string { return con.Type })).To(ConsistOf("touched", "new", "recent"))
+
+			By("touched conditions are preserved even if they exceed the limit")
+			updated, _ = conditions.ConditionUpdater(cons, false).WithMaxConditions(1, nil).
+				UpdateCondition("touched", metav1.ConditionTrue, 0, "reason", "message").
+				UpdateCondition("new", metav1.ConditionTrue, 0, "reason", "message").
+				Conditions()
+			Expect(collections.ProjectSliceToSlice(updated, func(con metav1.Condition) string { return con.Type })).To(ConsistOf("touched", "new"))
+
+			By("the limit has no effect if it is not exceeded")
+			updated, changed = conditions.ConditionUpdater(cons, false).WithMaxConditions(4, nil).Conditions()
+			Expect(changed).To(BeFalse())
+			Expect(updated).To(HaveLen(4))
+		})
+
+		It("should use the given evict function", func() {
+			cons := testConditionSet()
+			var evictCandidates [][]string
+			evictAlphabetically := func(cons []metav1.Condition) string {
+				types := collections.ProjectSliceToSlice(cons, func(con metav1.Condition) string { return con.Type })
+				evictCandidates = append(evictCandidates, types)
+				return slices.Min(types)
+			}
+			updated, _ := conditions.ConditionUpdater(cons, false).WithMaxConditions(1, evictAlphabetically).
+				UpdateCondition("true", metav1.ConditionTrue, 0, "reason", "message").
+				Conditions()
+			Expect(updated).To(HaveLen(1))
+			Expect(updated[0].Type).To(Equal("true"))
+			Expect(evictCandidates).To(HaveLen(2))
+			Expect(evictCandidates[0]).To(ConsistOf("false", "alsoTrue"))
+			Expect(evictCandidates[1]).To(ConsistOf("false"))
+
+			By("eviction stops if the evict function returns an unknown type")
+			updated, _ = conditions.ConditionUpdater(cons, false).WithMaxConditions(1, func(_ []metav1.Condition) string { return "" }).Conditions()
+			Expect(updated).To(HaveLen(3))
+		})
+
 	})
 
 	Context("EventRecorder", func() {
@@ -279,8 +636,44 @@ var _ = Describe("Conditions", func() {
 
 				events := flush(recorder.Events)
 				Expect(events).To(ConsistOf(
-					ContainSubstring("Condition '%s' changed from '%s' to '%s'", trueCon1.Type, trueCon1.Status, invert(trueCon1.Status)),
-					ContainSubstring("Condition '%s' changed from '%s' to '%s'", trueCon2.Type, trueCon2.Status, invert(trueCon2.Status)),
+					And(HavePrefix(corev1.EventTypeNormal), ContainSubstring("Condition '%s' changed from '%s' to '%s'", trueCon1.Type, trueCon1.Status, invert(trueCon1.Status))),
+					And(HavePrefix(corev1.EventTypeNormal), ContainSubstring("Condition '%s' changed from '%s' to '%s'", trueCon2.Type, trueCon2.Status, invert(trueCon2.Status))),
+				))
+			})
+
+			It("should record warning events for conditions that changed to False or Unknown, if configured", func() {
+				cons := testConditionSet()
+				updater := conditions.ConditionUpdater(cons, false).WithEventRecorder(recorder, conditions.EventPerChange).WithWarningOnNegative(true)
+				trueCon := conditions.GetCondition(cons, "true")
+				falseCon := conditions.GetCondition(cons, "false")
+				_, changed := updater.
+					UpdateCondition(trueCon.Type, invert(trueCon.Status), trueCon.ObservedGeneration+1, "newReason", "newMessage").
+					UpdateCondition(falseCon.Type, invert(falseCon.Status), falseCon.ObservedGeneration+1, "newReason", "newMessage").
+					UpdateCondition("new", metav1.ConditionUnknown, 1, "newReason", "newMessage").
+					Record(dummy).Conditions()
+				Expect(changed).To(BeTrue())
+
+				events := flush(recorder.Events)
+				Expect(events).To(ConsistOf(
+					And(HavePrefix(corev1.EventTypeWarning), ContainSubstring("Condition '%s' changed from '%s' to '%s'", trueCon.Type, metav1.ConditionTrue, metav1.ConditionFalse)),
+					And(HavePrefix(corev1.EventTypeNormal), ContainSubstring("Condition '%s' changed from '%s' to '%s'", falseCon.Type, metav1.ConditionFalse, metav1.ConditionTrue)),
+					And(HavePrefix(corev1.EventTypeWarning), ContainSubstring("Condition 'new' added with status '%s'", metav1.ConditionUnknown)),
+				))
+			})
+
+			It("should record the same events for bulk-imported conditions", func() {
+				cons := testConditionSet()
+				updater := conditions.ConditionUpdater(cons, false).WithEventRecorder(recorder, conditions.EventPerChange)
+				trueCon := *conditions.GetCondition(cons, "true")
+				trueCon.Status = invert(trueCon.Status)
+				newCon := TestConditionFromValues("new", metav1.ConditionUnknown, 1, "newReason", "newMessage", metav1.Now()).ToCondition()
+				_, changed := updater.UpdateConditionsFromTemplates(trueCon, newCon).Record(dummy).Conditions()
+				Expect(changed).To(BeTrue())
+
+				events := flush(recorder.Events)
+				Expect(events).To(ConsistOf(
+					ContainSubstring("Condition 'true' changed from '%s' to '%s'", metav1.ConditionTrue, metav1.ConditionFalse),
+					ContainSubstring("Condition 'new' added with status '%s'", metav1.ConditionUnknown),
 				))
 			})
 
@@ -348,6 +741,26 @@ var _ = Describe("Conditions", func() {
 				))
 			})
 
+			It("should record warning events for conditions that changed to False or Unknown, if configured", func() {
+				cons := testConditionSet()
+				updater := conditions.ConditionUpdater(cons, false).WithEventRecorder(recorder, conditions.EventPerNewStatus).WithWarningOnNegative(true)
+				trueCon := conditions.GetCondition(cons, "true")
+				falseCon := conditions.GetCondition(cons, "false")
+				_, changed := updater.
+					UpdateCondition(trueCon.Type, invert(trueCon.Status), trueCon.ObservedGeneration+1, "newReason", "newMessage").
+					UpdateCondition(falseCon.Type, invert(falseCon.Status), falseCon.ObservedGeneration+1, "newReason", "newMessage").
+					UpdateCondition("new", metav1.ConditionUnknown, 1, "newReason", "newMessage").
+					Record(dummy).Conditions()
+				Expect(changed).To(BeTrue())
+
+				events := flush(recorder.Events)
+				Expect(events).To(ConsistOf(
+					And(HavePrefix(corev1.EventTypeWarning), ContainSubstring("The following conditions changed to '%s': %s", metav1.ConditionFalse, trueCon.Type)),
+					And(HavePrefix(corev1.EventTypeNormal), ContainSubstring("The following conditions changed to '%s': %s", metav1.ConditionTrue, falseCon.Type)),
+					And(HavePrefix(corev1.EventTypeWarning), ContainSubstring("The following conditions changed to '%s': new", metav1.ConditionUnknown)),
+				))
+			})
+
 			It("should not record any events if no condition status changed, even if other fields changed", func() {
 				cons := testConditionSet()
 				updater := conditions.ConditionUpdater(cons, false).WithEventRecorder(recorder, conditions.EventPerNewStatus)
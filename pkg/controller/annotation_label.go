@@ -2,7 +2,12 @@ package controller
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -102,55 +107,229 @@ func EnsureLabel(ctx context.Context, c client.Client, obj client.Object, labelK
 	return ensureMetadataEntry(LABEL, ctx, c, obj, labelKey, labelValue, patch, mode...)
 }
 
+// ErrMetadataEntryUpdateSkipped is returned by EnsureAnnotationIf and EnsureLabelIf if a change would be required, but the guard prevented it.
+var ErrMetadataEntryUpdateSkipped = errors.New("metadata entry update skipped because the guard condition is not fulfilled")
+
+// EnsureAnnotationIf works like EnsureAnnotation, but only modifies the object if the given guard returns true for it.
+// The guard is only evaluated if a change is actually required. If it returns false, ErrMetadataEntryUpdateSkipped is returned and the object is not modified.
+// This can be used to e.g. only update an annotation if the object's observedGeneration matches its generation, to avoid clobbering during in-flight spec changes.
+// A nil guard behaves as if it always returned true.
+func EnsureAnnotationIf(ctx context.Context, c client.Client, obj client.Object, annKey, annValue string, patch bool, guard func(obj client.Object) bool, mode ...ModifyMetadataEntryMode) error {
+	return ensureMetadataEntriesGuarded(ANNOTATION, ctx, c, obj, map[string]string{annKey: annValue}, patch, guard, mode...)
+}
+
+// EnsureLabelIf works like EnsureLabel, but only modifies the object if the given guard returns true for it.
+// See EnsureAnnotationIf for details.
+func EnsureLabelIf(ctx context.Context, c client.Client, obj client.Object, labelKey, labelValue string, patch bool, guard func(obj client.Object) bool, mode ...ModifyMetadataEntryMode) error {
+	return ensureMetadataEntriesGuarded(LABEL, ctx, c, obj, map[string]string{labelKey: labelValue}, patch, guard, mode...)
+}
+
+// EnsureAnnotationCAS sets the given annotation to newValue, but only if its current value on the in-memory object equals expectedOld (compare-and-set).
+// A missing annotation is treated as having the empty string as value, so pass an empty expectedOld to only set the annotation if it doesn't exist yet.
+// If the current value differs from expectedOld, a MetadataEntryAlreadyExistsError is returned and the object is not modified.
+// If patch is set to true, the object will be patched in the cluster immediately, otherwise only the in-memory object is modified. client may be nil when patch is false.
+// The patch contains the object's resourceVersion, so it fails with a conflict error if the object has been modified in the cluster in the meantime.
+func EnsureAnnotationCAS(ctx context.Context, c client.Client, obj client.Object, annKey, expectedOld, newValue string, patch bool) error {
+	return ensureMetadataEntryCAS(ANNOTATION, ctx, c, obj, annKey, expectedOld, newValue, patch)
+}
+
+// EnsureLabelCAS sets the given label to newValue, but only if its current value on the in-memory object equals expectedOld (compare-and-set).
+// A missing label is treated as having the empty string as value, so pass an empty expectedOld to only set the label if it doesn't exist yet.
+// If the current value differs from expectedOld, a MetadataEntryAlreadyExistsError is returned and the object is not modified.
+// If patch is set to true, the object will be patched in the cluster immediately, otherwise only the in-memory object is modified. client may be nil when patch is false.
+// The patch contains the object's resourceVersion, so it fails with a conflict error if the object has been modified in the cluster in the meantime.
+func EnsureLabelCAS(ctx context.Context, c client.Client, obj client.Object, labelKey, expectedOld, newValue string, patch bool) error {
+	return ensureMetadataEntryCAS(LABEL, ctx, c, obj, labelKey, expectedOld, newValue, patch)
+}
+
+// ensureMetadataEntryCAS is the common base method for EnsureAnnotationCAS and EnsureLabelCAS.
+func ensureMetadataEntryCAS(mType metadataEntryType, ctx context.Context, c client.Client, obj client.Object, key, expectedOld, newValue string, patch bool) error {
+	val, _ := getMetadataEntry(mType, obj, key)
+	if val != expectedOld {
+		return NewMetadataEntryAlreadyExistsError(mType, key, newValue, val)
+	}
+	return ensureMetadataEntry(mType, ctx, c, obj, key, newValue, patch, OVERWRITE, CAS)
+}
+
+// EnsureAnnotations is the plural version of EnsureAnnotation. It ensures that all given annotations have the desired state on the object.
+// The same rules as for EnsureAnnotation apply to each entry. The entries are checked before anything is modified,
+// so if any annotation already exists with a different value (and mode OVERWRITE is not set), a MetadataEntryAlreadyExistsError is returned and the object is not modified.
+// To remove annotations, set mode to DELETE. The values of the given entries do not matter in this case.
+// If patch is set to true, all changes are sent to the cluster via a single patch. client may be nil when patch is false.
+func EnsureAnnotations(ctx context.Context, c client.Client, obj client.Object, entries map[string]string, patch bool, mode ...ModifyMetadataEntryMode) error {
+	return ensureMetadataEntries(ANNOTATION, ctx, c, obj, entries, patch, mode...)
+}
+
+// EnsureLabels is the plural version of EnsureLabel. It ensures that all given labels have the desired state on the object.
+// The same rules as for EnsureLabel apply to each entry. The entries are checked before anything is modified,
+// so if any label already exists with a different value (and mode OVERWRITE is not set), a MetadataEntryAlreadyExistsError is returned and the object is not modified.
+// To remove labels, set mode to DELETE. The values of the given entries do not matter in this case.
+// If patch is set to true, all changes are sent to the cluster via a single patch. client may be nil when patch is false.
+func EnsureLabels(ctx context.Context, c client.Client, obj client.Object, entries map[string]string, patch bool, mode ...ModifyMetadataEntryMode) error {
+	return ensureMetadataEntries(LABEL, ctx, c, obj, entries, patch, mode...)
+}
+
 // ensureMetadataEntry is the common base method for EnsureAnnotation and EnsureLabel.
 func ensureMetadataEntry(mType metadataEntryType, ctx context.Context, c client.Client, obj client.Object, key, value string, patch bool, mode ...ModifyMetadataEntryMode) error {
+	return ensureMetadataEntries(mType, ctx, c, obj, map[string]string{key: value}, patch, mode...)
+}
+
+// ensureMetadataEntries is the common base method for the singular and plural versions of EnsureAnnotation and EnsureLabel.
+func ensureMetadataEntries(mType metadataEntryType, ctx context.Context, c client.Client, obj client.Object, entries map[string]string, patch bool, mode ...ModifyMetadataEntryMode) error {
+	return ensureMetadataEntriesGuarded(mType, ctx, c, obj, entries, patch, nil, mode...)
+}
+
+// ensureMetadataEntriesGuarded works like ensureMetadataEntries, but if a guard is given and any change is required, the guard is evaluated before modifying anything.
+// If the guard returns false, ErrMetadataEntryUpdateSkipped is returned and the object is not modified.
+func ensureMetadataEntriesGuarded(mType metadataEntryType, ctx context.Context, c client.Client, obj client.Object, entries map[string]string, patch bool, guard func(obj client.Object) bool, mode ...ModifyMetadataEntryMode) error {
 	modeDelete := false
 	modeOverwrite := false
+	modeCAS := false
 	for _, m := range mode {
 		switch m {
 		case DELETE:
 			modeDelete = true
 		case OVERWRITE:
 			modeOverwrite = true
+		case CAS:
+			modeCAS = true
 		}
 	}
-	quote := "\""
 	data := mType.GetData(obj)
 	if data == nil {
 		data = map[string]string{}
 	}
-	val, ok := data[key]
-	if (ok && val == value && !modeDelete) || (!ok && modeDelete) {
-		// annotation/label already exists on the object, nothing to do
-		return nil
-	}
-	if modeDelete {
-		// delete annotation/label
-		value = "null"
-		quote = ""
-		delete(data, key)
-	} else {
+	// determine the required changes before modifying anything
+	changes := map[string]*string{}
+	for _, key := range slices.Sorted(maps.Keys(entries)) {
+		value := entries[key]
+		val, ok := data[key]
+		if (ok && val == value && !modeDelete) || (!ok && modeDelete) {
+			// annotation/label already exists on the object, nothing to do
+			continue
+		}
+		if modeDelete {
+			changes[key] = nil
+			continue
+		}
 		if ok && !modeOverwrite {
 			return NewMetadataEntryAlreadyExistsError(mType, key, value, val)
 		}
-		// add annotation/label to obj
-		data[key] = value
+		changes[key] = &value
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	if guard != nil && !guard(obj) {
+		return ErrMetadataEntryUpdateSkipped
+	}
+	for key, value := range changes {
+		if value == nil {
+			// delete annotation/label
+			delete(data, key)
+		} else {
+			// add annotation/label to obj
+			data[key] = *value
+		}
 	}
 	mType.SetData(obj, data)
 	if patch {
-		// patch annotation/label to in-cluster object
-		if err := c.Patch(ctx, obj, client.RawPatch(types.MergePatchType, []byte(fmt.Sprintf(`{"metadata":{"%ss":{"%s":%s%s%s}}}`, mType.Name(), key, quote, value, quote)))); err != nil {
+		// patch annotations/labels to in-cluster object
+		resourceVersion := ""
+		if modeCAS {
+			resourceVersion = obj.GetResourceVersion()
+		}
+		rawPatch, err := metadataEntriesPatch(mType, changes, resourceVersion)
+		if err != nil {
+			return err
+		}
+		if err := c.Patch(ctx, obj, client.RawPatch(types.MergePatchType, rawPatch)); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// ReconcileManagedAnnotations works like ReconcileManagedLabels, but for annotations.
+func ReconcileManagedAnnotations(ctx context.Context, c client.Client, obj client.Object, desired map[string]string, managedPrefix string, patch bool) (bool, error) {
+	return reconcileManagedMetadataEntries(ANNOTATION, ctx, c, obj, desired, managedPrefix, patch)
+}
+
+// ReconcileManagedLabels ensures that the labels of the object which start with managedPrefix exactly match the desired ones.
+// All desired labels are added or updated, independent of their prefix. Labels which start with managedPrefix but are not desired are removed.
+// All other labels are left untouched. Note that an empty managedPrefix causes all labels which are not desired to be removed.
+// If patch is set to true, all changes are sent to the cluster via a single patch. client may be nil when patch is false.
+// Returns whether the labels of the object have been changed.
+func ReconcileManagedLabels(ctx context.Context, c client.Client, obj client.Object, desired map[string]string, managedPrefix string, patch bool) (bool, error) {
+	return reconcileManagedMetadataEntries(LABEL, ctx, c, obj, desired, managedPrefix, patch)
+}
+
+// reconcileManagedMetadataEntries is the common base method for ReconcileManagedAnnotations and ReconcileManagedLabels.
+func reconcileManagedMetadataEntries(mType metadataEntryType, ctx context.Context, c client.Client, obj client.Object, desired map[string]string, managedPrefix string, patch bool) (bool, error) {
+	data := mType.GetData(obj)
+	if data == nil {
+		data = map[string]string{}
+	}
+	changes := map[string]*string{}
+	for key, value := range desired {
+		if val, ok := data[key]; !ok || val != value {
+			changes[key] = &value
+		}
+	}
+	for key := range data {
+		if _, ok := desired[key]; !ok && strings.HasPrefix(key, managedPrefix) {
+			changes[key] = nil
+		}
+	}
+	if len(changes) == 0 {
+		return false, nil
+	}
+	for key, value := range changes {
+		if value == nil {
+			delete(data, key)
+		} else {
+			data[key] = *value
+		}
+	}
+	mType.SetData(obj, data)
+	if patch {
+		rawPatch, err := metadataEntriesPatch(mType, changes, "")
+		if err != nil {
+			return true, err
+		}
+		if err := c.Patch(ctx, obj, client.RawPatch(types.MergePatchType, rawPatch)); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+// metadataEntriesPatch builds a JSON merge patch for the given annotations/labels.
+// Entries with a nil value are removed by the patch.
+// If resourceVersion is not empty, it is added to the patch, which causes the patch to fail with a conflict if the object's resourceVersion differs.
+func metadataEntriesPatch(mType metadataEntryType, entries map[string]*string, resourceVersion string) ([]byte, error) {
+	metadata := map[string]any{
+		mType.Name() + "s": entries,
+	}
+	if resourceVersion != "" {
+		metadata["resourceVersion"] = resourceVersion
+	}
+	rawPatch, err := json.Marshal(map[string]any{"metadata": metadata})
+	if err != nil {
+		return nil, fmt.Errorf("error building %s patch: %w", mType.Name(), err)
+	}
+	return rawPatch, nil
+}
+
 type ModifyMetadataEntryMode string
 
 const (
 	OVERWRITE ModifyMetadataEntryMode = "overwrite"
 	DELETE    ModifyMetadataEntryMode = "delete"
+	// CAS adds the object's resourceVersion to the patch, so that the patch fails with a conflict error if the object has been modified in the cluster in the meantime.
+	// It is used by EnsureAnnotationCAS and EnsureLabelCAS, but can also be passed to EnsureAnnotation and EnsureLabel.
+	// Has no effect if patch is false.
+	CAS ModifyMetadataEntryMode = "cas"
 )
 
 //////////////////////////////////////
@@ -201,3 +380,39 @@ func GetAnnotation(obj client.Object, key string) (string, bool) {
 func GetLabel(obj client.Object, key string) (string, bool) {
 	return getMetadataEntry(LABEL, obj, key)
 }
+
+//////////////////////////////////////
+/// DIFFING ANNOTATIONS AND LABELS ///
+//////////////////////////////////////
+
+// DiffMetadata compares the annotations or labels (depending on mType, use ANNOTATION or LABEL) of the old and the updated object.
+// 'added' contains the entries which exist only on the updated object, 'removed' contains the entries which exist only on the old object (with their old values),
+// and 'changed' contains the entries which exist on both objects with different values (with their updated values).
+// A nil object is treated like an object without any annotations or labels.
+// The returned maps are never nil.
+func DiffMetadata(old, updated client.Object, mType metadataEntryType) (added, removed, changed map[string]string) {
+	added = map[string]string{}
+	removed = map[string]string{}
+	changed = map[string]string{}
+	var oldData, newData map[string]string
+	if !IsNil(old) {
+		oldData = mType.GetData(old)
+	}
+	if !IsNil(updated) {
+		newData = mType.GetData(updated)
+	}
+	for k, newVal := range newData {
+		oldVal, ok := oldData[k]
+		if !ok {
+			added[k] = newVal
+		} else if oldVal != newVal {
+			changed[k] = newVal
+		}
+	}
+	for k, oldVal := range oldData {
+		if _, ok := newData[k]; !ok {
+			removed[k] = oldVal
+		}
+	}
+	return added, removed, changed
+}
@@ -1,13 +1,17 @@
 package controller_test
 
 import (
+	"context"
 	"fmt"
+	"maps"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
 	ctrlutils "github.com/openmcp-project/controller-utils/pkg/controller"
 	testutils "github.com/openmcp-project/controller-utils/pkg/testing"
@@ -208,6 +212,223 @@ var _ = Describe("Annotation/Label Library", func() {
 
 	})
 
+	Context("Multiple entries", func() {
+
+		It("should set multiple annotations with a single patch", func() {
+			patches := 0
+			env := testutils.NewEnvironmentBuilder().WithInitObjectPath("testdata", "test-01").WithFakeClientBuilderCall("WithInterceptorFuncs", interceptor.Funcs{
+				Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+					patches++
+					return c.Patch(ctx, obj, patch, opts...)
+				},
+			}).Build()
+			ns := &corev1.Namespace{}
+			Expect(env.Client().Get(env.Ctx, client.ObjectKey{Name: "foo-annotation"}, ns)).To(Succeed())
+			entries := map[string]string{
+				"foo.bar.baz/foo": "bar",
+				"foo.bar.baz/bar": "baz",
+				"foo.bar.baz/baz": "foo",
+				"foo.bar.baz/new": "value",
+			}
+			Expect(ctrlutils.EnsureAnnotations(env.Ctx, env.Client(), ns, entries, true)).To(Succeed())
+			Expect(patches).To(Equal(1))
+			Expect(ns.GetAnnotations()).To(Equal(entries))
+			Expect(env.Client().Get(env.Ctx, client.ObjectKeyFromObject(ns), ns)).To(Succeed())
+			Expect(ns.GetAnnotations()).To(Equal(entries))
+
+			// nothing to do, no patch
+			Expect(ctrlutils.EnsureAnnotations(env.Ctx, env.Client(), ns, entries, true)).To(Succeed())
+			Expect(patches).To(Equal(1))
+
+			// delete multiple annotations with a single patch
+			Expect(ctrlutils.EnsureAnnotations(env.Ctx, env.Client(), ns, map[string]string{"foo.bar.baz/bar": "", "foo.bar.baz/baz": "", "foo.bar.baz/missing": ""}, true, ctrlutils.DELETE)).To(Succeed())
+			Expect(patches).To(Equal(2))
+			Expect(env.Client().Get(env.Ctx, client.ObjectKeyFromObject(ns), ns)).To(Succeed())
+			Expect(ns.GetAnnotations()).To(Equal(map[string]string{"foo.bar.baz/foo": "bar", "foo.bar.baz/new": "value"}))
+		})
+
+		It("should not modify anything if one of the entries already exists with a different value", func() {
+			env := testutils.NewEnvironmentBuilder().WithInitObjectPath("testdata", "test-01").Build()
+			ns := &corev1.Namespace{}
+			Expect(env.Client().Get(env.Ctx, client.ObjectKey{Name: "foo-label"}, ns)).To(Succeed())
+			oldNs := ns.DeepCopy()
+			entries := map[string]string{
+				"foo.bar.baz/bar": "baz",
+				"foo.bar.baz/foo": "baz",
+			}
+			Expect(ctrlutils.EnsureLabels(env.Ctx, env.Client(), ns, entries, true)).To(MatchError(ctrlutils.NewMetadataEntryAlreadyExistsError(ctrlutils.LABEL, "foo.bar.baz/foo", "baz", "bar")))
+			Expect(ns).To(Equal(oldNs))
+			Expect(ctrlutils.EnsureLabels(env.Ctx, env.Client(), ns, entries, true, ctrlutils.OVERWRITE)).To(Succeed())
+			Expect(env.Client().Get(env.Ctx, client.ObjectKeyFromObject(ns), ns)).To(Succeed())
+			Expect(ns.GetLabels()).To(Equal(entries))
+		})
+
+	})
+
+	Context("Managed entries", func() {
+
+		It("should add, update, and prune labels with the managed prefix", func() {
+			env := testutils.NewEnvironmentBuilder().WithInitObjectPath("testdata", "test-01").Build()
+			ns := &corev1.Namespace{}
+			Expect(env.Client().Get(env.Ctx, client.ObjectKey{Name: "foo-label"}, ns)).To(Succeed())
+			ns.SetLabels(map[string]string{
+				"managed.test/update": "old",
+				"managed.test/prune":  "value",
+				"unmanaged":           "value",
+			})
+			Expect(env.Client().Update(env.Ctx, ns)).To(Succeed())
+
+			desired := map[string]string{
+				"managed.test/update": "new",
+				"managed.test/add":    "value",
+			}
+			changed, err := ctrlutils.ReconcileManagedLabels(env.Ctx, env.Client(), ns, desired, "managed.test/", true)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changed).To(BeTrue())
+			expected := map[string]string{
+				"managed.test/update": "new",
+				"managed.test/add":    "value",
+				"unmanaged":           "value",
+			}
+			Expect(ns.GetLabels()).To(Equal(expected))
+			Expect(env.Client().Get(env.Ctx, client.ObjectKeyFromObject(ns), ns)).To(Succeed())
+			Expect(ns.GetLabels()).To(Equal(expected))
+
+			// nothing to do
+			changed, err = ctrlutils.ReconcileManagedLabels(env.Ctx, env.Client(), ns, desired, "managed.test/", true)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changed).To(BeFalse())
+
+			// prune all managed labels
+			changed, err = ctrlutils.ReconcileManagedLabels(env.Ctx, env.Client(), ns, nil, "managed.test/", true)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changed).To(BeTrue())
+			Expect(env.Client().Get(env.Ctx, client.ObjectKeyFromObject(ns), ns)).To(Succeed())
+			Expect(ns.GetLabels()).To(Equal(map[string]string{"unmanaged": "value"}))
+		})
+
+		It("should only modify the object in memory if patch is false", func() {
+			env := testutils.NewEnvironmentBuilder().WithInitObjectPath("testdata", "test-01").Build()
+			ns := &corev1.Namespace{}
+			Expect(env.Client().Get(env.Ctx, client.ObjectKey{Name: "foo-annotation"}, ns)).To(Succeed())
+			oldAnnotations := maps.Clone(ns.GetAnnotations())
+			changed, err := ctrlutils.ReconcileManagedAnnotations(env.Ctx, nil, ns, map[string]string{"managed.test/add": "value"}, "managed.test/", false)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changed).To(BeTrue())
+			Expect(ns.GetAnnotations()).To(HaveKeyWithValue("managed.test/add", "value"))
+			Expect(env.Client().Get(env.Ctx, client.ObjectKeyFromObject(ns), ns)).To(Succeed())
+			Expect(ns.GetAnnotations()).To(Equal(oldAnnotations))
+		})
+
+	})
+
+	Context("Compare-and-set", func() {
+
+		It("should set the annotation if the current value matches the expected one", func() {
+			env := testutils.NewEnvironmentBuilder().WithInitObjectPath("testdata", "test-01").Build()
+			ns := &corev1.Namespace{}
+			Expect(env.Client().Get(env.Ctx, client.ObjectKey{Name: "foo-annotation"}, ns)).To(Succeed())
+			Expect(ctrlutils.EnsureAnnotationCAS(env.Ctx, env.Client(), ns, "foo.bar.baz/foo", "bar", "baz", true)).To(Succeed())
+			Expect(ns.GetAnnotations()).To(HaveKeyWithValue("foo.bar.baz/foo", "baz"))
+			Expect(env.Client().Get(env.Ctx, client.ObjectKeyFromObject(ns), ns)).To(Succeed())
+			Expect(ns.GetAnnotations()).To(HaveKeyWithValue("foo.bar.baz/foo", "baz"))
+		})
+
+		It("should set a missing annotation if the expected value is empty", func() {
+			env := testutils.NewEnvironmentBuilder().WithInitObjectPath("testdata", "test-01").Build()
+			ns := &corev1.Namespace{}
+			Expect(env.Client().Get(env.Ctx, client.ObjectKey{Name: "no-annotation"}, ns)).To(Succeed())
+			Expect(ctrlutils.EnsureAnnotationCAS(env.Ctx, env.Client(), ns, "foo.bar.baz/foo", "", "baz", true)).To(Succeed())
+			Expect(env.Client().Get(env.Ctx, client.ObjectKeyFromObject(ns), ns)).To(Succeed())
+			Expect(ns.GetAnnotations()).To(HaveKeyWithValue("foo.bar.baz/foo", "baz"))
+		})
+
+		It("should return a MetadataEntryAlreadyExistsError if the current value does not match the expected one", func() {
+			env := testutils.NewEnvironmentBuilder().WithInitObjectPath("testdata", "test-01").Build()
+			ns := &corev1.Namespace{}
+			Expect(env.Client().Get(env.Ctx, client.ObjectKey{Name: "foo-annotation"}, ns)).To(Succeed())
+			oldNs := ns.DeepCopy()
+			Expect(ctrlutils.EnsureAnnotationCAS(env.Ctx, env.Client(), ns, "foo.bar.baz/foo", "foo", "baz", true)).To(MatchError(ctrlutils.NewMetadataEntryAlreadyExistsError(ctrlutils.ANNOTATION, "foo.bar.baz/foo", "baz", "bar")))
+			Expect(ns).To(Equal(oldNs))
+			Expect(env.Client().Get(env.Ctx, client.ObjectKeyFromObject(ns), ns)).To(Succeed())
+			Expect(ns.GetAnnotations()).To(HaveKeyWithValue("foo.bar.baz/foo", "bar"))
+		})
+
+		It("should return a conflict error if the object has been modified concurrently", func() {
+			env := testutils.NewEnvironmentBuilder().WithInitObjectPath("testdata", "test-01").Build()
+			ns := &corev1.Namespace{}
+			Expect(env.Client().Get(env.Ctx, client.ObjectKey{Name: "foo-annotation"}, ns)).To(Succeed())
+			concurrent := ns.DeepCopy()
+			concurrent.SetAnnotations(map[string]string{"foo.bar.baz/foo": "concurrent"})
+			Expect(env.Client().Update(env.Ctx, concurrent)).To(Succeed())
+
+			err := ctrlutils.EnsureAnnotationCAS(env.Ctx, env.Client(), ns, "foo.bar.baz/foo", "bar", "baz", true)
+			Expect(apierrors.IsConflict(err)).To(BeTrue(), "expected conflict error, got %v", err)
+			Expect(env.Client().Get(env.Ctx, client.ObjectKeyFromObject(ns), ns)).To(Succeed())
+			Expect(ns.GetAnnotations()).To(HaveKeyWithValue("foo.bar.baz/foo", "concurrent"))
+		})
+
+		It("should set the label if the current value matches the expected one", func() {
+			env := testutils.NewEnvironmentBuilder().WithInitObjectPath("testdata", "test-01").Build()
+			ns := &corev1.Namespace{}
+			Expect(env.Client().Get(env.Ctx, client.ObjectKey{Name: "foo-label"}, ns)).To(Succeed())
+			Expect(ctrlutils.EnsureLabelCAS(env.Ctx, env.Client(), ns, "foo.bar.baz/foo", "foo", "baz", true)).To(MatchError(ctrlutils.NewMetadataEntryAlreadyExistsError(ctrlutils.LABEL, "foo.bar.baz/foo", "baz", "bar")))
+			Expect(ctrlutils.EnsureLabelCAS(env.Ctx, env.Client(), ns, "foo.bar.baz/foo", "bar", "baz", true)).To(Succeed())
+			Expect(env.Client().Get(env.Ctx, client.ObjectKeyFromObject(ns), ns)).To(Succeed())
+			Expect(ns.GetLabels()).To(HaveKeyWithValue("foo.bar.baz/foo", "baz"))
+		})
+
+	})
+
+	Context("Guarded", func() {
+
+		observedGeneration := int64(1)
+		generationObserved := func(obj client.Object) bool {
+			return obj.GetGeneration() == observedGeneration
+		}
+
+		It("should modify the annotation if the guard passes", func() {
+			env := testutils.NewEnvironmentBuilder().WithInitObjectPath("testdata", "test-01").Build()
+			ns := &corev1.Namespace{}
+			Expect(env.Client().Get(env.Ctx, client.ObjectKey{Name: "foo-annotation"}, ns)).To(Succeed())
+			ns.SetGeneration(observedGeneration)
+			Expect(ctrlutils.EnsureAnnotationIf(env.Ctx, env.Client(), ns, "foo.bar.baz/foo", "new", true, generationObserved, ctrlutils.OVERWRITE)).To(Succeed())
+			Expect(ns.GetAnnotations()).To(HaveKeyWithValue("foo.bar.baz/foo", "new"))
+			Expect(env.Client().Get(env.Ctx, client.ObjectKeyFromObject(ns), ns)).To(Succeed())
+			Expect(ns.GetAnnotations()).To(HaveKeyWithValue("foo.bar.baz/foo", "new"))
+		})
+
+		It("should skip the modification if the guard fails", func() {
+			env := testutils.NewEnvironmentBuilder().WithInitObjectPath("testdata", "test-01").Build()
+			ns := &corev1.Namespace{}
+			Expect(env.Client().Get(env.Ctx, client.ObjectKey{Name: "foo-annotation"}, ns)).To(Succeed())
+			ns.SetGeneration(observedGeneration + 1)
+			oldNs := ns.DeepCopy()
+			err := ctrlutils.EnsureAnnotationIf(env.Ctx, env.Client(), ns, "foo.bar.baz/foo", "new", true, generationObserved, ctrlutils.OVERWRITE)
+			Expect(err).To(MatchError(ctrlutils.ErrMetadataEntryUpdateSkipped))
+			Expect(ns).To(Equal(oldNs))
+			Expect(env.Client().Get(env.Ctx, client.ObjectKeyFromObject(ns), ns)).To(Succeed())
+			Expect(ns.GetAnnotations()).To(HaveKeyWithValue("foo.bar.baz/foo", "bar"))
+
+			err = ctrlutils.EnsureLabelIf(env.Ctx, env.Client(), oldNs, "foo.bar.baz/new", "value", true, generationObserved)
+			Expect(err).To(MatchError(ctrlutils.ErrMetadataEntryUpdateSkipped))
+		})
+
+		It("should not evaluate the guard if no change is required", func() {
+			env := testutils.NewEnvironmentBuilder().WithInitObjectPath("testdata", "test-01").Build()
+			ns := &corev1.Namespace{}
+			Expect(env.Client().Get(env.Ctx, client.ObjectKey{Name: "foo-annotation"}, ns)).To(Succeed())
+			guardCalled := false
+			guard := func(obj client.Object) bool {
+				guardCalled = true
+				return false
+			}
+			Expect(ctrlutils.EnsureAnnotationIf(env.Ctx, env.Client(), ns, "foo.bar.baz/foo", "bar", true, guard)).To(Succeed())
+			Expect(guardCalled).To(BeFalse())
+		})
+
+	})
+
 	Context("Labels", func() {
 
 		Context("IsMetadataEntryAlreadyExistsError", func() {
@@ -401,4 +622,69 @@ var _ = Describe("Annotation/Label Library", func() {
 
 	})
 
+	Context("DiffMetadata", func() {
+
+		It("should detect added, removed, and changed entries", func() {
+			oldNs := &corev1.Namespace{}
+			oldNs.SetLabels(map[string]string{
+				"unchanged": "foo",
+				"removed":   "bar",
+				"changed":   "old",
+			})
+			oldNs.SetAnnotations(map[string]string{
+				"removed": "bar",
+			})
+			newNs := &corev1.Namespace{}
+			newNs.SetLabels(map[string]string{
+				"unchanged": "foo",
+				"changed":   "new",
+				"added":     "baz",
+			})
+			newNs.SetAnnotations(map[string]string{
+				"added": "baz",
+			})
+
+			added, removed, changed := ctrlutils.DiffMetadata(oldNs, newNs, ctrlutils.LABEL)
+			Expect(added).To(Equal(map[string]string{"added": "baz"}))
+			Expect(removed).To(Equal(map[string]string{"removed": "bar"}))
+			Expect(changed).To(Equal(map[string]string{"changed": "new"}))
+
+			added, removed, changed = ctrlutils.DiffMetadata(oldNs, newNs, ctrlutils.ANNOTATION)
+			Expect(added).To(Equal(map[string]string{"added": "baz"}))
+			Expect(removed).To(Equal(map[string]string{"removed": "bar"}))
+			Expect(changed).To(BeEmpty())
+		})
+
+		It("should return empty maps if nothing changed", func() {
+			ns := &corev1.Namespace{}
+			ns.SetLabels(map[string]string{"foo": "bar"})
+			added, removed, changed := ctrlutils.DiffMetadata(ns, ns.DeepCopy(), ctrlutils.LABEL)
+			Expect(added).ToNot(BeNil())
+			Expect(added).To(BeEmpty())
+			Expect(removed).To(BeEmpty())
+			Expect(changed).To(BeEmpty())
+		})
+
+		It("should treat nil objects, including typed nil pointers, like objects without metadata", func() {
+			ns := &corev1.Namespace{}
+			ns.SetLabels(map[string]string{"foo": "bar"})
+
+			added, removed, changed := ctrlutils.DiffMetadata(nil, ns, ctrlutils.LABEL)
+			Expect(added).To(Equal(map[string]string{"foo": "bar"}))
+			Expect(removed).To(BeEmpty())
+			Expect(changed).To(BeEmpty())
+
+			added, removed, changed = ctrlutils.DiffMetadata((*corev1.Namespace)(nil), ns, ctrlutils.LABEL)
+			Expect(added).To(Equal(map[string]string{"foo": "bar"}))
+			Expect(removed).To(BeEmpty())
+			Expect(changed).To(BeEmpty())
+
+			added, removed, changed = ctrlutils.DiffMetadata(ns, (*corev1.Namespace)(nil), ctrlutils.LABEL)
+			Expect(added).To(BeEmpty())
+			Expect(removed).To(Equal(map[string]string{"foo": "bar"}))
+			Expect(changed).To(BeEmpty())
+		})
+
+	})
+
 })
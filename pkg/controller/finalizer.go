@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// HasFinalizer returns true if the given object has the given finalizer.
+func HasFinalizer(obj client.Object, finalizer string) bool {
+	return controllerutil.ContainsFinalizer(obj, finalizer)
+}
+
+// EnsureFinalizer adds the given finalizer to the object, if it is not already present.
+// If patch is set to true, the object will be patched in the cluster immediately, otherwise only the in-memory object is modified. client may be nil when patch is false.
+// Since a merge patch replaces the complete list of finalizers, the patch contains the object's resourceVersion, so it fails with a conflict error if the object has been modified in the cluster in the meantime.
+// Returns whether the finalizers of the object have been changed.
+func EnsureFinalizer(ctx context.Context, c client.Client, obj client.Object, finalizer string, patch bool) (bool, error) {
+	if !controllerutil.AddFinalizer(obj, finalizer) {
+		return false, nil
+	}
+	return true, patchFinalizers(ctx, c, obj, patch)
+}
+
+// RemoveFinalizer removes the given finalizer from the object, if it is present.
+// If patch is set to true, the object will be patched in the cluster immediately, otherwise only the in-memory object is modified. client may be nil when patch is false.
+// Since a merge patch replaces the complete list of finalizers, the patch contains the object's resourceVersion, so it fails with a conflict error if the object has been modified in the cluster in the meantime.
+// Returns whether the finalizers of the object have been changed.
+func RemoveFinalizer(ctx context.Context, c client.Client, obj client.Object, finalizer string, patch bool) (bool, error) {
+	if !controllerutil.RemoveFinalizer(obj, finalizer) {
+		return false, nil
+	}
+	return true, patchFinalizers(ctx, c, obj, patch)
+}
+
+// patchFinalizers sends the in-memory finalizers of the object to the cluster via a merge patch, if patch is true.
+func patchFinalizers(ctx context.Context, c client.Client, obj client.Object, patch bool) error {
+	if !patch {
+		return nil
+	}
+	metadata := map[string]any{
+		// a nil list results in null, which removes the field in a merge patch
+		"finalizers": obj.GetFinalizers(),
+	}
+	if rv := obj.GetResourceVersion(); rv != "" {
+		metadata["resourceVersion"] = rv
+	}
+	rawPatch, err := json.Marshal(map[string]any{"metadata": metadata})
+	if err != nil {
+		return fmt.Errorf("error building finalizer patch: %w", err)
+	}
+	return c.Patch(ctx, obj, client.RawPatch(types.MergePatchType, rawPatch))
+}
@@ -0,0 +1,99 @@
+package controller_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ctrlutils "github.com/openmcp-project/controller-utils/pkg/controller"
+	testutils "github.com/openmcp-project/controller-utils/pkg/testing"
+)
+
+const testFinalizer = "foo.bar.baz/finalizer"
+
+var _ = Describe("Finalizers", func() {
+
+	It("should add a new finalizer in memory and in the cluster", func() {
+		env := testutils.NewEnvironmentBuilder().WithInitObjectPath("testdata", "test-01").Build()
+		ns := &corev1.Namespace{}
+		Expect(env.Client().Get(env.Ctx, client.ObjectKey{Name: "foo-label"}, ns)).To(Succeed())
+		Expect(ctrlutils.HasFinalizer(ns, testFinalizer)).To(BeFalse())
+
+		changed, err := ctrlutils.EnsureFinalizer(env.Ctx, env.Client(), ns, testFinalizer, true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(ctrlutils.HasFinalizer(ns, testFinalizer)).To(BeTrue())
+		Expect(env.Client().Get(env.Ctx, client.ObjectKeyFromObject(ns), ns)).To(Succeed())
+		Expect(ns.GetFinalizers()).To(ConsistOf(testFinalizer))
+	})
+
+	It("should not do anything if the finalizer already exists", func() {
+		env := testutils.NewEnvironmentBuilder().WithInitObjectPath("testdata", "test-01").Build()
+		ns := &corev1.Namespace{}
+		Expect(env.Client().Get(env.Ctx, client.ObjectKey{Name: "foo-label"}, ns)).To(Succeed())
+		_, err := ctrlutils.EnsureFinalizer(env.Ctx, env.Client(), ns, testFinalizer, true)
+		Expect(err).ToNot(HaveOccurred())
+
+		// nil client proves that no patch is sent
+		changed, err := ctrlutils.EnsureFinalizer(env.Ctx, nil, ns, testFinalizer, true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeFalse())
+		Expect(ns.GetFinalizers()).To(ConsistOf(testFinalizer))
+	})
+
+	It("should remove a finalizer in memory and in the cluster", func() {
+		env := testutils.NewEnvironmentBuilder().WithInitObjectPath("testdata", "test-01").Build()
+		ns := &corev1.Namespace{}
+		Expect(env.Client().Get(env.Ctx, client.ObjectKey{Name: "foo-label"}, ns)).To(Succeed())
+		_, err := ctrlutils.EnsureFinalizer(env.Ctx, env.Client(), ns, "other", true)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = ctrlutils.EnsureFinalizer(env.Ctx, env.Client(), ns, testFinalizer, true)
+		Expect(err).ToNot(HaveOccurred())
+
+		changed, err := ctrlutils.RemoveFinalizer(env.Ctx, env.Client(), ns, testFinalizer, true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(env.Client().Get(env.Ctx, client.ObjectKeyFromObject(ns), ns)).To(Succeed())
+		Expect(ns.GetFinalizers()).To(ConsistOf("other"))
+
+		// removing a missing finalizer is a no-op
+		changed, err = ctrlutils.RemoveFinalizer(env.Ctx, nil, ns, testFinalizer, true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeFalse())
+
+		// removing the last finalizer
+		changed, err = ctrlutils.RemoveFinalizer(env.Ctx, env.Client(), ns, "other", true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(env.Client().Get(env.Ctx, client.ObjectKeyFromObject(ns), ns)).To(Succeed())
+		Expect(ns.GetFinalizers()).To(BeEmpty())
+	})
+
+	It("should only modify the in-memory object if patch is false", func() {
+		env := testutils.NewEnvironmentBuilder().WithInitObjectPath("testdata", "test-01").Build()
+		ns := &corev1.Namespace{}
+		Expect(env.Client().Get(env.Ctx, client.ObjectKey{Name: "foo-label"}, ns)).To(Succeed())
+		changed, err := ctrlutils.EnsureFinalizer(env.Ctx, nil, ns, testFinalizer, false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(ctrlutils.HasFinalizer(ns, testFinalizer)).To(BeTrue())
+		Expect(env.Client().Get(env.Ctx, client.ObjectKeyFromObject(ns), ns)).To(Succeed())
+		Expect(ns.GetFinalizers()).To(BeEmpty())
+	})
+
+	It("should fail with a conflict if the object has been modified in the meantime", func() {
+		env := testutils.NewEnvironmentBuilder().WithInitObjectPath("testdata", "test-01").Build()
+		ns := &corev1.Namespace{}
+		Expect(env.Client().Get(env.Ctx, client.ObjectKey{Name: "foo-label"}, ns)).To(Succeed())
+		outdated := ns.DeepCopy()
+		_, err := ctrlutils.EnsureFinalizer(env.Ctx, env.Client(), ns, "other", true)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = ctrlutils.EnsureFinalizer(env.Ctx, env.Client(), outdated, testFinalizer, true)
+		Expect(apierrors.IsConflict(err)).To(BeTrue())
+	})
+
+})
@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// StreamList lists the objects of the given list type page by page and streams the extracted items into the returned channel.
+// The 'list' argument is used as a template for each page, it is not modified.
+// The 'extract' function is used to extract the items from a listed page, e.g. 'func(l *corev1.ConfigMapList) []corev1.ConfigMap { return l.Items }'.
+// The page size defines how many objects are fetched per List call. If it is 0 or negative, no limit is set and all objects are fetched in a single call.
+// Both returned channels are closed when all items have been streamed, the context is cancelled, or an error occurred.
+// At most one error is sent into the error channel. The caller is expected to consume the item channel until it is closed and then check the error channel.
+func StreamList[T any, L client.ObjectList](ctx context.Context, c client.Reader, list L, extract func(L) []T, pageSize int64, opts ...client.ListOption) (<-chan T, <-chan error) {
+	items := make(chan T)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(items)
+
+		continueToken := ""
+		for {
+			page, ok := list.DeepCopyObject().(L)
+			if !ok {
+				errs <- fmt.Errorf("unable to copy list of type %T", list)
+				return
+			}
+			pageOpts := make([]client.ListOption, 0, len(opts)+2)
+			pageOpts = append(pageOpts, opts...)
+			if pageSize > 0 {
+				pageOpts = append(pageOpts, client.Limit(pageSize))
+			}
+			if continueToken != "" {
+				pageOpts = append(pageOpts, client.Continue(continueToken))
+			}
+			if err := c.List(ctx, page, pageOpts...); err != nil {
+				errs <- fmt.Errorf("error listing objects: %w", err)
+				return
+			}
+			for _, item := range extract(page) {
+				select {
+				case items <- item:
+				case <-ctx.Done():
+					errs <- ctx.Err()
+					return
+				}
+			}
+			continueToken = page.GetContinue()
+			if continueToken == "" {
+				return
+			}
+		}
+	}()
+
+	return items, errs
+}
@@ -0,0 +1,111 @@
+package controller_test
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	ctrlutils "github.com/openmcp-project/controller-utils/pkg/controller"
+	testutils "github.com/openmcp-project/controller-utils/pkg/testing"
+)
+
+// pagingClient returns a client that simulates pagination for ConfigMapLists, because the fake client ignores the Limit and Continue options.
+// The continue token is simply the index of the next item. The returned counter is increased with each List call.
+func pagingClient(c client.WithWatch) (client.WithWatch, *int) {
+	calls := 0
+	return interceptor.NewClient(c, interceptor.Funcs{
+		List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+			calls++
+			lo := &client.ListOptions{}
+			lo.ApplyOptions(opts)
+			if err := c.List(ctx, list, opts...); err != nil {
+				return err
+			}
+			cml, ok := list.(*corev1.ConfigMapList)
+			if !ok || lo.Limit <= 0 {
+				return nil
+			}
+			start := 0
+			if lo.Continue != "" {
+				var err error
+				start, err = strconv.Atoi(lo.Continue)
+				if err != nil {
+					return err
+				}
+			}
+			total := len(cml.Items)
+			end := min(start+int(lo.Limit), total)
+			cml.Items = cml.Items[start:end]
+			cml.Continue = ""
+			if end < total {
+				cml.Continue = strconv.Itoa(end)
+			}
+			return nil
+		},
+	}), &calls
+}
+
+var _ = Describe("StreamList", func() {
+
+	configMaps := func(n int) []client.Object {
+		res := make([]client.Object, 0, n)
+		for i := range n {
+			cm := &corev1.ConfigMap{}
+			cm.SetName(fmt.Sprintf("cm-%02d", i))
+			cm.SetNamespace("default")
+			res = append(res, cm)
+		}
+		return res
+	}
+
+	extract := func(l *corev1.ConfigMapList) []corev1.ConfigMap {
+		return l.Items
+	}
+
+	It("should stream all items across multiple pages", func() {
+		env := testutils.NewEnvironmentBuilder().WithInitObjects(configMaps(11)...).Build()
+		c, calls := pagingClient(env.Client().(client.WithWatch))
+
+		items, errs := ctrlutils.StreamList(env.Ctx, c, &corev1.ConfigMapList{}, extract, 3, client.InNamespace("default"))
+		names := []string{}
+		for item := range items {
+			names = append(names, item.Name)
+		}
+		Expect(<-errs).ToNot(HaveOccurred())
+		Expect(names).To(HaveLen(11))
+		Expect(names).To(HaveEach(HavePrefix("cm-")))
+		Expect(*calls).To(Equal(4))
+	})
+
+	It("should return an error if listing fails", func() {
+		env := testutils.NewEnvironmentBuilder().Build()
+		c := interceptor.NewClient(env.Client().(client.WithWatch), interceptor.Funcs{
+			List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				return fmt.Errorf("list failed")
+			},
+		})
+
+		items, errs := ctrlutils.StreamList(env.Ctx, c, &corev1.ConfigMapList{}, extract, 3)
+		Eventually(items).Should(BeClosed())
+		Expect(<-errs).To(MatchError(ContainSubstring("list failed")))
+	})
+
+	It("should stop streaming if the context is cancelled", func() {
+		env := testutils.NewEnvironmentBuilder().WithInitObjects(configMaps(5)...).Build()
+		ctx, cancel := context.WithCancel(env.Ctx)
+
+		items, errs := ctrlutils.StreamList(ctx, env.Client(), &corev1.ConfigMapList{}, extract, 0)
+		Expect(<-items).ToNot(BeZero())
+		cancel()
+		Eventually(items).Should(BeClosed())
+		Expect(<-errs).To(MatchError(context.Canceled))
+	})
+
+})
@@ -1,11 +1,18 @@
 package controller
 
 import (
+	"context"
 	"fmt"
+	"reflect"
 
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 // HasOwnerReference returns the index of the owner reference if the 'owned' object has a owner reference pointing to the 'owner' object.
@@ -44,3 +51,105 @@ func HasOwnerReference(owned, owner client.Object, scheme *runtime.Scheme) (int,
 	}
 	return -1, nil
 }
+
+// ReconcileOwnedSet ensures that exactly the desired objects exist among the objects of type T which are owned by the given owner.
+// Each desired object gets a controller reference pointing to the owner and is created, if it doesn't exist, or updated, if it differs from the existing object.
+// The desired object is compared to the existing one as a whole, except for the status and the metadata set by the server (e.g. resourceVersion, uid, and managedFields).
+// This means that removed fields, labels, or map entries are removed on the server as well, but also that fields defaulted by the server cause an update, unless they are set in the desired object.
+// Afterwards, all objects of type T which are owned by the owner (according to HasOwnerReference) and are not part of the desired set, are deleted.
+// The listOpts are used to list the existing objects of type T, e.g. to restrict them to a namespace or label selector.
+// The scheme is required to determine the GVKs of the owner and T.
+// The desired objects are modified: their owner references and, for updated objects, their metadata are set.
+// Note that the owner needs to have a UID, otherwise the created owner references are not recognized as pointing to the owner.
+// Returns the number of created, updated, and deleted objects. If an error occurs, the counts reflect the operations performed until then.
+func ReconcileOwnedSet[T client.Object](ctx context.Context, c client.Client, owner client.Object, scheme *runtime.Scheme, desired []T, listOpts ...client.ListOption) (created, updated, deleted int, err error) {
+	typeOfT := reflect.TypeFor[T]()
+	if typeOfT.Kind() != reflect.Pointer {
+		return 0, 0, 0, fmt.Errorf("type parameter must be a pointer type, got %s", typeOfT)
+	}
+	gvk, err := apiutil.GVKForObject(reflect.New(typeOfT.Elem()).Interface().(T), scheme)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("unable to determine GVK for type %s: %w", typeOfT, err)
+	}
+
+	desiredKeys := sets.New[client.ObjectKey]()
+	for _, obj := range desired {
+		key := client.ObjectKeyFromObject(obj)
+		desiredKeys.Insert(key)
+		if err := controllerutil.SetControllerReference(owner, obj, scheme); err != nil {
+			return created, updated, deleted, fmt.Errorf("error setting owner reference on %s '%s': %w", gvk.Kind, key.String(), err)
+		}
+		existing := reflect.New(typeOfT.Elem()).Interface().(T)
+		if err := c.Get(ctx, key, existing); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return created, updated, deleted, fmt.Errorf("error getting %s '%s': %w", gvk.Kind, key.String(), err)
+			}
+			if err := c.Create(ctx, obj); err != nil {
+				return created, updated, deleted, fmt.Errorf("error creating %s '%s': %w", gvk.Kind, key.String(), err)
+			}
+			created++
+			continue
+		}
+		// copy metadata which is managed by the server, so that it is not detected as a difference
+		obj.SetResourceVersion(existing.GetResourceVersion())
+		obj.SetUID(existing.GetUID())
+		obj.SetCreationTimestamp(existing.GetCreationTimestamp())
+		obj.SetGeneration(existing.GetGeneration())
+		obj.SetManagedFields(existing.GetManagedFields())
+		obj.SetDeletionTimestamp(existing.GetDeletionTimestamp())
+		obj.SetDeletionGracePeriodSeconds(existing.GetDeletionGracePeriodSeconds())
+		existing.GetObjectKind().SetGroupVersionKind(obj.GetObjectKind().GroupVersionKind())
+		changed, err := ownedObjectChanged(obj, existing)
+		if err != nil {
+			return created, updated, deleted, fmt.Errorf("error comparing %s '%s': %w", gvk.Kind, key.String(), err)
+		}
+		if !changed {
+			continue
+		}
+		if err := c.Update(ctx, obj); err != nil {
+			return created, updated, deleted, fmt.Errorf("error updating %s '%s': %w", gvk.Kind, key.String(), err)
+		}
+		updated++
+	}
+
+	existingList := &metav1.PartialObjectMetadataList{}
+	existingList.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err := c.List(ctx, existingList, listOpts...); err != nil {
+		return created, updated, deleted, fmt.Errorf("error listing %s objects: %w", gvk.Kind, err)
+	}
+	for i := range existingList.Items {
+		obj := &existingList.Items[i]
+		if desiredKeys.Has(client.ObjectKeyFromObject(obj)) {
+			continue
+		}
+		idx, err := HasOwnerReference(obj, owner, scheme)
+		if err != nil {
+			return created, updated, deleted, err
+		}
+		if idx < 0 {
+			continue
+		}
+		obj.SetGroupVersionKind(gvk)
+		if err := c.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+			return created, updated, deleted, fmt.Errorf("error deleting %s '%s': %w", gvk.Kind, client.ObjectKeyFromObject(obj).String(), err)
+		}
+		deleted++
+	}
+	return created, updated, deleted, nil
+}
+
+// ownedObjectChanged returns true if the desired object differs from the existing one.
+// The status is ignored, because it is not modified by updates.
+func ownedObjectChanged(desired, existing client.Object) (bool, error) {
+	desiredData, err := runtime.DefaultUnstructuredConverter.ToUnstructured(desired)
+	if err != nil {
+		return false, err
+	}
+	existingData, err := runtime.DefaultUnstructuredConverter.ToUnstructured(existing)
+	if err != nil {
+		return false, err
+	}
+	delete(desiredData, "status")
+	delete(existingData, "status")
+	return !equality.Semantic.DeepEqual(desiredData, existingData), nil
+}
@@ -5,12 +5,16 @@ import (
 	. "github.com/onsi/gomega"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	ctrlutils "github.com/openmcp-project/controller-utils/pkg/controller"
+	testutils "github.com/openmcp-project/controller-utils/pkg/testing"
 )
 
 var _ = Describe("Owners", func() {
@@ -80,4 +84,114 @@ var _ = Describe("Owners", func() {
 
 	})
 
+	Context("ReconcileOwnedSet", func() {
+
+		var owner *corev1.ConfigMap
+		var sc *runtime.Scheme
+
+		configMap := func(name string, data map[string]string, owned bool) *corev1.ConfigMap {
+			cm := &corev1.ConfigMap{}
+			cm.SetName(name)
+			cm.SetNamespace("default")
+			cm.Data = data
+			if owned {
+				cm.SetOwnerReferences([]metav1.OwnerReference{{
+					APIVersion:         "v1",
+					Kind:               "ConfigMap",
+					Name:               owner.Name,
+					UID:                owner.UID,
+					Controller:         ptr.To(true),
+					BlockOwnerDeletion: ptr.To(true),
+				}})
+			}
+			return cm
+		}
+
+		BeforeEach(func() {
+			sc = runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(sc)).To(Succeed())
+			owner = &corev1.ConfigMap{}
+			owner.SetName("owner")
+			owner.SetNamespace("default")
+			owner.SetUID(types.UID("owner-uid"))
+		})
+
+		It("should create, update, and prune owned objects", func() {
+			env := testutils.NewEnvironmentBuilder().WithFakeClient(sc).WithInitObjects(
+				owner,
+				configMap("update", map[string]string{"foo": "old"}, true),
+				configMap("unchanged", map[string]string{"foo": "bar"}, true),
+				configMap("prune", nil, true),
+				configMap("foreign", nil, false),
+			).Build()
+
+			desired := func() []*corev1.ConfigMap {
+				return []*corev1.ConfigMap{
+					configMap("create", map[string]string{"foo": "bar"}, false),
+					configMap("update", map[string]string{"foo": "new"}, false),
+					configMap("unchanged", map[string]string{"foo": "bar"}, false),
+				}
+			}
+			created, updated, deleted, err := ctrlutils.ReconcileOwnedSet(env.Ctx, env.Client(), owner, sc, desired(), client.InNamespace("default"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(created).To(Equal(1))
+			Expect(updated).To(Equal(1))
+			Expect(deleted).To(Equal(1))
+
+			cm := &corev1.ConfigMap{}
+			Expect(env.Client().Get(env.Ctx, ctrlutils.ObjectKey("create", "default"), cm)).To(Succeed())
+			Expect(cm.Data).To(HaveKeyWithValue("foo", "bar"))
+			Expect(ctrlutils.HasOwnerReference(cm, owner, sc)).To(BeNumerically(">=", 0))
+			Expect(env.Client().Get(env.Ctx, ctrlutils.ObjectKey("update", "default"), cm)).To(Succeed())
+			Expect(cm.Data).To(HaveKeyWithValue("foo", "new"))
+			Expect(env.Client().Get(env.Ctx, ctrlutils.ObjectKey("prune", "default"), cm)).To(MatchError(apierrors.IsNotFound, "IsNotFound"))
+			Expect(env.Client().Get(env.Ctx, ctrlutils.ObjectKey("foreign", "default"), cm)).To(Succeed())
+			Expect(env.Client().Get(env.Ctx, ctrlutils.ObjectKey("owner", "default"), cm)).To(Succeed())
+
+			By("doing nothing if the desired state is already reached")
+			created, updated, deleted, err = ctrlutils.ReconcileOwnedSet(env.Ctx, env.Client(), owner, sc, desired(), client.InNamespace("default"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(created).To(BeZero())
+			Expect(updated).To(BeZero())
+			Expect(deleted).To(BeZero())
+		})
+
+		It("should remove data keys and labels which have been removed from the desired object", func() {
+			existing := configMap("update", map[string]string{"foo": "bar", "removed": "value"}, true)
+			existing.SetLabels(map[string]string{"keep": "true", "removed": "true"})
+			env := testutils.NewEnvironmentBuilder().WithFakeClient(sc).WithInitObjects(owner, existing).Build()
+
+			desired := configMap("update", map[string]string{"foo": "bar"}, false)
+			desired.SetLabels(map[string]string{"keep": "true"})
+			created, updated, deleted, err := ctrlutils.ReconcileOwnedSet(env.Ctx, env.Client(), owner, sc, []*corev1.ConfigMap{desired}, client.InNamespace("default"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(created).To(BeZero())
+			Expect(updated).To(Equal(1))
+			Expect(deleted).To(BeZero())
+
+			cm := &corev1.ConfigMap{}
+			Expect(env.Client().Get(env.Ctx, ctrlutils.ObjectKey("update", "default"), cm)).To(Succeed())
+			Expect(cm.Data).To(Equal(map[string]string{"foo": "bar"}))
+			Expect(cm.Labels).To(Equal(map[string]string{"keep": "true"}))
+		})
+
+		It("should delete all owned objects if nothing is desired", func() {
+			env := testutils.NewEnvironmentBuilder().WithFakeClient(sc).WithInitObjects(
+				configMap("prune1", nil, true),
+				configMap("prune2", nil, true),
+				configMap("foreign", nil, false),
+			).Build()
+			created, updated, deleted, err := ctrlutils.ReconcileOwnedSet[*corev1.ConfigMap](env.Ctx, env.Client(), owner, sc, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(created).To(BeZero())
+			Expect(updated).To(BeZero())
+			Expect(deleted).To(Equal(2))
+			cms := &corev1.ConfigMapList{}
+			Expect(env.Client().List(env.Ctx, cms)).To(Succeed())
+			Expect(cms.Items).To(HaveLen(1))
+			Expect(cms.Items[0].Name).To(Equal("foreign"))
+		})
+
+	})
+
 })
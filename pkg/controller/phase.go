@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PhaseColumn returns the value of the phase field of the given object, as it would be displayed in a printer column.
+// The phaseField is a dot-separated path to the field, e.g. 'status.phase'. A leading dot is allowed, so JSONPaths of the form '.status.phase',
+// as they are used for additionalPrinterColumns, can be passed in directly.
+// Returns an empty string without an error if the field is not set.
+// Returns an error if the object cannot be converted or if the field exists but is not a string.
+func PhaseColumn(obj client.Object, phaseField string) (string, error) {
+	path := strings.Split(strings.TrimPrefix(phaseField, "."), ".")
+	if slices.Contains(path, "") {
+		return "", fmt.Errorf("invalid phase field path '%s'", phaseField)
+	}
+	data, err := toUnstructuredMap(obj)
+	if err != nil {
+		return "", err
+	}
+	phase, _, err := unstructured.NestedString(data, path...)
+	if err != nil {
+		return "", fmt.Errorf("error reading phase field '%s': %w", phaseField, err)
+	}
+	return phase, nil
+}
+
+// toUnstructuredMap returns the unstructured representation of the given object.
+// For unstructured objects, the internal map is returned directly, without copying it.
+func toUnstructuredMap(obj client.Object) (map[string]any, error) {
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		return u.Object, nil
+	}
+	data, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, fmt.Errorf("error converting object to unstructured: %w", err)
+	}
+	return data, nil
+}
+
+// ValidatePhase returns an error if the given phase is not one of the allowed phases.
+// If no allowed phases are given, every phase is considered invalid.
+func ValidatePhase(phase string, allowed ...string) error {
+	if !slices.Contains(allowed, phase) {
+		return fmt.Errorf("invalid phase '%s', must be one of [%s]", phase, strings.Join(allowed, ", "))
+	}
+	return nil
+}
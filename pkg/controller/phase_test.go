@@ -0,0 +1,73 @@
+package controller_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	ctrlutils "github.com/openmcp-project/controller-utils/pkg/controller"
+)
+
+var _ = Describe("Phase", func() {
+
+	Context("PhaseColumn", func() {
+
+		It("should return the phase of a typed object", func() {
+			pod := &corev1.Pod{}
+			pod.Status.Phase = corev1.PodRunning
+			phase, err := ctrlutils.PhaseColumn(pod, "status.phase")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(phase).To(Equal(string(corev1.PodRunning)))
+			phase, err = ctrlutils.PhaseColumn(pod, ".status.phase")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(phase).To(Equal(string(corev1.PodRunning)))
+		})
+
+		It("should return the phase of an unstructured object", func() {
+			u := &unstructured.Unstructured{Object: map[string]any{
+				"status": map[string]any{
+					"phase": "Ready",
+				},
+			}}
+			phase, err := ctrlutils.PhaseColumn(u, ".status.phase")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(phase).To(Equal("Ready"))
+		})
+
+		It("should return an empty string if the phase is not set", func() {
+			phase, err := ctrlutils.PhaseColumn(&corev1.Pod{}, ".status.phase")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(phase).To(BeEmpty())
+		})
+
+		It("should return an error if the field is not a string or the path is invalid", func() {
+			u := &unstructured.Unstructured{Object: map[string]any{
+				"status": map[string]any{
+					"phase": int64(1),
+				},
+			}}
+			_, err := ctrlutils.PhaseColumn(u, ".status.phase")
+			Expect(err).To(HaveOccurred())
+			_, err = ctrlutils.PhaseColumn(u, "status..phase")
+			Expect(err).To(HaveOccurred())
+		})
+
+	})
+
+	Context("ValidatePhase", func() {
+
+		It("should accept allowed phases", func() {
+			Expect(ctrlutils.ValidatePhase("Ready", "Pending", "Ready", "Failed")).To(Succeed())
+		})
+
+		It("should reject phases that are not allowed", func() {
+			err := ctrlutils.ValidatePhase("Unknown", "Pending", "Ready", "Failed")
+			Expect(err).To(MatchError(ContainSubstring("Unknown")))
+			Expect(ctrlutils.ValidatePhase("Ready")).ToNot(Succeed())
+		})
+
+	})
+
+})
@@ -3,8 +3,12 @@ package controller
 // This package contains predicates which can be used for constructing controllers.
 
 import (
+	"encoding/json"
 	"reflect"
+	"slices"
 
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -52,6 +56,81 @@ func (t *typedPredicateConverter[Obj]) Update(e event.TypedUpdateEvent[Obj]) boo
 	})
 }
 
+///////////////////
+/// COMBINATION ///
+///////////////////
+
+// AnyOf returns a predicate that returns true if at least one of the given predicates returns true.
+// The predicates are evaluated in order and evaluation stops at the first predicate that returns true.
+// This applies to all event types. Nil predicates are ignored. If no predicates are given, the returned predicate always returns false.
+func AnyOf(preds ...predicate.Predicate) predicate.Predicate {
+	return &combinedPredicate{preds: preds, matchAny: true}
+}
+
+// AllOf returns a predicate that returns true if all of the given predicates return true.
+// The predicates are evaluated in order and evaluation stops at the first predicate that returns false.
+// This applies to all event types. Nil predicates are ignored. If no predicates are given, the returned predicate always returns true.
+func AllOf(preds ...predicate.Predicate) predicate.Predicate {
+	return &combinedPredicate{preds: preds, matchAny: false}
+}
+
+// combinedPredicate combines multiple predicates.
+// If matchAny is true, it returns true if any of the predicates returns true, otherwise it returns true only if all of them return true.
+type combinedPredicate struct {
+	preds    []predicate.Predicate
+	matchAny bool
+}
+
+var _ predicate.Predicate = &combinedPredicate{}
+
+// evaluate calls f for each non-nil predicate, short-circuiting as soon as the result is determined.
+func (c *combinedPredicate) evaluate(f func(p predicate.Predicate) bool) bool {
+	for _, p := range c.preds {
+		if p == nil {
+			continue
+		}
+		if f(p) == c.matchAny {
+			return c.matchAny
+		}
+	}
+	return !c.matchAny
+}
+
+func (c *combinedPredicate) Create(e event.CreateEvent) bool {
+	return c.evaluate(func(p predicate.Predicate) bool { return p.Create(e) })
+}
+
+func (c *combinedPredicate) Delete(e event.DeleteEvent) bool {
+	return c.evaluate(func(p predicate.Predicate) bool { return p.Delete(e) })
+}
+
+func (c *combinedPredicate) Update(e event.UpdateEvent) bool {
+	return c.evaluate(func(p predicate.Predicate) bool { return p.Update(e) })
+}
+
+func (c *combinedPredicate) Generic(e event.GenericEvent) bool {
+	return c.evaluate(func(p predicate.Predicate) bool { return p.Generic(e) })
+}
+
+// DefaultPredicates returns a sensible default event filter for controllers.
+// Update events pass if the generation, the status, or the deletion timestamp of the object changed,
+// unless the update was caused solely by the given field manager (see IgnoreOwnUpdatesPredicate).
+// If fieldManager is empty, own updates are not filtered out.
+// Create, delete, and generic events always pass.
+// Use it with the controller builder's WithEventFilter method:
+//
+//	ctrl.NewControllerManagedBy(mgr).For(&MyType{}).WithEventFilter(controller.DefaultPredicates("my-controller")).Complete(r)
+func DefaultPredicates(fieldManager string) predicate.Predicate {
+	var ignoreOwnUpdates predicate.Predicate
+	if fieldManager != "" {
+		ignoreOwnUpdates = IgnoreOwnUpdatesPredicate(fieldManager)
+	}
+	return AllOf(
+		AnyOf(predicate.GenerationChangedPredicate{}, StatusChangedPredicate{}, DeletionTimestampChangedPredicate{}),
+		ignoreOwnUpdates,
+	)
+}
+
 /////////////////////////////////////
 /// DELETION TIMESTAMP PREDICATES ///
 /////////////////////////////////////
@@ -72,6 +151,103 @@ func (DeletionTimestampChangedPredicate) Update(e event.UpdateEvent) bool {
 	return !reflect.DeepEqual(newDel, oldDel)
 }
 
+////////////////////////////
+/// FINALIZER PREDICATES ///
+////////////////////////////
+
+// OnlyFinalizersChangedPredicate filters out update events where only the finalizers of the object changed.
+// It returns false if the finalizers differ between old and new object and everything else (ignoring resourceVersion and managedFields) is equal.
+// In all other cases, it returns true.
+type OnlyFinalizersChangedPredicate struct {
+	predicate.Funcs
+}
+
+var _ predicate.Predicate = OnlyFinalizersChangedPredicate{}
+
+func (OnlyFinalizersChangedPredicate) Update(e event.UpdateEvent) bool {
+	if e.ObjectOld == nil || e.ObjectNew == nil {
+		return true
+	}
+	if equality.Semantic.DeepEqual(e.ObjectOld.GetFinalizers(), e.ObjectNew.GetFinalizers()) {
+		// finalizers did not change
+		return true
+	}
+	oldObj, ok := e.ObjectOld.DeepCopyObject().(client.Object)
+	if !ok {
+		return true
+	}
+	newObj, ok := e.ObjectNew.DeepCopyObject().(client.Object)
+	if !ok {
+		return true
+	}
+	for _, obj := range []client.Object{oldObj, newObj} {
+		obj.SetFinalizers(nil)
+		obj.SetResourceVersion("")
+		obj.SetManagedFields(nil)
+	}
+	return !equality.Semantic.DeepEqual(oldObj, newObj)
+}
+
+////////////////////////////////
+/// FIELD MANAGER PREDICATES ///
+////////////////////////////////
+
+// IgnoreOwnUpdatesPredicate returns a predicate that filters out update events caused solely by the given field manager.
+// This can be used to prevent a controller from being triggered by its own writes, e.g. status updates.
+// The managedFields entries of the new object are compared to the ones of the old object to determine which managers changed the object.
+// If no entry changed, the entries with the most recent timestamp are considered instead.
+// The predicate returns false if all of these entries belong to the given field manager, and true otherwise (including if the object does not have any managedFields).
+// Create, delete, and generic events are not filtered.
+func IgnoreOwnUpdatesPredicate(fieldManager string) predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if e.ObjectOld == nil || e.ObjectNew == nil {
+				return true
+			}
+			changed := changedManagedFields(e.ObjectOld.GetManagedFields(), e.ObjectNew.GetManagedFields())
+			if len(changed) == 0 {
+				return true
+			}
+			for _, mf := range changed {
+				if mf.Manager != fieldManager {
+					return true
+				}
+			}
+			return false
+		},
+	}
+}
+
+// changedManagedFields returns the managedFields entries of the new object which don't have an equal counterpart in the old object.
+// If all entries are unchanged, the entries with the most recent timestamp are returned instead.
+func changedManagedFields(oldFields, newFields []metav1.ManagedFieldsEntry) []metav1.ManagedFieldsEntry {
+	res := []metav1.ManagedFieldsEntry{}
+	for _, nmf := range newFields {
+		if !slices.ContainsFunc(oldFields, func(omf metav1.ManagedFieldsEntry) bool {
+			return equality.Semantic.DeepEqual(omf, nmf)
+		}) {
+			res = append(res, nmf)
+		}
+	}
+	if len(res) > 0 {
+		return res
+	}
+	var latest *metav1.Time
+	for _, nmf := range newFields {
+		if nmf.Time == nil {
+			continue
+		}
+		if latest == nil || latest.Before(nmf.Time) {
+			latest = nmf.Time
+			res = res[:0]
+		}
+		if latest.Equal(nmf.Time) {
+			res = append(res, nmf)
+		}
+	}
+	return res
+}
+
 ///////////////////////////////////////
 /// ANNOTATION AND LABEL PREDICATES ///
 ///////////////////////////////////////
@@ -148,6 +324,28 @@ func LostAnnotationPredicate(key, val string) predicate.Predicate {
 	}
 }
 
+// HasAnnotationJSONFieldPredicate reacts if the resource has the specified annotation, its value is a JSON object, and that object contains the specified top-level field.
+// The value of the field doesn't matter, only its existence.
+// This is useful for feature-flag style gating, where multiple flags are stored as JSON in a single annotation.
+// If the annotation doesn't exist or its value cannot be parsed as a JSON object, false is returned.
+func HasAnnotationJSONFieldPredicate(annKey, jsonField string) predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		if obj == nil {
+			return false
+		}
+		value, ok := getMetadataEntry(ANNOTATION, obj, annKey)
+		if !ok {
+			return false
+		}
+		fields := map[string]json.RawMessage{}
+		if err := json.Unmarshal([]byte(value), &fields); err != nil {
+			return false
+		}
+		_, ok = fields[jsonField]
+		return ok
+	})
+}
+
 // HasLabelPredicate reacts if the resource has the specified label.
 // If val is empty, the value of the label doesn't matter, only its existence.
 // Otherwise, true is only returned if the label has the specified value.
@@ -203,21 +401,68 @@ func LabelSelectorPredicate(sel labels.Selector) predicate.Predicate {
 var _ predicate.Predicate = StatusChangedPredicate{}
 
 // StatusChangedPredicate returns true if the object's status changed.
-// Getting the status is done via reflection and only works if the corresponding field is named 'Status'.
+// Getting the status is done via reflection and only works if the corresponding field is named 'Status',
+// unless a different field name is specified via StatusFieldName.
 // If getting the status fails, this predicate always returns true.
 type StatusChangedPredicate struct {
 	predicate.Funcs
+
+	// StatusFieldName is the name of the field that holds the object's status.
+	// Nested fields can be specified by separating the field names with a dot, e.g. 'Observed.Status'.
+	// Defaults to 'Status' if empty.
+	StatusFieldName string
 }
 
 func (p StatusChangedPredicate) Update(e event.UpdateEvent) bool {
-	oldStatus := GetField(e.ObjectOld, "Status", false)
-	newStatus := GetField(e.ObjectNew, "Status", false)
+	field := p.StatusFieldName
+	if field == "" {
+		field = "Status"
+	}
+	oldStatus := getFieldOrNil(e.ObjectOld, field)
+	newStatus := getFieldOrNil(e.ObjectNew, field)
 	if oldStatus == nil || newStatus == nil {
 		return true
 	}
 	return !reflect.DeepEqual(oldStatus, newStatus)
 }
 
+///////////////////////
+/// SPEC PREDICATES ///
+///////////////////////
+
+var _ predicate.Predicate = SpecChangedPredicate{}
+
+// SpecChangedPredicate returns true if the object's spec changed.
+// In contrast to controller-runtime's GenerationChangedPredicate, it does not rely on the generation being increased,
+// but compares the spec of the old and new object directly.
+// Getting the spec is done via reflection and only works if the corresponding field is named 'Spec'.
+// If getting the spec fails, this predicate always returns true.
+type SpecChangedPredicate struct {
+	predicate.Funcs
+}
+
+func (p SpecChangedPredicate) Update(e event.UpdateEvent) bool {
+	oldSpec := getFieldOrNil(e.ObjectOld, "Spec")
+	newSpec := getFieldOrNil(e.ObjectNew, "Spec")
+	if oldSpec == nil || newSpec == nil {
+		return true
+	}
+	return !reflect.DeepEqual(oldSpec, newSpec)
+}
+
+// getFieldOrNil works like GetField, but returns nil instead of panicking if the field cannot be found.
+func getFieldOrNil(obj any, field string) (res any) {
+	if obj == nil {
+		return nil
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			res = nil
+		}
+	}()
+	return GetField(obj, field, false)
+}
+
 ////////////////////////////////////
 /// IDENTITY MATCHING PREDICATES ///
 ////////////////////////////////////
@@ -1,15 +1,19 @@
 package controller_test
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	ctrlutils "github.com/openmcp-project/controller-utils/pkg/controller"
 )
@@ -38,6 +42,100 @@ var _ = Describe("Predicates", func() {
 
 	})
 
+	Context("Finalizers", func() {
+
+		It("should skip updates where only the finalizers changed", func() {
+			p := ctrlutils.OnlyFinalizersChangedPredicate{}
+			changed.SetFinalizers([]string{"foo.bar/finalizer"})
+			changed.SetResourceVersion("2")
+			Expect(p.Update(updateEvent(base, changed))).To(BeFalse(), "OnlyFinalizersChangedPredicate should return false if only the finalizers changed")
+		})
+
+		It("should not skip updates where finalizers and spec changed", func() {
+			p := ctrlutils.OnlyFinalizersChangedPredicate{}
+			changed.SetFinalizers([]string{"foo.bar/finalizer"})
+			changed.Spec.ClusterIP = "10.0.0.1"
+			Expect(p.Update(updateEvent(base, changed))).To(BeTrue(), "OnlyFinalizersChangedPredicate should return true if the finalizers and the spec changed")
+		})
+
+		It("should not skip updates where the finalizers did not change", func() {
+			p := ctrlutils.OnlyFinalizersChangedPredicate{}
+			Expect(p.Update(updateEvent(base, changed))).To(BeTrue(), "OnlyFinalizersChangedPredicate should return true if nothing changed")
+			changed.Spec.ClusterIP = "10.0.0.1"
+			Expect(p.Update(updateEvent(base, changed))).To(BeTrue(), "OnlyFinalizersChangedPredicate should return true if only the spec changed")
+		})
+
+	})
+
+	Context("Field Manager", func() {
+
+		managedFields := func(manager string, t time.Time, fields string) metav1.ManagedFieldsEntry {
+			return metav1.ManagedFieldsEntry{
+				Manager:    manager,
+				Operation:  metav1.ManagedFieldsOperationUpdate,
+				APIVersion: "v1",
+				Time:       ptr.To(metav1.NewTime(t)),
+				FieldsType: "FieldsV1",
+				FieldsV1:   &metav1.FieldsV1{Raw: []byte(fields)},
+			}
+		}
+
+		var now time.Time
+
+		BeforeEach(func() {
+			now = time.Now().Truncate(time.Second)
+			base.SetManagedFields([]metav1.ManagedFieldsEntry{
+				managedFields("my-controller", now.Add(-time.Hour), `{"f:status":{}}`),
+				managedFields("kubectl", now.Add(-time.Hour), `{"f:spec":{}}`),
+			})
+			changed = base.DeepCopy()
+		})
+
+		It("should skip updates attributed to the given field manager", func() {
+			p := ctrlutils.IgnoreOwnUpdatesPredicate("my-controller")
+			changed.SetManagedFields([]metav1.ManagedFieldsEntry{
+				managedFields("my-controller", now, `{"f:status":{"f:loadBalancer":{}}}`),
+				managedFields("kubectl", now.Add(-time.Hour), `{"f:spec":{}}`),
+			})
+			Expect(p.Update(updateEvent(base, changed))).To(BeFalse(), "IgnoreOwnUpdatesPredicate should return false if only the own field manager changed the object")
+		})
+
+		It("should not skip updates attributed to another field manager", func() {
+			p := ctrlutils.IgnoreOwnUpdatesPredicate("my-controller")
+			changed.SetManagedFields([]metav1.ManagedFieldsEntry{
+				managedFields("my-controller", now.Add(-time.Hour), `{"f:status":{}}`),
+				managedFields("kubectl", now, `{"f:spec":{"f:clusterIP":{}}}`),
+			})
+			Expect(p.Update(updateEvent(base, changed))).To(BeTrue(), "IgnoreOwnUpdatesPredicate should return true if another field manager changed the object")
+
+			changed.SetManagedFields([]metav1.ManagedFieldsEntry{
+				managedFields("my-controller", now, `{"f:status":{"f:loadBalancer":{}}}`),
+				managedFields("kubectl", now, `{"f:spec":{"f:clusterIP":{}}}`),
+			})
+			Expect(p.Update(updateEvent(base, changed))).To(BeTrue(), "IgnoreOwnUpdatesPredicate should return true if the own and another field manager changed the object")
+		})
+
+		It("should fall back to the most recent entries if the managed fields did not change", func() {
+			p := ctrlutils.IgnoreOwnUpdatesPredicate("my-controller")
+			base.SetManagedFields([]metav1.ManagedFieldsEntry{
+				managedFields("my-controller", now, `{"f:status":{}}`),
+				managedFields("kubectl", now.Add(-time.Hour), `{"f:spec":{}}`),
+			})
+			changed = base.DeepCopy()
+			Expect(p.Update(updateEvent(base, changed))).To(BeFalse(), "IgnoreOwnUpdatesPredicate should return false if the most recent entry belongs to the own field manager")
+			Expect(ctrlutils.IgnoreOwnUpdatesPredicate("kubectl").Update(updateEvent(base, changed))).To(BeTrue(), "IgnoreOwnUpdatesPredicate should return true if the most recent entry belongs to another field manager")
+		})
+
+		It("should not skip updates of objects without managed fields", func() {
+			p := ctrlutils.IgnoreOwnUpdatesPredicate("my-controller")
+			base.SetManagedFields(nil)
+			changed.SetManagedFields(nil)
+			Expect(p.Update(updateEvent(base, changed))).To(BeTrue())
+			Expect(p.Create(event.CreateEvent{Object: base})).To(BeTrue())
+		})
+
+	})
+
 	Context("Annotations", func() {
 
 		It("should detect changes to the annotations", func() {
@@ -110,6 +208,40 @@ var _ = Describe("Predicates", func() {
 
 	})
 
+	Context("Annotation JSON Fields", func() {
+
+		It("should react if the annotation contains the JSON field", func() {
+			p := ctrlutils.HasAnnotationJSONFieldPredicate("flags", "foo")
+			base.SetAnnotations(map[string]string{
+				"flags": `{"foo": false, "bar": {"baz": true}}`,
+			})
+			Expect(p.Create(event.CreateEvent{Object: base})).To(BeTrue())
+			Expect(ctrlutils.HasAnnotationJSONFieldPredicate("flags", "bar").Create(event.CreateEvent{Object: base})).To(BeTrue())
+		})
+
+		It("should not react if the JSON field is absent", func() {
+			p := ctrlutils.HasAnnotationJSONFieldPredicate("flags", "baz")
+			base.SetAnnotations(map[string]string{
+				"flags": `{"foo": false, "bar": {"baz": true}}`,
+			})
+			Expect(p.Create(event.CreateEvent{Object: base})).To(BeFalse(), "nested fields should not be considered")
+			Expect(p.Create(event.CreateEvent{Object: changed})).To(BeFalse(), "missing annotation should not match")
+		})
+
+		It("should not react if the annotation is not a JSON object", func() {
+			p := ctrlutils.HasAnnotationJSONFieldPredicate("flags", "foo")
+			base.SetAnnotations(map[string]string{
+				"flags": "foo",
+			})
+			Expect(p.Create(event.CreateEvent{Object: base})).To(BeFalse())
+			base.SetAnnotations(map[string]string{
+				"flags": `["foo"]`,
+			})
+			Expect(p.Create(event.CreateEvent{Object: base})).To(BeFalse())
+		})
+
+	})
+
 	Context("Labels", func() {
 
 		It("should detect changes to the labels", func() {
@@ -232,6 +364,58 @@ var _ = Describe("Predicates", func() {
 			Expect(p.Update(updateEvent(base, changed))).To(BeTrue(), "StatusChangedPredicate should return true if the status changed")
 		})
 
+		It("should detect changes to the status if the status field has a different name", func() {
+			oldObj := &RenamedStatusObject{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"},
+				Observed: CustomObjectStatus{
+					Phase: PhaseSucceeded,
+				},
+			}
+			newObj := oldObj.DeepCopyObject().(*RenamedStatusObject)
+			newObj.Labels = map[string]string{"foo": "bar"}
+			p := ctrlutils.StatusChangedPredicate{StatusFieldName: "Observed"}
+			Expect(p.Update(updateEvent(oldObj, newObj))).To(BeFalse(), "StatusChangedPredicate should return false if the status did not change")
+			By("change status")
+			newObj.Observed.Phase = PhaseFailed
+			Expect(p.Update(updateEvent(oldObj, newObj))).To(BeTrue(), "StatusChangedPredicate should return true if the status changed")
+			By("use nested field name")
+			p = ctrlutils.StatusChangedPredicate{StatusFieldName: "Observed.Phase"}
+			Expect(p.Update(updateEvent(oldObj, newObj))).To(BeTrue(), "StatusChangedPredicate should return true if the status changed")
+			newObj.Observed.Phase = PhaseSucceeded
+			newObj.Observed.Message = "foo"
+			Expect(p.Update(updateEvent(oldObj, newObj))).To(BeFalse(), "StatusChangedPredicate should only compare the specified field")
+		})
+
+	})
+
+	Context("Spec", func() {
+
+		It("should detect changes to the spec", func() {
+			p := ctrlutils.SpecChangedPredicate{}
+			Expect(p.Update(updateEvent(base, changed))).To(BeFalse(), "SpecChangedPredicate should return false if the spec did not change")
+			By("change status only")
+			changed.Status = corev1.ServiceStatus{
+				LoadBalancer: corev1.LoadBalancerStatus{
+					Ingress: []corev1.LoadBalancerIngress{
+						{
+							IP: "127.0.0.1",
+						},
+					},
+				},
+			}
+			Expect(p.Update(updateEvent(base, changed))).To(BeFalse(), "SpecChangedPredicate should return false if only the status changed")
+			By("change spec")
+			changed = base.DeepCopy()
+			changed.Spec.Type = corev1.ServiceTypeClusterIP
+			Expect(p.Update(updateEvent(base, changed))).To(BeTrue(), "SpecChangedPredicate should return true if the spec changed")
+		})
+
+		It("should return true if the object does not have a spec", func() {
+			p := ctrlutils.SpecChangedPredicate{}
+			oldObj := &RenamedStatusObject{}
+			Expect(p.Update(updateEvent(oldObj, oldObj.DeepCopyObject().(*RenamedStatusObject)))).To(BeTrue())
+		})
+
 	})
 
 	Context("Identity", func() {
@@ -253,6 +437,109 @@ var _ = Describe("Predicates", func() {
 
 	})
 
+	Context("Combination", func() {
+
+		It("should combine predicates with AnyOf and AllOf on update events", func() {
+			hasAnnotation := ctrlutils.HasAnnotationPredicate("foo.bar.baz/foo", "bar")
+			hasLabel := ctrlutils.HasLabelPredicate("foo.bar.baz/foo", "bar")
+			anyOf := ctrlutils.AnyOf(hasAnnotation, hasLabel)
+			allOf := ctrlutils.AllOf(hasAnnotation, hasLabel)
+
+			Expect(anyOf.Update(updateEvent(base, changed))).To(BeFalse(), "AnyOf should return false if none of the predicates match")
+			Expect(allOf.Update(updateEvent(base, changed))).To(BeFalse(), "AllOf should return false if none of the predicates match")
+
+			By("add annotation")
+			changed.SetAnnotations(map[string]string{"foo.bar.baz/foo": "bar"})
+			Expect(anyOf.Update(updateEvent(base, changed))).To(BeTrue(), "AnyOf should return true if one of the predicates matches")
+			Expect(allOf.Update(updateEvent(base, changed))).To(BeFalse(), "AllOf should return false if only one of the predicates matches")
+
+			By("add label")
+			changed.SetLabels(map[string]string{"foo.bar.baz/foo": "bar"})
+			Expect(anyOf.Update(updateEvent(base, changed))).To(BeTrue(), "AnyOf should return true if all of the predicates match")
+			Expect(allOf.Update(updateEvent(base, changed))).To(BeTrue(), "AllOf should return true if all of the predicates match")
+		})
+
+		It("should short-circuit and respect all event types", func() {
+			evaluated := 0
+			counting := func(res bool) predicate.Predicate {
+				return predicate.NewPredicateFuncs(func(_ client.Object) bool {
+					evaluated++
+					return res
+				})
+			}
+			Expect(ctrlutils.AnyOf(counting(true), counting(false)).Create(event.CreateEvent{Object: base})).To(BeTrue())
+			Expect(evaluated).To(Equal(1))
+			evaluated = 0
+			Expect(ctrlutils.AllOf(counting(false), counting(true)).Delete(event.DeleteEvent{Object: base})).To(BeFalse())
+			Expect(evaluated).To(Equal(1))
+			evaluated = 0
+			Expect(ctrlutils.AllOf(counting(true), nil, counting(true)).Generic(event.GenericEvent{Object: base})).To(BeTrue())
+			Expect(evaluated).To(Equal(2))
+
+			Expect(ctrlutils.AnyOf(ctrlutils.OnCreatePredicate(), ctrlutils.OnDeletePredicate()).Generic(event.GenericEvent{Object: base})).To(BeFalse())
+			Expect(ctrlutils.AnyOf(ctrlutils.OnCreatePredicate(), ctrlutils.OnDeletePredicate()).Delete(event.DeleteEvent{Object: base})).To(BeTrue())
+			Expect(ctrlutils.AnyOf().Create(event.CreateEvent{Object: base})).To(BeFalse())
+			Expect(ctrlutils.AllOf().Create(event.CreateEvent{Object: base})).To(BeTrue())
+		})
+
+	})
+
+	Context("DefaultPredicates", func() {
+
+		ownUpdate := func(obj client.Object, manager string) {
+			obj.SetManagedFields([]metav1.ManagedFieldsEntry{
+				{
+					Manager:    manager,
+					Operation:  metav1.ManagedFieldsOperationUpdate,
+					APIVersion: "v1",
+					Time:       ptr.To(metav1.Now()),
+					FieldsType: "FieldsV1",
+					FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:status":{}}`)},
+				},
+			})
+		}
+
+		It("should pass create, delete, and generic events", func() {
+			p := ctrlutils.DefaultPredicates("my-controller")
+			Expect(p.Create(event.CreateEvent{Object: base})).To(BeTrue())
+			Expect(p.Delete(event.DeleteEvent{Object: base})).To(BeTrue())
+			Expect(p.Generic(event.GenericEvent{Object: base})).To(BeTrue())
+		})
+
+		It("should filter update events which don't change generation, status, or deletion timestamp", func() {
+			p := ctrlutils.DefaultPredicates("my-controller")
+			Expect(p.Update(updateEvent(base, changed))).To(BeFalse(), "nothing changed")
+			changed.SetLabels(map[string]string{"foo": "bar"})
+			Expect(p.Update(updateEvent(base, changed))).To(BeFalse(), "only labels changed")
+
+			changed = base.DeepCopy()
+			changed.SetGeneration(2)
+			Expect(p.Update(updateEvent(base, changed))).To(BeTrue(), "generation changed")
+
+			changed = base.DeepCopy()
+			changed.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "10.0.0.1"}}
+			Expect(p.Update(updateEvent(base, changed))).To(BeTrue(), "status changed")
+
+			changed = base.DeepCopy()
+			changed.SetDeletionTimestamp(ptr.To(metav1.Now()))
+			Expect(p.Update(updateEvent(base, changed))).To(BeTrue(), "deletion timestamp changed")
+		})
+
+		It("should filter update events caused by the own field manager", func() {
+			p := ctrlutils.DefaultPredicates("my-controller")
+			changed.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "10.0.0.1"}}
+			ownUpdate(changed, "my-controller")
+			Expect(p.Update(updateEvent(base, changed))).To(BeFalse(), "status changed by own field manager")
+
+			ownUpdate(changed, "someone-else")
+			Expect(p.Update(updateEvent(base, changed))).To(BeTrue(), "status changed by another field manager")
+
+			ownUpdate(changed, "my-controller")
+			Expect(ctrlutils.DefaultPredicates("").Update(updateEvent(base, changed))).To(BeTrue(), "own updates should not be filtered without field manager")
+		})
+
+	})
+
 	Context("Event Types", func() {
 
 		It("should match only create events", func() {
@@ -297,3 +584,22 @@ func updateEvent(old, new client.Object) event.UpdateEvent {
 		ObjectNew: new,
 	}
 }
+
+var _ client.Object = &RenamedStatusObject{}
+
+// RenamedStatusObject is a dummy k8s object whose status is not stored in a field named 'Status'.
+type RenamedStatusObject struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Observed CustomObjectStatus `json:"observed,omitempty"`
+}
+
+// DeepCopyObject copies the receiver, creating a new runtime.Object.
+func (in *RenamedStatusObject) DeepCopyObject() runtime.Object {
+	out := &RenamedStatusObject{}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Observed.DeepCopyInto(&out.Observed)
+	return out
+}
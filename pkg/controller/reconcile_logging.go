@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"slices"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+)
+
+// LogReconcileResult logs a single message summarizing the outcome of a reconciliation.
+// The given ReconcileResult is expected to contain the updated object, e.g. after the status has been updated via the status updater,
+// and res is the result returned by the reconciliation.
+// The log line contains the following keys:
+//   - phase: the value of 'status.phase' of the object, if any
+//   - reason: the reason from the ReconcileResult, or the one from the reconcile error if not set
+//   - requeue: whether the object will be requeued, and requeueAfter: the requeue duration, if set
+//   - conditions: the conditions set by the ReconcileResult in the format '<type>=<status>', and removedConditions: the types of removed conditions
+//
+// If the ReconcileResult contains an error, the message is logged at error level, together with the error. Otherwise, it is logged at info level.
+func LogReconcileResult[Obj client.Object](log logging.Logger, rr ReconcileResult[Obj], res ctrl.Result) {
+	phase := ""
+	if !IsNil(rr.Object) {
+		// errors are ignored, since the phase is purely informational here
+		phase, _ = PhaseColumn(rr.Object, "status.phase")
+	}
+	reason := rr.Reason
+	if reason == "" && rr.ReconcileError != nil {
+		reason = rr.ReconcileError.Reason()
+	}
+	cons := make([]string, len(rr.Conditions))
+	for i, con := range rr.Conditions {
+		cons[i] = con.Type + "=" + string(con.Status)
+	}
+	removed := slices.Clone(rr.ConditionsToRemove)
+	if removed == nil {
+		removed = []string{}
+	}
+	requeue := res.Requeue || res.RequeueAfter > 0 //nolint:staticcheck
+
+	keysAndValues := []any{
+		"phase", phase,
+		"reason", reason,
+		"requeue", requeue,
+		"conditions", cons,
+		"removedConditions", removed,
+	}
+	if res.RequeueAfter > 0 {
+		keysAndValues = append(keysAndValues, "requeueAfter", res.RequeueAfter.String())
+	}
+
+	if rr.ReconcileError != nil {
+		log.Error(rr.ReconcileError, "Reconciliation failed", keysAndValues...)
+		return
+	}
+	log.Info("Reconciliation finished", keysAndValues...)
+}
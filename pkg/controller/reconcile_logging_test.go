@@ -0,0 +1,98 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr/funcr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/openmcp-project/controller-utils/pkg/controller"
+	"github.com/openmcp-project/controller-utils/pkg/errors"
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+)
+
+// newCapturingLogger returns a logger which stores every log line as parsed JSON in the returned slice.
+func newCapturingLogger() (logging.Logger, *[]map[string]any) {
+	lines := &[]map[string]any{}
+	log := funcr.NewJSON(func(obj string) {
+		line := map[string]any{}
+		Expect(json.Unmarshal([]byte(obj), &line)).To(Succeed())
+		*lines = append(*lines, line)
+	}, funcr.Options{Verbosity: logging.LevelToVerbosity(logging.DEBUG)})
+	return logging.Wrap(log), lines
+}
+
+var _ = Describe("LogReconcileResult", func() {
+
+	It("should log a successful reconciliation at info level", func() {
+		log, lines := newCapturingLogger()
+		obj := &CustomObject{}
+		obj.Status.Phase = PhaseSucceeded
+		rr := controller.ReconcileResult[*CustomObject]{
+			Object: obj,
+			Reason: "AllGood",
+			Conditions: []metav1.Condition{
+				{Type: "Ready", Status: metav1.ConditionTrue},
+				{Type: "Healthy", Status: metav1.ConditionUnknown},
+			},
+			ConditionsToRemove: []string{"Outdated"},
+		}
+		controller.LogReconcileResult(log, rr, ctrl.Result{RequeueAfter: 30 * time.Second})
+
+		Expect(*lines).To(HaveLen(1))
+		line := (*lines)[0]
+		Expect(line).To(HaveKeyWithValue("msg", "Reconciliation finished"))
+		Expect(line).To(HaveKeyWithValue("level", BeEquivalentTo(logging.LevelToVerbosity(logging.INFO))))
+		Expect(line).ToNot(HaveKey("error"))
+		Expect(line).To(HaveKeyWithValue("phase", PhaseSucceeded))
+		Expect(line).To(HaveKeyWithValue("reason", "AllGood"))
+		Expect(line).To(HaveKeyWithValue("requeue", true))
+		Expect(line).To(HaveKeyWithValue("requeueAfter", "30s"))
+		Expect(line).To(HaveKeyWithValue("conditions", ConsistOf("Ready=True", "Healthy=Unknown")))
+		Expect(line).To(HaveKeyWithValue("removedConditions", ConsistOf("Outdated")))
+	})
+
+	It("should log a failed reconciliation at error level", func() {
+		log, lines := newCapturingLogger()
+		obj := &CustomObject{}
+		obj.Status.Phase = PhaseFailed
+		rr := controller.ReconcileResult[*CustomObject]{
+			Object:         obj,
+			ReconcileError: errors.WithReason(fmt.Errorf("something went wrong"), "SomethingWrong"),
+			Conditions: []metav1.Condition{
+				{Type: "Ready", Status: metav1.ConditionFalse},
+			},
+		}
+		controller.LogReconcileResult(log, rr, ctrl.Result{})
+
+		Expect(*lines).To(HaveLen(1))
+		line := (*lines)[0]
+		Expect(line).To(HaveKeyWithValue("msg", "Reconciliation failed"))
+		Expect(line).To(HaveKeyWithValue("error", "something went wrong"))
+		Expect(line).To(HaveKeyWithValue("phase", PhaseFailed))
+		Expect(line).To(HaveKeyWithValue("reason", "SomethingWrong"))
+		Expect(line).To(HaveKeyWithValue("requeue", false))
+		Expect(line).ToNot(HaveKey("requeueAfter"))
+		Expect(line).To(HaveKeyWithValue("conditions", ConsistOf("Ready=False")))
+		Expect(line).To(HaveKeyWithValue("removedConditions", BeEmpty()))
+	})
+
+	It("should not fail if the object is nil", func() {
+		log, lines := newCapturingLogger()
+		controller.LogReconcileResult(log, controller.ReconcileResult[*CustomObject]{}, ctrl.Result{})
+
+		Expect(*lines).To(HaveLen(1))
+		line := (*lines)[0]
+		Expect(line).To(HaveKeyWithValue("msg", "Reconciliation finished"))
+		Expect(line).To(HaveKeyWithValue("phase", ""))
+		Expect(line).To(HaveKeyWithValue("reason", ""))
+		Expect(line).To(HaveKeyWithValue("conditions", BeEmpty()))
+	})
+
+})
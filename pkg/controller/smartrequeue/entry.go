@@ -1,6 +1,7 @@
 package smartrequeue
 
 import (
+	"sync"
 	"time"
 
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -11,6 +12,7 @@ import (
 type Entry struct {
 	store        *Store
 	nextDuration time.Duration
+	mu           sync.Mutex // protects nextDuration
 }
 
 func newEntry(s *Store) *Entry {
@@ -23,6 +25,9 @@ func newEntry(s *Store) *Entry {
 // ReturnError resets the backoff to minInterval and returns the given error,
 // delegating backoff handling to controller-runtime.
 func (e *Entry) ReturnError(err error) (ctrl.Result, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
 	e.nextDuration = e.store.minInterval
 	return ctrl.Result{}, err
 }
@@ -31,13 +36,16 @@ func (e *Entry) ReturnError(err error) (ctrl.Result, error) {
 // the current interval and increases the interval for the next call, implementing
 // exponential backoff.
 func (e *Entry) IsStable() (ctrl.Result, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
 	// Save current duration for result
 	current := e.nextDuration
 
 	// Schedule calculation of next duration
 	defer e.setNext()
 
-	return ctrl.Result{RequeueAfter: current}, nil
+	return ctrl.Result{RequeueAfter: e.store.withJitter(current)}, nil
 }
 
 // RequeueWithBackoff requeues after the current interval and increases the interval for the next call.
@@ -49,9 +57,12 @@ func (e *Entry) RequeueWithBackoff() (ctrl.Result, error) {
 // IsProgressing indicates the resource is actively changing. It resets the backoff
 // to minInterval and requeues after that interval.
 func (e *Entry) IsProgressing() (ctrl.Result, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
 	e.nextDuration = e.store.minInterval
 	defer e.setNext()
-	return ctrl.Result{RequeueAfter: e.nextDuration}, nil
+	return ctrl.Result{RequeueAfter: e.store.withJitter(e.nextDuration)}, nil
 }
 
 // RequeueWithReset requeues after the minimum interval and resets the backoff for the next call.
@@ -70,6 +81,7 @@ func (e *Entry) StopRequeue() (ctrl.Result, error) {
 // setNext updates the next requeue duration using exponential backoff.
 // It multiplies the current duration by the store's multiplier and ensures
 // the result doesn't exceed the configured maximum interval.
+// The caller must hold the entry's lock.
 func (e *Entry) setNext() {
 	newDuration := time.Duration(float32(e.nextDuration) * e.store.multiplier)
 
@@ -79,3 +91,11 @@ func (e *Entry) setNext() {
 
 	e.nextDuration = newDuration
 }
+
+// peek returns the next requeue duration without modifying it.
+func (e *Entry) peek() time.Duration {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.nextDuration
+}
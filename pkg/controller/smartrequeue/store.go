@@ -1,6 +1,7 @@
 package smartrequeue
 
 import (
+	"math/rand/v2"
 	"reflect"
 	"sync"
 	"time"
@@ -14,13 +15,23 @@ type Store struct {
 	minInterval time.Duration
 	maxInterval time.Duration
 	multiplier  float32
+	jitter      float64
 	objects     map[key]*Entry
 	mu          sync.RWMutex // Using RWMutex for better read concurrency
 }
 
 // NewStore creates a new Store with the specified minimum and maximum intervals
 // and a multiplier for the exponential backoff logic.
+// The returned requeue intervals are not randomized, use NewStoreWithJitter for that.
 func NewStore(minInterval, maxInterval time.Duration, multiplier float32) *Store {
+	return NewStoreWithJitter(minInterval, maxInterval, multiplier, 0)
+}
+
+// NewStoreWithJitter works like NewStore, but randomizes each returned requeue interval
+// by up to +/- jitterFraction of its value (e.g. 0.1 for +/- 10%).
+// This prevents many objects which have been requeued at the same time from being reconciled at the same time again.
+// The jitter does not influence the backoff logic itself.
+func NewStoreWithJitter(minInterval, maxInterval time.Duration, multiplier float32, jitterFraction float64) *Store {
 	if minInterval <= 0 {
 		minInterval = 1 * time.Second // Safe default
 	}
@@ -33,10 +44,17 @@ func NewStore(minInterval, maxInterval time.Duration, multiplier float32) *Store
 		multiplier = 2.0 // Safe default: double each time
 	}
 
+	if jitterFraction < 0 {
+		jitterFraction = 0
+	} else if jitterFraction > 1 {
+		jitterFraction = 1 // Never return negative intervals
+	}
+
 	return &Store{
 		minInterval: minInterval,
 		maxInterval: maxInterval,
 		multiplier:  multiplier,
+		jitter:      jitterFraction,
 		objects:     make(map[key]*Entry),
 	}
 }
@@ -71,6 +89,43 @@ func (s *Store) For(obj client.Object) *Entry {
 	return entry
 }
 
+// Peek returns the interval that would be used for the next requeue of the specified object.
+// If the store has been created with a jitter, the returned value is the interval before the jitter is applied.
+// In contrast to For, it does not create an entry if none exists for the object
+// and it does not modify any stored state.
+func (s *Store) Peek(obj client.Object) time.Duration {
+	key := keyFromObject(obj)
+
+	s.mu.RLock()
+	entry, exists := s.objects[key]
+	s.mu.RUnlock()
+
+	if !exists {
+		return s.minInterval
+	}
+	return entry.peek()
+}
+
+// Forget removes the entry for the specified object from the store, if it exists.
+// This should be called when the object is deleted, to avoid accumulating entries for objects that don't exist anymore.
+// A subsequent call to For creates a new entry, starting with the minimum interval again.
+func (s *Store) Forget(obj client.Object) {
+	key := keyFromObject(obj)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.objects, key)
+}
+
+// Len returns the number of objects for which the store currently holds an entry.
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.objects)
+}
+
 // Clear removes all entries from the store (mainly useful for testing).
 func (s *Store) Clear() {
 	s.mu.Lock()
@@ -79,6 +134,14 @@ func (s *Store) Clear() {
 	s.objects = make(map[key]*Entry)
 }
 
+// withJitter randomizes the given duration according to the store's jitter fraction.
+func (s *Store) withJitter(d time.Duration) time.Duration {
+	if s.jitter == 0 {
+		return d
+	}
+	return time.Duration(float64(d) * (1 + s.jitter*(2*rand.Float64()-1)))
+}
+
 // deleteEntry removes an entry from the store.
 func (s *Store) deleteEntry(toDelete *Entry) {
 	s.mu.Lock()
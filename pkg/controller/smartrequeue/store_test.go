@@ -182,3 +182,160 @@ func TestConcurrentAccess(t *testing.T) {
 
 	wg.Wait()
 }
+
+// TestPeek ensures Peek returns the next interval without modifying the store
+func TestPeek(t *testing.T) {
+	store := NewStore(time.Second, time.Minute, 2)
+
+	obj := &dummyObject{
+		ObjectMeta: ctrl.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+	}
+
+	// Peek on an unknown object returns the minimum interval and doesn't create an entry
+	assert.Equal(t, 1*time.Second, store.Peek(obj))
+	assert.Equal(t, 1*time.Second, store.Peek(obj))
+	assert.Empty(t, store.objects)
+
+	entry := store.For(obj)
+	result, err := entry.RequeueWithBackoff()
+	require.NoError(t, err)
+	assert.Equal(t, 1*time.Second, getRequeueAfter(result, err))
+
+	// Peek returns the increased interval, repeatedly
+	assert.Equal(t, 2*time.Second, store.Peek(obj))
+	assert.Equal(t, 2*time.Second, store.Peek(obj))
+
+	// The next backoff uses the peeked interval
+	result, err = entry.RequeueWithBackoff()
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Second, getRequeueAfter(result, err))
+	assert.Equal(t, 4*time.Second, store.Peek(obj))
+}
+
+// TestConcurrentPeek ensures Peek can be called while the entry is being requeued (run with -race)
+func TestConcurrentPeek(t *testing.T) {
+	store := NewStore(time.Second, time.Minute, 2)
+
+	obj := &dummyObject{
+		ObjectMeta: ctrl.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+	}
+	entry := store.For(obj)
+
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		for range 100 {
+			_, _ = entry.IsStable()
+			_, _ = entry.IsProgressing()
+		}
+	})
+	wg.Go(func() {
+		for range 100 {
+			interval := store.Peek(obj)
+			assert.GreaterOrEqual(t, interval, time.Second)
+			assert.LessOrEqual(t, interval, time.Minute)
+		}
+	})
+	wg.Wait()
+}
+
+// TestNewStoreWithJitter ensures the returned intervals stay within the configured jitter band
+func TestNewStoreWithJitter(t *testing.T) {
+	const jitter = 0.2
+	store := NewStoreWithJitter(10*time.Second, 10*time.Minute, 2, jitter)
+
+	obj := &dummyObject{
+		ObjectMeta: ctrl.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+	}
+	entry := store.For(obj)
+
+	lower := time.Duration(float64(10*time.Second) * (1 - jitter))
+	upper := time.Duration(float64(10*time.Second) * (1 + jitter))
+	seen := map[time.Duration]struct{}{}
+	for range 1000 {
+		result, err := entry.IsProgressing()
+		require.NoError(t, err)
+		requeueAfter := getRequeueAfter(result, err)
+		assert.GreaterOrEqual(t, requeueAfter, lower)
+		assert.LessOrEqual(t, requeueAfter, upper)
+		seen[requeueAfter] = struct{}{}
+	}
+	assert.Greater(t, len(seen), 1, "Expected the intervals to be randomized")
+
+	// The jitter doesn't influence the backoff itself
+	_, _ = entry.IsProgressing()
+	result, err := entry.IsStable()
+	require.NoError(t, err)
+	requeueAfter := getRequeueAfter(result, err)
+	assert.GreaterOrEqual(t, requeueAfter, 2*lower)
+	assert.LessOrEqual(t, requeueAfter, 2*upper)
+	assert.Equal(t, 40*time.Second, store.Peek(obj))
+}
+
+// TestNewStoreWithoutJitter ensures that NewStore doesn't randomize the intervals
+func TestNewStoreWithoutJitter(t *testing.T) {
+	store := NewStore(time.Second, time.Minute, 2)
+
+	entry := store.For(&dummyObject{
+		ObjectMeta: ctrl.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+	})
+
+	for range 100 {
+		result, err := entry.IsProgressing()
+		require.NoError(t, err)
+		assert.Equal(t, 1*time.Second, getRequeueAfter(result, err))
+	}
+}
+
+// TestForget ensures Forget removes the entry of an object and resets its backoff
+func TestForget(t *testing.T) {
+	store := NewStore(time.Second, time.Minute, 2)
+
+	obj1 := &dummyObject{
+		ObjectMeta: ctrl.ObjectMeta{
+			Name:      "test1",
+			Namespace: "test",
+		},
+	}
+	obj2 := &dummyObject{
+		ObjectMeta: ctrl.ObjectMeta{
+			Name:      "test2",
+			Namespace: "test",
+		},
+	}
+
+	assert.Equal(t, 0, store.Len())
+
+	// Back off obj1 a few times
+	entry := store.For(obj1)
+	for range 3 {
+		_, _ = entry.RequeueWithBackoff()
+	}
+	assert.Equal(t, 8*time.Second, store.Peek(obj1))
+	_ = store.For(obj2)
+	assert.Equal(t, 2, store.Len())
+
+	store.Forget(obj1)
+	assert.Equal(t, 1, store.Len())
+
+	// Forgetting an unknown object is a no-op
+	store.Forget(obj1)
+	assert.Equal(t, 1, store.Len())
+
+	// The next requeue starts from the minimum interval again
+	result, err := store.For(obj1).RequeueWithBackoff()
+	require.NoError(t, err)
+	assert.Equal(t, 1*time.Second, getRequeueAfter(result, err))
+	assert.Equal(t, 2, store.Len())
+}
@@ -0,0 +1,68 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	jplib "github.com/evanphx/json-patch/v5"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// StatusMergePatch computes a JSON merge patch that contains only the status fields which differ between old and updated.
+// The ignoreFields are dot-separated paths relative to the status, using the JSON field names (e.g. 'lastReconcileTime' or 'foo.bar').
+// These fields are considered volatile and are never part of the returned patch.
+// The returned bool is true if any non-ignored status field changed. If it is false, the returned patch is nil.
+// The patch is meant to be used with the status subresource, e.g. c.Status().Patch(ctx, updated, patch).
+func StatusMergePatch(old, updated client.Object, ignoreFields ...string) (client.Patch, bool, error) {
+	oldStatus, err := statusWithoutFields(old, ignoreFields)
+	if err != nil {
+		return nil, false, fmt.Errorf("error computing status of old object: %w", err)
+	}
+	newStatus, err := statusWithoutFields(updated, ignoreFields)
+	if err != nil {
+		return nil, false, fmt.Errorf("error computing status of updated object: %w", err)
+	}
+	oldRaw, err := json.Marshal(oldStatus)
+	if err != nil {
+		return nil, false, fmt.Errorf("error marshalling status of old object: %w", err)
+	}
+	newRaw, err := json.Marshal(newStatus)
+	if err != nil {
+		return nil, false, fmt.Errorf("error marshalling status of updated object: %w", err)
+	}
+	diff, err := jplib.CreateMergePatch(oldRaw, newRaw)
+	if err != nil {
+		return nil, false, fmt.Errorf("error creating status merge patch: %w", err)
+	}
+	if string(diff) == "{}" {
+		return nil, false, nil
+	}
+	rawPatch, err := json.Marshal(map[string]json.RawMessage{"status": diff})
+	if err != nil {
+		return nil, false, fmt.Errorf("error marshalling status merge patch: %w", err)
+	}
+	return client.RawPatch(types.MergePatchType, rawPatch), true, nil
+}
+
+// statusWithoutFields returns a copy of the object's status with the given fields removed.
+// Returns an empty map if the object does not have a status.
+func statusWithoutFields(obj client.Object, ignoreFields []string) (map[string]any, error) {
+	data, err := toUnstructuredMap(obj)
+	if err != nil {
+		return nil, err
+	}
+	status, found, err := unstructured.NestedMap(data, "status")
+	if err != nil {
+		return nil, err
+	}
+	if !found || status == nil {
+		return map[string]any{}, nil
+	}
+	for _, field := range ignoreFields {
+		unstructured.RemoveNestedField(status, strings.Split(field, ".")...)
+	}
+	return status, nil
+}
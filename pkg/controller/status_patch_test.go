@@ -0,0 +1,73 @@
+package controller_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	ctrlutils "github.com/openmcp-project/controller-utils/pkg/controller"
+)
+
+var _ = Describe("StatusMergePatch", func() {
+
+	var oldObj *CustomObject
+
+	BeforeEach(func() {
+		oldObj = &CustomObject{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "bar",
+			},
+			Status: CustomObjectStatus{
+				CommonStatus: CommonStatus{
+					ObservedGeneration: 1,
+					LastReconcileTime:  metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second)),
+					Reason:             "Foo",
+				},
+				Phase: PhaseSucceeded,
+			},
+		}
+	})
+
+	It("should not return a patch if only volatile fields changed", func() {
+		newObj := oldObj.DeepCopy()
+		newObj.Status.LastReconcileTime = metav1.NewTime(time.Now().Truncate(time.Second))
+		patch, changed, err := ctrlutils.StatusMergePatch(oldObj, newObj, "lastReconcileTime")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeFalse())
+		Expect(patch).To(BeNil())
+
+		By("without ignoring the volatile field")
+		patch, changed, err = ctrlutils.StatusMergePatch(oldObj, newObj)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(patch).ToNot(BeNil())
+	})
+
+	It("should return a patch containing only the changed non-volatile fields", func() {
+		newObj := oldObj.DeepCopy()
+		newObj.Status.LastReconcileTime = metav1.NewTime(time.Now().Truncate(time.Second))
+		newObj.Status.Phase = PhaseFailed
+		newObj.Status.Reason = ""
+		newObj.Status.Message = "something went wrong"
+		patch, changed, err := ctrlutils.StatusMergePatch(oldObj, newObj, "lastReconcileTime")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(patch.Type()).To(Equal(types.MergePatchType))
+		data, err := patch.Data(newObj)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(data).To(MatchJSON(`{"status":{"phase":"Failed","reason":null,"message":"something went wrong"}}`))
+	})
+
+	It("should not return a patch if nothing changed", func() {
+		patch, changed, err := ctrlutils.StatusMergePatch(oldObj, oldObj.DeepCopy())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeFalse())
+		Expect(patch).To(BeNil())
+	})
+
+})
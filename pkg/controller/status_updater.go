@@ -4,12 +4,14 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
 
 	"github.com/openmcp-project/controller-utils/pkg/conditions"
 	"github.com/openmcp-project/controller-utils/pkg/controller/smartrequeue"
 	"github.com/openmcp-project/controller-utils/pkg/errors"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -92,6 +94,49 @@ func (b *StatusUpdaterBuilder[Obj]) WithConditionUpdater(removeUntouchedConditio
 	return b
 }
 
+// WithMergeExistingConditions controls whether conditions which exist on the object, but are not part of the ReconcileResult's Conditions, are preserved.
+// If set to true, these conditions are kept, even if the condition updater has been configured to remove untouched conditions via WithConditionUpdater(true).
+// This allows reconcilers to only pass in the conditions they actually want to modify.
+// Conditions listed in the ReconcileResult's ConditionsToRemove are removed nevertheless.
+// Defaults to false.
+func (b *StatusUpdaterBuilder[Obj]) WithMergeExistingConditions(merge bool) *StatusUpdaterBuilder[Obj] {
+	b.internal.mergeExistingConditions = merge
+	return b
+}
+
+// WithGenerationCondition makes the status updater maintain a condition of the given type, which reflects whether the object's current generation has been processed successfully.
+// If the ReconcileResult does not contain an error, the condition is set to True.
+// Otherwise, it is set to False, using the error's reason (or ReconcileError, if the error has no reason) and message.
+// The condition is set after the conditions from the ReconcileResult, so it overwrites a condition of the same type from there.
+// Pass in an empty string to disable this behavior again.
+// Note that this has no effect if the conditions field has been disabled.
+func (b *StatusUpdaterBuilder[Obj]) WithGenerationCondition(conType string) *StatusUpdaterBuilder[Obj] {
+	b.internal.generationCondition = conType
+	return b
+}
+
+// WithConditionTypeSanitizer sets the function that is used to sanitize condition types.
+// If set, it is applied to the types of all conditions (and the types in ConditionsToRemove) from the ReconcileResult before the conditions are updated,
+// as well as by the function returned from the status updater's GenerateCreateConditionFunc method.
+// By default, no sanitizer is set, so condition types are used as they are when updating the conditions,
+// and the function returned by GenerateCreateConditionFunc uses conditions.ReplaceIllegalCharsInConditionType, like the package-level GenerateCreateConditionFunc.
+// conditions.ReplaceIllegalCharsInConditionType can be passed in to sanitize all condition types, but note that it also replaces the '/' of domain-prefixed types.
+// Setting this to nil restores the default behavior.
+func (b *StatusUpdaterBuilder[Obj]) WithConditionTypeSanitizer(sanitizer func(string) string) *StatusUpdaterBuilder[Obj] {
+	b.internal.conditionTypeSanitizer = sanitizer
+	return b
+}
+
+// WithMessageMode sets how the message field of the status is computed.
+// In MESSAGE_MODE_REPLACE (the default), the message is replaced with the ReconcileResult's Message, or the error message if the former is empty.
+// In MESSAGE_MODE_APPEND, the ReconcileResult's Message and the error message (if any) are appended to the existing message, separated by MessageSeparator.
+// Note that in append mode, the message grows with every status update, unless it is reset by other means.
+// Other fields, such as the observed generation, are not affected by this.
+func (b *StatusUpdaterBuilder[Obj]) WithMessageMode(mode MessageMode) *StatusUpdaterBuilder[Obj] {
+	b.internal.messageMode = mode
+	return b
+}
+
 // WithConditionEvents sets the event recorder and the verbosity that is used for recording events for changed conditions.
 // If the event recorder is nil, no events are recorded.
 // Note that this has no effect if condition updates are enabled, see WithConditionUpdater().
@@ -150,11 +195,42 @@ func (b *StatusUpdaterBuilder[Obj]) WithSmartRequeue(store *smartrequeue.Store,
 	return b
 }
 
+// WithRequeueUntilConditionTrue integrates the smart requeue logic into the status updater, based on the status of the condition with the given type.
+// As long as the condition is not true (or doesn't exist) after the conditions have been updated, the object is requeued with an increasing backoff, as specified in the store.
+// As soon as the condition is true, the object is not requeued anymore and its entry is removed from the store, which resets the backoff.
+// This takes precedence over the action set in the ReconcileResult and any SmartRequeueConditionals passed into WithSmartRequeue.
+// Note that this sets the smart requeue store, calling WithSmartRequeue afterwards overwrites it.
+// If the conditions field has been disabled, the condition is never considered to be true.
+func (b *StatusUpdaterBuilder[Obj]) WithRequeueUntilConditionTrue(conType string, store *smartrequeue.Store) *StatusUpdaterBuilder[Obj] {
+	b.internal.smartRequeueStore = store
+	b.internal.requeueUntilConditionTrue = conType
+	return b
+}
+
+// WithResultValidation enables or disables validating the ReconcileResult before the status is updated.
+// This is meant as a debug mode: if the ReconcileResult's Validate method returns an error, the status is not updated
+// and the validation error is returned together with the reconcile error, using the reason "InvalidReconcileResult".
+// Validation is disabled by default.
+func (b *StatusUpdaterBuilder[Obj]) WithResultValidation(enabled bool) *StatusUpdaterBuilder[Obj] {
+	b.internal.validateResult = enabled
+	return b
+}
+
 // Build returns the status updater.
 func (b *StatusUpdaterBuilder[Obj]) Build() *statusUpdater[Obj] {
 	return b.internal
 }
 
+type MessageMode string
+
+const (
+	MESSAGE_MODE_REPLACE MessageMode = "Replace"
+	MESSAGE_MODE_APPEND  MessageMode = "Append"
+)
+
+// MessageSeparator is used to separate the single messages in MESSAGE_MODE_APPEND.
+const MessageSeparator = "; "
+
 type StatusField string
 
 const (
@@ -186,10 +262,16 @@ type statusUpdater[Obj client.Object] struct {
 	phaseUpdateFunc           func(obj Obj, rr ReconcileResult[Obj]) (string, error)
 	customUpdateFunc          func(obj Obj, rr ReconcileResult[Obj]) error
 	removeUntouchedConditions bool
+	mergeExistingConditions   bool
+	conditionTypeSanitizer    func(string) string
+	generationCondition       string
+	messageMode               MessageMode
 	eventRecorder             events.EventRecorder
 	eventVerbosity            conditions.EventVerbosity
 	smartRequeueStore         *smartrequeue.Store
 	smartRequeueConditionals  []SmartRequeueConditional[Obj]
+	requeueUntilConditionTrue string
+	validateResult            bool
 }
 
 func newStatusUpdater[Obj client.Object]() *statusUpdater[Obj] {
@@ -204,6 +286,7 @@ func newStatusUpdater[Obj client.Object]() *statusUpdater[Obj] {
 			STATUS_FIELD_PHASE:               string(STATUS_FIELD_PHASE),
 		},
 		phaseUpdateFunc: defaultPhaseUpdateFunc[Obj],
+		messageMode:     MESSAGE_MODE_REPLACE,
 	}
 }
 
@@ -216,15 +299,31 @@ func defaultPhaseUpdateFunc[Obj client.Object](obj Obj, _ ReconcileResult[Obj])
 // UpdateStatus updates the status of the object in the given ReconcileResult, using the previously set field names and functions.
 // The object is expected to be a pointer to a struct with the status field.
 // If the 'Object' field in the ReconcileResult is nil, the status update becomes a no-op.
+// It is a wrapper around UpdateStatusWithChange which discards the information whether the status changed.
+func (s *statusUpdater[Obj]) UpdateStatus(ctx context.Context, c client.Client, rr ReconcileResult[Obj]) (ctrl.Result, error) {
+	res, _, err := s.UpdateStatusWithChange(ctx, c, rr)
+	return res, err
+}
+
+// UpdateStatusWithChange works like UpdateStatus, but additionally returns whether the status actually changed.
+// The returned bool is true if any of the status fields (e.g. phase, conditions, reason, message) differs from the old object's status.
+// The LastReconcileTime field is not taken into account, because it is updated with every call.
+// If the status update is a no-op (e.g. because the 'Object' field in the ReconcileResult is nil), false is returned.
 //
 //nolint:gocyclo
-func (s *statusUpdater[Obj]) UpdateStatus(ctx context.Context, c client.Client, rr ReconcileResult[Obj]) (ctrl.Result, error) {
+func (s *statusUpdater[Obj]) UpdateStatusWithChange(ctx context.Context, c client.Client, rr ReconcileResult[Obj]) (ctrl.Result, bool, error) {
 	errs := errors.NewReasonableErrorList(rr.ReconcileError)
+	if s.validateResult {
+		if err := rr.Validate(); err != nil {
+			errs.Append(errors.WithReason(fmt.Errorf("invalid reconcile result: %w", err), "InvalidReconcileResult"))
+			return rr.Result, false, errs.Aggregate()
+		}
+	}
 	if IsNil(rr.Object) {
-		return rr.Result, errs.Aggregate()
+		return rr.Result, false, errs.Aggregate()
 	}
 	if s.fieldNames[STATUS_FIELD] == "" {
-		return rr.Result, errs.Aggregate()
+		return rr.Result, false, errs.Aggregate()
 	}
 	if IsNil(rr.OldObject) || IsSameObject(rr.OldObject, rr.Object) {
 		// create old object based on given one
@@ -233,9 +332,10 @@ func (s *statusUpdater[Obj]) UpdateStatus(ctx context.Context, c client.Client,
 	status := GetField(rr.Object, s.fieldNames[STATUS_FIELD], true)
 	if IsNil(status) {
 		errs.Append(errors.WithReason(fmt.Errorf("unable to get pointer to status field '%s' of object %T", s.fieldNames[STATUS_FIELD], rr.Object), "InternalError"))
-		return rr.Result, errs.Aggregate()
+		return rr.Result, false, errs.Aggregate()
 	}
 
+	changed := false
 	now := metav1.Now()
 	if s.fieldNames[STATUS_FIELD_LAST_RECONCILE_TIME] != "" {
 		SetField(status, s.fieldNames[STATUS_FIELD_LAST_RECONCILE_TIME], now)
@@ -245,8 +345,18 @@ func (s *statusUpdater[Obj]) UpdateStatus(ctx context.Context, c client.Client,
 	}
 	if s.fieldNames[STATUS_FIELD_MESSAGE] != "" {
 		message := rr.Message
-		if message == "" && rr.ReconcileError != nil {
-			message = rr.ReconcileError.Error()
+		switch s.messageMode {
+		case MESSAGE_MODE_APPEND:
+			parts := []string{GetField(status, s.fieldNames[STATUS_FIELD_MESSAGE], false).(string), rr.Message}
+			if rr.ReconcileError != nil {
+				parts = append(parts, rr.ReconcileError.Error())
+			}
+			parts = slices.DeleteFunc(parts, func(p string) bool { return p == "" })
+			message = strings.Join(parts, MessageSeparator)
+		default:
+			if message == "" && rr.ReconcileError != nil {
+				message = rr.ReconcileError.Error()
+			}
 		}
 		SetField(status, s.fieldNames[STATUS_FIELD_MESSAGE], message)
 	}
@@ -259,7 +369,7 @@ func (s *statusUpdater[Obj]) UpdateStatus(ctx context.Context, c client.Client,
 	}
 	if s.fieldNames[STATUS_FIELD_CONDITIONS] != "" {
 		oldCons := GetField(status, s.fieldNames[STATUS_FIELD_CONDITIONS], false).([]metav1.Condition)
-		cu := conditions.ConditionUpdater(oldCons, s.removeUntouchedConditions)
+		cu := conditions.ConditionUpdater(oldCons, s.removeUntouchedConditions && !s.mergeExistingConditions)
 		if s.eventRecorder != nil {
 			cu.WithEventRecorder(s.eventRecorder, s.eventVerbosity)
 		}
@@ -269,14 +379,26 @@ func (s *statusUpdater[Obj]) UpdateStatus(ctx context.Context, c client.Client,
 			if gen == 0 {
 				gen = rr.Object.GetGeneration()
 			}
-			cu.UpdateCondition(con.Type, con.Status, gen, con.Reason, con.Message)
+			cu.UpdateCondition(s.sanitizeConditionType(con.Type), con.Status, gen, con.Reason, con.Message)
+		}
+		if s.generationCondition != "" {
+			if rr.ReconcileError == nil {
+				cu.UpdateCondition(s.sanitizeConditionType(s.generationCondition), metav1.ConditionTrue, rr.Object.GetGeneration(), "GenerationObserved", fmt.Sprintf("Generation %d has been reconciled successfully.", rr.Object.GetGeneration()))
+			} else {
+				reason := rr.ReconcileError.Reason()
+				if reason == "" {
+					reason = "ReconcileError"
+				}
+				cu.UpdateCondition(s.sanitizeConditionType(s.generationCondition), metav1.ConditionFalse, rr.Object.GetGeneration(), reason, rr.ReconcileError.Error())
+			}
 		}
 		if len(rr.ConditionsToRemove) > 0 {
 			for _, conType := range rr.ConditionsToRemove {
-				cu.RemoveCondition(conType)
+				cu.RemoveCondition(s.sanitizeConditionType(conType))
 			}
 		}
-		newCons, _ := cu.Record(rr.Object).Conditions()
+		newCons, consChanged := cu.Record(rr.Object).Conditions()
+		changed = consChanged
 		SetField(status, s.fieldNames[STATUS_FIELD_CONDITIONS], newCons)
 	}
 	if s.fieldNames[STATUS_FIELD_PHASE] != "" {
@@ -293,6 +415,8 @@ func (s *statusUpdater[Obj]) UpdateStatus(ctx context.Context, c client.Client,
 		}
 	}
 
+	changed = changed || s.statusChanged(rr.OldObject, rr.Object)
+
 	// update status in cluster
 	if err := c.Status().Patch(ctx, rr.Object, client.MergeFrom(rr.OldObject)); err != nil {
 		errs.Append(fmt.Errorf("error patching status: %w", err))
@@ -308,6 +432,13 @@ func (s *statusUpdater[Obj]) UpdateStatus(ctx context.Context, c client.Client,
 					rr.SmartRequeue = srcFunc(rr)
 				}
 			}
+			if s.requeueUntilConditionTrue != "" {
+				if s.isConditionTrue(rr.Object, s.requeueUntilConditionTrue) {
+					rr.SmartRequeue = SR_NO_REQUEUE
+				} else {
+					rr.SmartRequeue = SR_BACKOFF
+				}
+			}
 			switch rr.SmartRequeue {
 			case SR_BACKOFF:
 				srRes, _ = s.smartRequeueStore.For(rr.Object).IsStable()
@@ -322,7 +453,28 @@ func (s *statusUpdater[Obj]) UpdateStatus(ctx context.Context, c client.Client,
 		}
 	}
 
-	return rr.Result, errs.Aggregate()
+	return rr.Result, changed, errs.Aggregate()
+}
+
+// statusChanged returns true if the status of newObj differs from the status of oldObj.
+// The LastReconcileTime field is ignored.
+func (s *statusUpdater[Obj]) statusChanged(oldObj, newObj Obj) bool {
+	newObj = newObj.DeepCopyObject().(Obj)
+	if s.fieldNames[STATUS_FIELD_LAST_RECONCILE_TIME] != "" {
+		oldStatus := GetField(oldObj, s.fieldNames[STATUS_FIELD], false)
+		SetField(GetField(newObj, s.fieldNames[STATUS_FIELD], true), s.fieldNames[STATUS_FIELD_LAST_RECONCILE_TIME], GetField(oldStatus, s.fieldNames[STATUS_FIELD_LAST_RECONCILE_TIME], false))
+	}
+	return !equality.Semantic.DeepEqual(GetField(oldObj, s.fieldNames[STATUS_FIELD], false), GetField(newObj, s.fieldNames[STATUS_FIELD], false))
+}
+
+// isConditionTrue returns true if the object's conditions contain a condition with the given type and status 'True'.
+func (s *statusUpdater[Obj]) isConditionTrue(obj Obj, conType string) bool {
+	if s.fieldNames[STATUS_FIELD_CONDITIONS] == "" {
+		return false
+	}
+	status := GetField(obj, s.fieldNames[STATUS_FIELD], false)
+	cons, ok := GetField(status, s.fieldNames[STATUS_FIELD_CONDITIONS], false).([]metav1.Condition)
+	return ok && conditions.IsConditionTrue(cons, conType)
 }
 
 // GetField returns the value of the field with the given name from the given object.
@@ -440,16 +592,126 @@ type ReconcileResult[Obj client.Object] struct {
 	SmartRequeue SmartRequeueAction
 }
 
+// Validate checks the ReconcileResult for inconsistencies which point to a misconfiguration, e.g. conditions being set while the object is nil.
+// Returns nil if no problems were found, otherwise an error listing all problems.
+// Note that some fields are defaulted by the status updater, call DefaultMissing before Validate to not report problems which would be fixed by defaulting.
+func (rr ReconcileResult[Obj]) Validate() error {
+	errs := errors.NewReasonableErrorList()
+	if IsNil(rr.Object) {
+		ignored := []string{}
+		if !IsNil(rr.OldObject) {
+			ignored = append(ignored, "OldObject")
+		}
+		if rr.Reason != "" {
+			ignored = append(ignored, "Reason")
+		}
+		if rr.Message != "" {
+			ignored = append(ignored, "Message")
+		}
+		if len(rr.Conditions) > 0 {
+			ignored = append(ignored, "Conditions")
+		}
+		if len(rr.ConditionsToRemove) > 0 {
+			ignored = append(ignored, "ConditionsToRemove")
+		}
+		if rr.SmartRequeue != "" {
+			ignored = append(ignored, "SmartRequeue")
+		}
+		if len(ignored) > 0 {
+			errs.Append(fmt.Errorf("object is nil, but the following fields are set and would be ignored: %s", strings.Join(ignored, ", ")))
+		}
+	}
+	switch rr.SmartRequeue {
+	case "", SR_BACKOFF, SR_RESET, SR_NO_REQUEUE:
+	default:
+		errs.Append(fmt.Errorf("unknown smart requeue action '%s'", rr.SmartRequeue))
+	}
+	if rr.Result.RequeueAfter < 0 {
+		errs.Append(fmt.Errorf("negative requeueAfter duration '%s'", rr.Result.RequeueAfter))
+	}
+	conTypes := map[string]bool{}
+	for i, con := range rr.Conditions {
+		if con.Type == "" {
+			errs.Append(fmt.Errorf("condition at index %d has an empty type", i))
+			continue
+		}
+		if conTypes[con.Type] {
+			errs.Append(fmt.Errorf("condition type '%s' is set multiple times", con.Type))
+		}
+		conTypes[con.Type] = true
+		switch con.Status {
+		case metav1.ConditionTrue, metav1.ConditionFalse, metav1.ConditionUnknown:
+		default:
+			errs.Append(fmt.Errorf("condition '%s' has invalid status '%s'", con.Type, con.Status))
+		}
+	}
+	for _, conType := range rr.ConditionsToRemove {
+		if conTypes[conType] {
+			errs.Append(fmt.Errorf("condition type '%s' is both set and marked for removal", conType))
+		}
+	}
+	return errs.Aggregate()
+}
+
+// DefaultMissing fills empty fields of the ReconcileResult with sensible defaults.
+// This modifies the receiver object.
+// - If Reason is empty, it is taken from the ReconcileError, if any.
+// - Conditions without status get the status 'Unknown'.
+// - Conditions without reason get the ReconcileResult's reason.
+// - Conditions without observedGeneration get the object's generation, if the object is not nil.
+func (rr *ReconcileResult[Obj]) DefaultMissing() {
+	if rr.Reason == "" && rr.ReconcileError != nil {
+		rr.Reason = rr.ReconcileError.Reason()
+	}
+	for i := range rr.Conditions {
+		con := &rr.Conditions[i]
+		if con.Status == "" {
+			con.Status = metav1.ConditionUnknown
+		}
+		if con.Reason == "" {
+			con.Reason = rr.Reason
+		}
+		if con.ObservedGeneration == 0 && !IsNil(rr.Object) {
+			con.ObservedGeneration = rr.Object.GetGeneration()
+		}
+	}
+}
+
 // GenerateCreateConditionFunc returns a function that can be used to add a condition to the given ReconcileResult.
 // If the ReconcileResult's Object is not nil, the condition's ObservedGeneration is set to the object's generation.
+// Illegal characters in the condition's type and reason are replaced with underscores.
+// Use the status updater's GenerateCreateConditionFunc method to sanitize the type with the function configured via WithConditionTypeSanitizer instead.
 func GenerateCreateConditionFunc[Obj client.Object](rr *ReconcileResult[Obj]) func(conType string, status metav1.ConditionStatus, reason, message string) {
+	return generateCreateConditionFunc(rr, conditions.ReplaceIllegalCharsInConditionType)
+}
+
+// GenerateCreateConditionFunc works like the package-level GenerateCreateConditionFunc function,
+// but uses the condition type sanitizer configured for this status updater.
+func (s *statusUpdater[Obj]) GenerateCreateConditionFunc(rr *ReconcileResult[Obj]) func(conType string, status metav1.ConditionStatus, reason, message string) {
+	sanitizer := s.conditionTypeSanitizer
+	if sanitizer == nil {
+		sanitizer = conditions.ReplaceIllegalCharsInConditionType
+	}
+	return generateCreateConditionFunc(rr, sanitizer)
+}
+
+// sanitizeConditionType applies the configured condition type sanitizer to the given condition type.
+// Returns the type unchanged if no sanitizer is configured.
+func (s *statusUpdater[Obj]) sanitizeConditionType(conType string) string {
+	if s.conditionTypeSanitizer == nil {
+		return conType
+	}
+	return s.conditionTypeSanitizer(conType)
+}
+
+func generateCreateConditionFunc[Obj client.Object](rr *ReconcileResult[Obj], sanitizeType func(string) string) func(conType string, status metav1.ConditionStatus, reason, message string) {
 	var gen int64 = 0
 	if any(rr.Object) != nil {
 		gen = rr.Object.GetGeneration()
 	}
 	return func(conType string, status metav1.ConditionStatus, reason, message string) {
 		rr.Conditions = append(rr.Conditions, metav1.Condition{
-			Type:               conditions.ReplaceIllegalCharsInConditionType(conType),
+			Type:               sanitizeType(conType),
 			Status:             status,
 			ObservedGeneration: gen,
 			Reason:             conditions.ReplaceIllegalCharsInConditionReason(reason),
@@ -457,3 +719,21 @@ func GenerateCreateConditionFunc[Obj client.Object](rr *ReconcileResult[Obj]) fu
 		})
 	}
 }
+
+// ConditionsToResult creates a ReconcileResult for the given object with the given conditions.
+// This is useful if the conditions have been computed beforehand, e.g. via the conditions package's updater.
+// The conditions are deep-copied, all of their fields are preserved.
+// The remaining fields of the ReconcileResult can be set on the returned value.
+func ConditionsToResult[Obj client.Object](obj Obj, cons []metav1.Condition) ReconcileResult[Obj] {
+	var resCons []metav1.Condition
+	if cons != nil {
+		resCons = make([]metav1.Condition, len(cons))
+		for i := range cons {
+			cons[i].DeepCopyInto(&resCons[i])
+		}
+	}
+	return ReconcileResult[Obj]{
+		Object:     obj,
+		Conditions: resCons,
+	}
+}
@@ -1,6 +1,7 @@
 package controller_test
 
 import (
+	"context"
 	"fmt"
 	"slices"
 	"time"
@@ -275,6 +276,344 @@ var _ = Describe("Status Updater", func() {
 			Expect(res.RequeueAfter).To(Equal(1 * time.Second))
 		})
 
+		It("should requeue with backoff until the specified condition is true", func() {
+			env := testutils.NewEnvironmentBuilder().WithFakeClient(coScheme).WithInitObjectPath("testdata", "test-02").WithDynamicObjectsWithStatus(&CustomObject{}).Build()
+			obj := &CustomObject{}
+			Expect(env.Client().Get(env.Ctx, controller.ObjectKey("status", "default"), obj)).To(Succeed())
+			store := smartrequeue.NewStore(1*time.Second, 10*time.Second, 2.0)
+			su := preconfiguredStatusUpdaterBuilder().WithRequeueUntilConditionTrue("Ready", store).Build()
+			rr := controller.ReconcileResult[*CustomObject]{
+				Object:       obj,
+				SmartRequeue: controller.SR_NO_REQUEUE,
+			}
+			createCon := controller.GenerateCreateConditionFunc(&rr)
+			createCon("Ready", metav1.ConditionFalse, "NotReady", "")
+
+			By("condition is false, requeue interval grows")
+			for _, expected := range []time.Duration{1 * time.Second, 2 * time.Second, 4 * time.Second} {
+				res, err := su.UpdateStatus(env.Ctx, env.Client(), rr)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(res.RequeueAfter).To(Equal(expected))
+			}
+
+			By("condition is true, requeue stops")
+			rr.Conditions = nil
+			createCon("Ready", metav1.ConditionTrue, "Ready", "")
+			res, err := su.UpdateStatus(env.Ctx, env.Client(), rr)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.RequeueAfter).To(BeZero())
+			Expect(obj.Status.Conditions).To(ContainElement(MatchCondition(TestCondition().WithType("Ready").WithStatus(metav1.ConditionTrue))))
+
+			By("condition is false again, backoff has been reset")
+			rr.Conditions = nil
+			createCon("Ready", metav1.ConditionFalse, "NotReady", "")
+			res, err = su.UpdateStatus(env.Ctx, env.Client(), rr)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.RequeueAfter).To(Equal(1 * time.Second))
+		})
+
+	})
+
+	Context("UpdateStatusWithChange", func() {
+
+		It("should report whether the status actually changed", func() {
+			env := testutils.NewEnvironmentBuilder().WithFakeClient(coScheme).WithInitObjectPath("testdata", "test-02").WithDynamicObjectsWithStatus(&CustomObject{}).Build()
+			obj := &CustomObject{}
+			Expect(env.Client().Get(env.Ctx, controller.ObjectKey("status", "default"), obj)).To(Succeed())
+			phase := PhaseSucceeded
+			su := preconfiguredStatusUpdaterBuilder().WithPhaseUpdateFunc(func(obj *CustomObject, rr controller.ReconcileResult[*CustomObject]) (string, error) {
+				return phase, nil
+			}).Build()
+			rr := controller.ReconcileResult[*CustomObject]{
+				Object:     obj,
+				Conditions: dummyConditions(),
+				Reason:     "TestReason",
+				Message:    "TestMessage",
+			}
+			_, changed, err := su.UpdateStatusWithChange(env.Ctx, env.Client(), rr)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changed).To(BeTrue())
+
+			By("updating again with the same values")
+			Expect(env.Client().Get(env.Ctx, client.ObjectKeyFromObject(obj), obj)).To(Succeed())
+			// move the last reconcile time into the past to make sure it changes
+			oldReconcileTime := metav1.NewTime(obj.Status.LastReconcileTime.Add(-time.Hour))
+			obj.Status.LastReconcileTime = oldReconcileTime
+			_, changed, err = su.UpdateStatusWithChange(env.Ctx, env.Client(), rr)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changed).To(BeFalse())
+			Expect(obj.Status.LastReconcileTime).ToNot(Equal(oldReconcileTime))
+
+			By("changing the phase")
+			phase = PhaseFailed
+			_, changed, err = su.UpdateStatusWithChange(env.Ctx, env.Client(), rr)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changed).To(BeTrue())
+			Expect(obj.Status.Phase).To(Equal(PhaseFailed))
+		})
+
+		It("should report no change if the object is nil", func() {
+			su := preconfiguredStatusUpdaterBuilder().Build()
+			_, changed, err := su.UpdateStatusWithChange(context.Background(), nil, controller.ReconcileResult[*CustomObject]{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changed).To(BeFalse())
+		})
+
+	})
+
+	Context("WithMergeExistingConditions", func() {
+
+		It("should preserve existing conditions which are not part of the ReconcileResult", func() {
+			env := testutils.NewEnvironmentBuilder().WithFakeClient(coScheme).WithInitObjectPath("testdata", "test-02").WithDynamicObjectsWithStatus(&CustomObject{}).Build()
+			obj := &CustomObject{}
+			Expect(env.Client().Get(env.Ctx, controller.ObjectKey("status", "default"), obj)).To(Succeed())
+			obj.Status.Conditions = dummyConditions()
+			Expect(env.Client().Status().Update(env.Ctx, obj)).To(Succeed())
+
+			rr := controller.ReconcileResult[*CustomObject]{
+				Object: obj,
+				Conditions: []metav1.Condition{
+					{
+						Type:    "TestConditionFalse",
+						Status:  metav1.ConditionTrue,
+						Reason:  "TestReasonNowTrue",
+						Message: "TestMessageNowTrue",
+					},
+				},
+			}
+			su := preconfiguredStatusUpdaterBuilder().WithMergeExistingConditions(true).Build()
+			_, err := su.UpdateStatus(env.Ctx, env.Client(), rr)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(env.Client().Get(env.Ctx, client.ObjectKeyFromObject(obj), obj)).To(Succeed())
+			Expect(obj.Status.Conditions).To(ConsistOf(
+				MatchCondition(TestConditionFromCondition(dummyConditions()[0])),
+				MatchCondition(TestCondition().WithType("TestConditionFalse").WithStatus(metav1.ConditionTrue).WithReason("TestReasonNowTrue").WithMessage("TestMessageNowTrue")),
+			))
+
+			By("without merging, untouched conditions are removed")
+			rr.Object = obj
+			su = preconfiguredStatusUpdaterBuilder().Build()
+			_, err = su.UpdateStatus(env.Ctx, env.Client(), rr)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(env.Client().Get(env.Ctx, client.ObjectKeyFromObject(obj), obj)).To(Succeed())
+			Expect(obj.Status.Conditions).To(ConsistOf(
+				MatchCondition(TestCondition().WithType("TestConditionFalse").WithStatus(metav1.ConditionTrue)),
+			))
+		})
+
+		It("should still remove conditions listed in ConditionsToRemove", func() {
+			env := testutils.NewEnvironmentBuilder().WithFakeClient(coScheme).WithInitObjectPath("testdata", "test-02").WithDynamicObjectsWithStatus(&CustomObject{}).Build()
+			obj := &CustomObject{}
+			Expect(env.Client().Get(env.Ctx, controller.ObjectKey("status", "default"), obj)).To(Succeed())
+			obj.Status.Conditions = dummyConditions()
+			rr := controller.ReconcileResult[*CustomObject]{
+				Object:             obj,
+				ConditionsToRemove: []string{"TestConditionTrue"},
+			}
+			su := preconfiguredStatusUpdaterBuilder().WithMergeExistingConditions(true).Build()
+			_, err := su.UpdateStatus(env.Ctx, env.Client(), rr)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(obj.Status.Conditions).To(ConsistOf(
+				MatchCondition(TestConditionFromCondition(dummyConditions()[1])),
+			))
+		})
+
+	})
+
+	Context("WithGenerationCondition", func() {
+
+		It("should set the generation condition to true if there is no reconcile error", func() {
+			env := testutils.NewEnvironmentBuilder().WithFakeClient(coScheme).WithInitObjectPath("testdata", "test-02").WithDynamicObjectsWithStatus(&CustomObject{}).Build()
+			obj := &CustomObject{}
+			Expect(env.Client().Get(env.Ctx, controller.ObjectKey("status", "default"), obj)).To(Succeed())
+			rr := controller.ReconcileResult[*CustomObject]{
+				Object:     obj,
+				Conditions: dummyConditions(),
+			}
+			su := preconfiguredStatusUpdaterBuilder().WithGenerationCondition("Synced").Build()
+			_, err := su.UpdateStatus(env.Ctx, env.Client(), rr)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(env.Client().Get(env.Ctx, client.ObjectKeyFromObject(obj), obj)).To(Succeed())
+			Expect(obj.Status.Conditions).To(ConsistOf(
+				MatchCondition(TestConditionFromCondition(dummyConditions()[0])),
+				MatchCondition(TestConditionFromCondition(dummyConditions()[1])),
+				MatchCondition(TestCondition().WithType("Synced").WithStatus(metav1.ConditionTrue).WithObservedGeneration(obj.Generation).WithReason("GenerationObserved")),
+			))
+		})
+
+		It("should set the generation condition to false with the error's reason if there is a reconcile error", func() {
+			env := testutils.NewEnvironmentBuilder().WithFakeClient(coScheme).WithInitObjectPath("testdata", "test-02").WithDynamicObjectsWithStatus(&CustomObject{}).Build()
+			obj := &CustomObject{}
+			Expect(env.Client().Get(env.Ctx, controller.ObjectKey("status", "default"), obj)).To(Succeed())
+			rr := controller.ReconcileResult[*CustomObject]{
+				Object:         obj,
+				ReconcileError: errors.WithReason(fmt.Errorf("test error"), "TestErrorReason"),
+			}
+			su := preconfiguredStatusUpdaterBuilder().WithGenerationCondition("Synced").Build()
+			_, err := su.UpdateStatus(env.Ctx, env.Client(), rr)
+			Expect(err).To(HaveOccurred())
+			Expect(env.Client().Get(env.Ctx, client.ObjectKeyFromObject(obj), obj)).To(Succeed())
+			Expect(obj.Status.Conditions).To(ConsistOf(
+				MatchCondition(TestCondition().WithType("Synced").WithStatus(metav1.ConditionFalse).WithReason("TestErrorReason").WithMessage("test error")),
+			))
+
+			By("using a default reason if the error has none")
+			rr.Object = obj
+			rr.ReconcileError = errors.WithReason(fmt.Errorf("another error"), "")
+			_, err = su.UpdateStatus(env.Ctx, env.Client(), rr)
+			Expect(err).To(HaveOccurred())
+			Expect(env.Client().Get(env.Ctx, client.ObjectKeyFromObject(obj), obj)).To(Succeed())
+			Expect(obj.Status.Conditions).To(ConsistOf(
+				MatchCondition(TestCondition().WithType("Synced").WithStatus(metav1.ConditionFalse).WithReason("ReconcileError").WithMessage("another error")),
+			))
+		})
+
+	})
+
+	Context("Validate", func() {
+
+		It("should not return an error for a valid result", func() {
+			rr := controller.ReconcileResult[*CustomObject]{
+				Object:             &CustomObject{},
+				Conditions:         dummyConditions(),
+				ConditionsToRemove: []string{"Obsolete"},
+				SmartRequeue:       controller.SR_BACKOFF,
+			}
+			Expect(rr.Validate()).To(Succeed())
+			Expect(controller.ReconcileResult[*CustomObject]{}.Validate()).To(Succeed())
+		})
+
+		It("should detect fields which are ignored because the object is nil", func() {
+			rr := controller.ReconcileResult[*CustomObject]{
+				Message:    "foo",
+				Conditions: dummyConditions(),
+			}
+			err := rr.Validate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Message, Conditions"))
+		})
+
+		It("should detect invalid conditions and smart requeue actions", func() {
+			rr := controller.ReconcileResult[*CustomObject]{
+				Object: &CustomObject{},
+				Conditions: []metav1.Condition{
+					{Type: "", Status: metav1.ConditionTrue},
+					{Type: "Dup", Status: metav1.ConditionTrue},
+					{Type: "Dup", Status: "Maybe"},
+				},
+				ConditionsToRemove: []string{"Dup"},
+				SmartRequeue:       "Sometimes",
+			}
+			err := rr.Validate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(And(
+				ContainSubstring("condition at index 0 has an empty type"),
+				ContainSubstring("condition type 'Dup' is set multiple times"),
+				ContainSubstring("condition 'Dup' has invalid status 'Maybe'"),
+				ContainSubstring("condition type 'Dup' is both set and marked for removal"),
+				ContainSubstring("unknown smart requeue action 'Sometimes'"),
+			))
+		})
+
+		It("should fill missing fields with defaults", func() {
+			obj := &CustomObject{}
+			obj.SetGeneration(3)
+			rr := controller.ReconcileResult[*CustomObject]{
+				Object:         obj,
+				ReconcileError: errors.WithReason(fmt.Errorf("test error"), "TestError"),
+				Conditions: []metav1.Condition{
+					{Type: "Empty"},
+					{Type: "Full", Status: metav1.ConditionTrue, Reason: "Ready", ObservedGeneration: 1},
+				},
+			}
+			Expect(rr.Validate()).ToNot(Succeed())
+			rr.DefaultMissing()
+			Expect(rr.Validate()).To(Succeed())
+			Expect(rr.Reason).To(Equal("TestError"))
+			Expect(rr.Conditions).To(ConsistOf(
+				MatchCondition(TestCondition().WithType("Empty").WithStatus(metav1.ConditionUnknown).WithReason("TestError").WithObservedGeneration(3)),
+				MatchCondition(TestCondition().WithType("Full").WithStatus(metav1.ConditionTrue).WithReason("Ready").WithObservedGeneration(1)),
+			))
+		})
+
+		It("should not update the status of an invalid result if validation is enabled", func() {
+			env := testutils.NewEnvironmentBuilder().WithFakeClient(coScheme).WithInitObjectPath("testdata", "test-02").WithDynamicObjectsWithStatus(&CustomObject{}).Build()
+			obj := &CustomObject{}
+			Expect(env.Client().Get(env.Ctx, controller.ObjectKey("nostatus", "default"), obj)).To(Succeed())
+			rr := controller.ReconcileResult[*CustomObject]{
+				Object:       obj,
+				SmartRequeue: "Sometimes",
+			}
+			su := preconfiguredStatusUpdaterBuilder().WithResultValidation(true).Build()
+			_, err := su.UpdateStatus(env.Ctx, env.Client(), rr)
+			Expect(err).To(HaveOccurred())
+			rerr, ok := err.(errors.ReasonableError)
+			Expect(ok).To(BeTrue())
+			Expect(rerr.Reason()).To(Equal("InvalidReconcileResult"))
+			Expect(env.Client().Get(env.Ctx, client.ObjectKeyFromObject(obj), obj)).To(Succeed())
+			Expect(obj.Status.LastReconcileTime.IsZero()).To(BeTrue())
+		})
+
+	})
+
+	Context("Objects created after Build", func() {
+
+		It("should be able to update the status after enabling the status subresource", func() {
+			env := testutils.NewEnvironmentBuilder().WithFakeClient(coScheme).Build()
+			obj := &CustomObject{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "late",
+					Namespace:  "default",
+					Generation: 3,
+				},
+			}
+			Expect(env.Client().Create(env.Ctx, obj)).To(Succeed())
+			rr := controller.ReconcileResult[*CustomObject]{
+				Object: obj,
+			}
+			su := preconfiguredStatusUpdaterBuilder().Build()
+			_, err := su.UpdateStatus(env.Ctx, env.Client(), rr)
+			Expect(err).To(HaveOccurred(), "status subresource should not be enabled yet")
+
+			Expect(env.EnableStatusSubresource(&CustomObject{})).To(Succeed())
+			Expect(env.Client().Get(env.Ctx, client.ObjectKeyFromObject(obj), obj)).To(Succeed(), "existing objects should be preserved")
+			rr.Object = obj
+			_, err = su.UpdateStatus(env.Ctx, env.Client(), rr)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(env.Client().Get(env.Ctx, client.ObjectKeyFromObject(obj), obj)).To(Succeed())
+			Expect(obj.Status.ObservedGeneration).To(Equal(int64(3)))
+		})
+
+	})
+
+	Context("ConditionsToResult", func() {
+
+		It("should convert the conditions into a ReconcileResult", func() {
+			obj := &CustomObject{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test",
+					Namespace:  "default",
+					Generation: 15,
+				},
+			}
+			cons := dummyConditions()
+			cons[0].LastTransitionTime = metav1.NewTime(time.Now().Truncate(time.Second))
+			rr := controller.ConditionsToResult(obj, cons)
+			Expect(rr.Object).To(BeIdenticalTo(obj))
+			Expect(rr.Conditions).To(Equal(cons))
+			Expect(rr.ReconcileError).To(BeNil())
+
+			// the conditions must have been copied
+			rr.Conditions[0].Message = "changed"
+			Expect(cons[0].Message).ToNot(Equal("changed"))
+		})
+
+		It("should work with nil conditions", func() {
+			rr := controller.ConditionsToResult[*CustomObject](nil, nil)
+			Expect(rr.Object).To(BeNil())
+			Expect(rr.Conditions).To(BeNil())
+		})
+
 	})
 
 	Context("GenerateCreateConditionFunc", func() {
@@ -318,6 +657,131 @@ var _ = Describe("Status Updater", func() {
 
 	})
 
+	Context("WithMessageMode", func() {
+
+		It("should append the new message and the error message to the existing one in append mode", func() {
+			env := testutils.NewEnvironmentBuilder().WithFakeClient(coScheme).WithInitObjectPath("testdata", "test-02").WithDynamicObjectsWithStatus(&CustomObject{}).Build()
+			obj := &CustomObject{}
+			Expect(env.Client().Get(env.Ctx, controller.ObjectKey("status", "default"), obj)).To(Succeed())
+			obj.Status.Message = "initial message"
+			rr := controller.ReconcileResult[*CustomObject]{
+				Object:         obj,
+				Message:        "new message",
+				ReconcileError: errors.WithReason(fmt.Errorf("test error"), "TestError"),
+			}
+			su := preconfiguredStatusUpdaterBuilder().WithMessageMode(controller.MESSAGE_MODE_APPEND).Build()
+			_, err := su.UpdateStatus(env.Ctx, env.Client(), rr)
+			Expect(err).To(HaveOccurred())
+			Expect(obj.Status.Message).To(Equal("initial message; new message; test error"))
+			Expect(obj.Status.ObservedGeneration).To(Equal(obj.GetGeneration()))
+			Expect(obj.Status.Reason).To(Equal("TestError"))
+
+			By("appending without an error")
+			rr.ReconcileError = nil
+			rr.Message = "another message"
+			_, err = su.UpdateStatus(env.Ctx, env.Client(), rr)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(obj.Status.Message).To(Equal("initial message; new message; test error; another message"))
+		})
+
+		It("should replace the message by default", func() {
+			env := testutils.NewEnvironmentBuilder().WithFakeClient(coScheme).WithInitObjectPath("testdata", "test-02").WithDynamicObjectsWithStatus(&CustomObject{}).Build()
+			obj := &CustomObject{}
+			Expect(env.Client().Get(env.Ctx, controller.ObjectKey("status", "default"), obj)).To(Succeed())
+			obj.Status.Message = "initial message"
+			rr := controller.ReconcileResult[*CustomObject]{
+				Object:  obj,
+				Message: "new message",
+			}
+			_, err := preconfiguredStatusUpdaterBuilder().Build().UpdateStatus(env.Ctx, env.Client(), rr)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(obj.Status.Message).To(Equal("new message"))
+		})
+
+	})
+
+	Context("WithConditionTypeSanitizer", func() {
+
+		const conType = "CondType :,;-_.Test02@"
+
+		It("should use the configured sanitizer in the create condition function", func() {
+			rr := controller.ReconcileResult[*CustomObject]{Object: &CustomObject{}}
+			su := preconfiguredStatusUpdaterBuilder().WithConditionTypeSanitizer(func(s string) string { return s }).Build()
+			su.GenerateCreateConditionFunc(&rr)(conType, metav1.ConditionTrue, "TestReason", "")
+			Expect(rr.Conditions).To(ConsistOf(MatchCondition(TestCondition().WithType(conType))))
+
+			By("using the default sanitizer")
+			rr = controller.ReconcileResult[*CustomObject]{Object: &CustomObject{}}
+			su = preconfiguredStatusUpdaterBuilder().Build()
+			su.GenerateCreateConditionFunc(&rr)(conType, metav1.ConditionTrue, "TestReason", "")
+			Expect(rr.Conditions).To(ConsistOf(MatchCondition(TestCondition().WithType("CondType____-_.Test02_"))))
+			rr = controller.ReconcileResult[*CustomObject]{Object: &CustomObject{}}
+			controller.GenerateCreateConditionFunc(&rr)(conType, metav1.ConditionTrue, "TestReason", "")
+			Expect(rr.Conditions).To(ConsistOf(MatchCondition(TestCondition().WithType("CondType____-_.Test02_"))))
+		})
+
+		It("should use the configured sanitizer when updating the conditions", func() {
+			env := testutils.NewEnvironmentBuilder().WithFakeClient(coScheme).WithInitObjectPath("testdata", "test-02").WithDynamicObjectsWithStatus(&CustomObject{}).Build()
+			obj := &CustomObject{}
+			Expect(env.Client().Get(env.Ctx, controller.ObjectKey("status", "default"), obj)).To(Succeed())
+			rr := controller.ReconcileResult[*CustomObject]{
+				Object: obj,
+				Conditions: []metav1.Condition{
+					{
+						Type:   conType,
+						Status: metav1.ConditionTrue,
+						Reason: "TestReason",
+					},
+				},
+			}
+			su := preconfiguredStatusUpdaterBuilder().WithConditionTypeSanitizer(func(s string) string { return s }).Build()
+			_, err := su.UpdateStatus(env.Ctx, env.Client(), rr)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(obj.Status.Conditions).To(ConsistOf(MatchCondition(TestCondition().WithType(conType))))
+
+			By("using a sanitizer which replaces illegal characters")
+			su = preconfiguredStatusUpdaterBuilder().WithConditionTypeSanitizer(conditions.ReplaceIllegalCharsInConditionType).Build()
+			_, err = su.UpdateStatus(env.Ctx, env.Client(), rr)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(obj.Status.Conditions).To(ConsistOf(MatchCondition(TestCondition().WithType("CondType____-_.Test02_"))))
+		})
+
+		It("should not modify condition types by default", func() {
+			const prefixedType = "example.com/Ready"
+			env := testutils.NewEnvironmentBuilder().WithFakeClient(coScheme).WithInitObjectPath("testdata", "test-02").WithDynamicObjectsWithStatus(&CustomObject{}).Build()
+			obj := &CustomObject{}
+			Expect(env.Client().Get(env.Ctx, controller.ObjectKey("status", "default"), obj)).To(Succeed())
+			imported := conditions.ImportConditions([]metav1.Condition{{Type: "Healthy", Status: metav1.ConditionTrue, Reason: "TestReason"}}, "child")
+			rr := controller.ReconcileResult[*CustomObject]{
+				Object: obj,
+				Conditions: append([]metav1.Condition{
+					{
+						Type:   prefixedType,
+						Status: metav1.ConditionTrue,
+						Reason: "TestReason",
+					},
+				}, imported...),
+			}
+			su := preconfiguredStatusUpdaterBuilder().Build()
+			_, err := su.UpdateStatus(env.Ctx, env.Client(), rr)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(obj.Status.Conditions).To(ConsistOf(
+				MatchCondition(TestCondition().WithType(prefixedType)),
+				MatchCondition(TestCondition().WithType("child/Healthy")),
+			))
+
+			By("removing a prefixed condition")
+			// keep untouched conditions, so that only the explicit removal takes effect
+			su = preconfiguredStatusUpdaterBuilder().WithConditionUpdater(false).Build()
+			rr.Conditions = nil
+			rr.ConditionsToRemove = []string{prefixedType}
+			_, err = su.UpdateStatus(env.Ctx, env.Client(), rr)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(obj.Status.Conditions).To(ConsistOf(MatchCondition(TestCondition().WithType("child/Healthy"))))
+		})
+
+	})
+
 })
 
 func preconfiguredStatusUpdaterBuilder() *controller.StatusUpdaterBuilder[*CustomObject] {
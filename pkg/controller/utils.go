@@ -3,9 +3,11 @@ package controller
 import (
 	"crypto/sha256"
 	"encoding/base32"
+	"fmt"
 	"reflect"
 	"strings"
 
+	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -54,3 +56,40 @@ func ObjectKey(name string, maybeNamespace ...string) client.ObjectKey {
 		Name:      name,
 	}
 }
+
+// ObjectKeyFromString is the inverse of client.ObjectKey's String method.
+// It parses strings in the format 'namespace/name' and treats strings without a slash as names of cluster-scoped objects.
+// A leading slash ('/name') is allowed as well and also results in an empty namespace.
+// Returns an error if the string is empty, contains more than one slash, or the name is empty.
+func ObjectKeyFromString(s string) (client.ObjectKey, error) {
+	if s == "" {
+		return client.ObjectKey{}, fmt.Errorf("unable to parse object key from empty string")
+	}
+	fields := strings.Split(s, "/")
+	var key client.ObjectKey
+	switch len(fields) {
+	case 1:
+		key.Name = fields[0]
+	case 2:
+		key.Namespace = fields[0]
+		key.Name = fields[1]
+	default:
+		return client.ObjectKey{}, fmt.Errorf("unable to parse object key from '%s': expected format 'namespace/name' or 'name', but found %d slashes", s, len(fields)-1)
+	}
+	if key.Name == "" {
+		return client.ObjectKey{}, fmt.Errorf("unable to parse object key from '%s': name must not be empty", s)
+	}
+	return key, nil
+}
+
+// NoRequeue returns an empty reconcile result, which means that the object is not requeued explicitly.
+// This is meant to make the intent of not requeuing more visible in the code.
+func NoRequeue() ctrl.Result {
+	return ctrl.Result{}
+}
+
+// Requeue returns a reconcile result which causes the object to be requeued (with the default rate limiting).
+// Use ctrl.Result{RequeueAfter: ...} directly if the object should be requeued after a specific duration.
+func Requeue() ctrl.Result {
+	return ctrl.Result{Requeue: true} //nolint:staticcheck
+}
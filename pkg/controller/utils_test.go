@@ -9,6 +9,8 @@ import (
 	"github.com/openmcp-project/controller-utils/pkg/pairs"
 
 	"k8s.io/apimachinery/pkg/util/validation"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 var _ = Describe("Predicates", func() {
@@ -58,4 +60,57 @@ var _ = Describe("Predicates", func() {
 
 	})
 
+	Context("ObjectKeyFromString", func() {
+
+		It("should parse namespaced keys", func() {
+			key, err := ObjectKeyFromString("ns/name")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(key).To(Equal(ObjectKey("name", "ns")))
+		})
+
+		It("should parse cluster-scoped keys", func() {
+			key, err := ObjectKeyFromString("name")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(key).To(Equal(ObjectKey("name")))
+
+			key, err = ObjectKeyFromString("/name")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(key).To(Equal(ObjectKey("name")))
+		})
+
+		It("should be the inverse of ObjectKey.String", func() {
+			for _, key := range []client.ObjectKey{ObjectKey("name", "ns"), ObjectKey("name")} {
+				parsed, err := ObjectKeyFromString(key.String())
+				Expect(err).ToNot(HaveOccurred())
+				Expect(parsed).To(Equal(key))
+			}
+		})
+
+		It("should return an error for malformed input", func() {
+			_, err := ObjectKeyFromString("")
+			Expect(err).To(MatchError(ContainSubstring("empty string")))
+			_, err = ObjectKeyFromString("a/b/c")
+			Expect(err).To(MatchError(ContainSubstring("found 2 slashes")))
+			_, err = ObjectKeyFromString("ns/")
+			Expect(err).To(MatchError(ContainSubstring("name must not be empty")))
+		})
+
+	})
+
+	Context("NoRequeue and Requeue", func() {
+
+		It("should return an empty result for NoRequeue", func() {
+			res := NoRequeue()
+			Expect(res).To(Equal(ctrl.Result{}))
+			Expect(res.IsZero()).To(BeTrue())
+		})
+
+		It("should return a result that requeues for Requeue", func() {
+			res := Requeue()
+			Expect(res.Requeue).To(BeTrue()) //nolint:staticcheck
+			Expect(res.RequeueAfter).To(BeZero())
+		})
+
+	})
+
 })
@@ -6,6 +6,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	ctrlutils "github.com/openmcp-project/controller-utils/pkg/errors"
 )
@@ -51,3 +54,173 @@ func TestIgnoreInvalidUserInput(t *testing.T) {
 		})
 	}
 }
+
+func TestWithObject(t *testing.T) {
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "foo",
+		},
+	}
+	cm := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "MyConfigMap",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "bar",
+			Namespace: "foo",
+		},
+	}
+
+	tests := []struct {
+		name       string
+		err        error
+		obj        client.Object
+		wantMsg    string
+		wantReason string
+		wantKey    client.ObjectKey
+	}{
+		{
+			name:    "namespaced object with kind from type meta",
+			err:     errors.New("something failed"),
+			obj:     cm,
+			wantMsg: "MyConfigMap/foo/bar: something failed",
+			wantKey: client.ObjectKey{Name: "bar", Namespace: "foo"},
+		},
+		{
+			name:       "cluster-scoped object with kind from go type and reason",
+			err:        ctrlutils.WithReason(errors.New("something failed"), "MyReason"),
+			obj:        ns,
+			wantMsg:    "Namespace/foo: something failed",
+			wantReason: "MyReason",
+			wantKey:    client.ObjectKey{Name: "foo"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ctrlutils.WithObject(tt.err, tt.obj)
+			assert.EqualError(t, err, tt.wantMsg)
+			assert.Equal(t, tt.wantReason, err.Reason())
+			assert.ErrorIs(t, err, tt.err)
+
+			key, ok := ctrlutils.ObjectOf(err)
+			assert.True(t, ok)
+			assert.Equal(t, tt.wantKey, key)
+
+			// the object can also be extracted from wrapping errors
+			key, ok = ctrlutils.ObjectOf(fmt.Errorf("outer: %w", err))
+			assert.True(t, ok)
+			assert.Equal(t, tt.wantKey, key)
+		})
+	}
+
+	t.Run("nil error", func(t *testing.T) {
+		assert.Nil(t, ctrlutils.WithObject(nil, ns))
+	})
+
+	t.Run("error without object", func(t *testing.T) {
+		_, ok := ctrlutils.ObjectOf(errors.New("plain error"))
+		assert.False(t, ok)
+		_, ok = ctrlutils.ObjectOf(nil)
+		assert.False(t, ok)
+	})
+}
+
+func TestAggregate(t *testing.T) {
+	errA := ctrlutils.WithReason(errors.New("error a"), "ReasonA")
+	errB := ctrlutils.WithReason(errors.New("error b"), "ReasonB")
+	errNoReason := ctrlutils.WithReason(errors.New("error c"), "")
+	plain := errors.New("plain error")
+
+	tests := []struct {
+		name        string
+		errs        []error
+		separator   string
+		wantReason  string
+		wantReasons []string
+	}{
+		{
+			name:        "first reason is used by default",
+			errs:        []error{errA, errB},
+			wantReason:  "ReasonA",
+			wantReasons: []string{"ReasonA", "ReasonB"},
+		},
+		{
+			name:        "errors without reason are skipped",
+			errs:        []error{plain, errNoReason, errB, errA},
+			wantReason:  "ReasonB",
+			wantReasons: []string{"ReasonB", "ReasonA"},
+		},
+		{
+			name:        "reasons are joined if a separator is configured",
+			errs:        []error{errA, plain, errB},
+			separator:   ",",
+			wantReason:  "ReasonA,ReasonB",
+			wantReasons: []string{"ReasonA", "ReasonB"},
+		},
+		{
+			name:        "no reasons",
+			errs:        []error{plain, errNoReason},
+			separator:   ",",
+			wantReason:  "",
+			wantReasons: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			el := ctrlutils.NewReasonableErrorList(tt.errs...).JoinReasons(tt.separator)
+			assert.Equal(t, tt.wantReason, el.Reason())
+			agg := el.Aggregate()
+			assert.Equal(t, tt.wantReason, agg.Reason())
+			aggErr, ok := agg.(*ctrlutils.AggregatedError)
+			if assert.True(t, ok) {
+				assert.Equal(t, tt.wantReasons, aggErr.Reasons())
+			}
+			for _, err := range tt.errs {
+				assert.ErrorIs(t, agg, err)
+				assert.Contains(t, agg.Error(), err.Error())
+			}
+		})
+	}
+
+	t.Run("single error is returned as is", func(t *testing.T) {
+		assert.Equal(t, errA, ctrlutils.Join(errA))
+		assert.Nil(t, ctrlutils.Join())
+	})
+}
+
+type customError struct {
+	msg string
+}
+
+func (e *customError) Error() string {
+	return e.msg
+}
+
+func TestWithReasonf(t *testing.T) {
+	base := &customError{msg: "base error"}
+
+	err := ctrlutils.WithReasonf("TestReason", "error for %s: %w", "foo", base)
+	assert.Equal(t, "TestReason", err.Reason())
+	assert.Equal(t, "error for foo: base error", err.Error())
+	assert.ErrorIs(t, err, base)
+
+	err = ctrlutils.WithReasonf("TestReason", "error without %s", "wrapping")
+	assert.Equal(t, "error without wrapping", err.Error())
+	assert.NotErrorIs(t, err, base)
+}
+
+func TestWrapWithReason(t *testing.T) {
+	base := &customError{msg: "base error"}
+
+	err := ctrlutils.WrapWithReason(base, "TestReason", "unable to process %q", "foo")
+	assert.Equal(t, "TestReason", err.Reason())
+	assert.Equal(t, `unable to process "foo": base error`, err.Error())
+	assert.ErrorIs(t, err, base)
+	var ce *customError
+	if assert.ErrorAs(t, err, &ce) {
+		assert.Same(t, base, ce)
+	}
+
+	assert.Nil(t, ctrlutils.WrapWithReason(nil, "TestReason", "unable to process %q", "foo"))
+}
@@ -0,0 +1,88 @@
+package errors
+
+import (
+	"errors"
+	"reflect"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ ReasonableError = &ObjectError{}
+
+// ObjectError wraps an error and annotates it with a reference to the k8s object it concerns.
+// The error message is prefixed with 'kind/namespace/name' (or 'kind/name' for cluster-scoped objects).
+// Use WithObject(err, obj) to wrap an error into an *ObjectError and ObjectOf(err) to extract the object reference again.
+type ObjectError struct {
+	error
+	reason string
+	kind   string
+	key    client.ObjectKey
+}
+
+// Error returns the error message of the wrapped error, prefixed with the object reference.
+func (e *ObjectError) Error() string {
+	return e.ObjectReference() + ": " + e.error.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *ObjectError) Unwrap() error {
+	return e.error
+}
+
+// Reason returns the reason of the wrapped error, if it had one, or the empty string otherwise.
+func (e *ObjectError) Reason() string {
+	return e.reason
+}
+
+// Kind returns the kind of the object this error concerns.
+func (e *ObjectError) Kind() string {
+	return e.kind
+}
+
+// ObjectKey returns the name and namespace of the object this error concerns.
+func (e *ObjectError) ObjectKey() client.ObjectKey {
+	return e.key
+}
+
+// ObjectReference returns the reference to the object in the format 'kind/namespace/name' or 'kind/name', if the namespace is empty.
+func (e *ObjectError) ObjectReference() string {
+	if e.key.Namespace == "" {
+		return e.kind + "/" + e.key.Name
+	}
+	return e.kind + "/" + e.key.Namespace + "/" + e.key.Name
+}
+
+// WithObject wraps an error together with a reference to the given object into an ObjectError.
+// If the given error is a ReasonableError, its reason is preserved.
+// The kind is taken from the object's GroupVersionKind, falling back to the name of the object's Go type if it is not set.
+// If the given error is nil, nil is returned.
+func WithObject(err error, obj client.Object) ReasonableError {
+	if err == nil {
+		return nil
+	}
+	res := &ObjectError{
+		error: err,
+	}
+	if rerr, ok := err.(ReasonableError); ok {
+		res.reason = rerr.Reason()
+	}
+	if obj != nil && !reflect.ValueOf(obj).IsNil() {
+		res.kind = obj.GetObjectKind().GroupVersionKind().Kind
+		if res.kind == "" {
+			res.kind = reflect.TypeOf(obj).Elem().Name()
+		}
+		res.key = client.ObjectKeyFromObject(obj)
+	}
+	return res
+}
+
+// ObjectOf returns the key of the object that the given error concerns.
+// The second return value is false if neither the error nor any error it wraps is an ObjectError.
+// If multiple errors in the chain are ObjectErrors, the outermost one is used.
+func ObjectOf(err error) (client.ObjectKey, bool) {
+	var oerr *ObjectError
+	if !errors.As(err, &oerr) {
+		return client.ObjectKey{}, false
+	}
+	return oerr.key, true
+}
@@ -3,10 +3,12 @@ package errors
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 )
 
 var _ ReasonableError = &ErrorWithReason{}
+var _ ReasonableError = &AggregatedError{}
 
 // ReasonableError enhances an error with a reason.
 // The reason is meant to be a CamelCased, machine-readable, enum-like string.
@@ -29,6 +31,11 @@ func (e *ErrorWithReason) Reason() string {
 	return e.reason
 }
 
+// Unwrap returns the wrapped error.
+func (e *ErrorWithReason) Unwrap() error {
+	return e.error
+}
+
 // WithReason wraps an error together with a reason into ErrorWithReason.
 // The reason is meant to be a CamelCased, machine-readable, enum-like string.
 // If the given error is nil, nil is returned.
@@ -42,6 +49,22 @@ func WithReason(err error, reason string) ReasonableError {
 	}
 }
 
+// WithReasonf constructs a new error from the given format string and arguments, similarly to fmt.Errorf, and combines it with the given reason.
+// The format string may contain the %w verb to wrap other errors.
+func WithReasonf(reason string, format string, args ...any) ReasonableError {
+	return WithReason(fmt.Errorf(format, args...), reason)
+}
+
+// WrapWithReason wraps the given error with a message constructed from the format string and arguments, and combines it with the given reason.
+// The resulting message is '<formatted message>: <error message>'. The given error can still be retrieved via errors.Is and errors.As.
+// If the given error is nil, nil is returned.
+func WrapWithReason(err error, reason string, format string, args ...any) ReasonableError {
+	if err == nil {
+		return nil
+	}
+	return WithReason(fmt.Errorf("%s: %w", fmt.Sprintf(format, args...), err), reason)
+}
+
 // Errorf works similarly to fmt.Errorf, with the exception that it requires an ErrorWithReason as second argument and returns nil if that one is nil.
 // Otherwise, it calls fmt.Errorf to construct an error and wraps it in an ErrorWithReason, using the reason from the given error.
 // This is useful for expanding the error message without losing the reason.
@@ -61,8 +84,48 @@ func Join(errs ...error) ReasonableError {
 
 // ReasonableErrorList is a helper struct for situations in which multiple errors (with or without reasons) should be returned as a single one.
 type ReasonableErrorList struct {
-	Errs    []error
+	Errs []error
+	// Reasons contains the non-empty reasons of all contained errors, in the order they were added.
 	Reasons []string
+
+	reasonSeparator string
+}
+
+// AggregatedError is the error returned by ReasonableErrorList.Aggregate() if the list contains more than one error.
+// Its message contains the messages of all contained errors.
+type AggregatedError struct {
+	error
+	errs      []error
+	reasons   []string
+	separator string
+}
+
+// Reason returns the first non-empty reason of the contained errors.
+// If a reason separator has been configured on the list via JoinReasons, all reasons are joined with the separator instead.
+// Returns the empty string if none of the contained errors has a reason.
+func (e *AggregatedError) Reason() string {
+	return joinReasons(e.reasons, e.separator)
+}
+
+// Reasons returns the non-empty reasons of all contained errors, in the order the errors were added to the list.
+func (e *AggregatedError) Reasons() []string {
+	return slices.Clone(e.reasons)
+}
+
+// Unwrap returns the contained errors.
+// This allows errors.Is and errors.As to check the contained errors.
+func (e *AggregatedError) Unwrap() []error {
+	return e.errs
+}
+
+func joinReasons(reasons []string, separator string) string {
+	if len(reasons) == 0 {
+		return ""
+	}
+	if separator == "" {
+		return reasons[0]
+	}
+	return strings.Join(reasons, separator)
 }
 
 // NewReasonableErrorList creates a new *ErrorListWithReasons containing the provided errors.
@@ -74,25 +137,22 @@ func NewReasonableErrorList(errs ...error) *ReasonableErrorList {
 	return res.Append(errs...)
 }
 
-// Aggregate aggregates all errors in the list into a single ErrorWithReason.
+// Aggregate aggregates all errors in the list into a single ReasonableError.
 // Returns nil if the list is either nil or empty.
-// If the list contains a single error, that error is returned.
-// Otherwise, a new error is constructed by appending all contained errors' messages.
-// The reason in the returned error is the first reason that was added to the list,
-// or the empty string if none of the contained errors was an ErrorWithReason.
+// If the list contains a single error, that error is returned (wrapped into an ErrorWithReason with an empty reason, if it isn't a ReasonableError).
+// Otherwise, an *AggregatedError is returned, whose message contains all contained errors' messages.
+// Its reason is the first non-empty reason of the contained errors, or the empty string if none of them has a reason.
+// If a separator has been configured via JoinReasons, the reason is the concatenation of all non-empty reasons instead.
+// Use the returned error's Reasons method to get all reasons separately.
 func (el *ReasonableErrorList) Aggregate() ReasonableError {
 	if el == nil || len(el.Errs) == 0 {
 		return nil
 	}
-	reason := ""
-	if len(el.Reasons) > 0 {
-		reason = el.Reasons[0]
-	}
 	if len(el.Errs) == 1 {
 		if ewr, ok := el.Errs[0].(ReasonableError); ok {
 			return ewr
 		}
-		return WithReason(el.Errs[0], reason)
+		return WithReason(el.Errs[0], "")
 	}
 	sb := strings.Builder{}
 	sb.WriteString("multiple errors occurred:")
@@ -100,19 +160,33 @@ func (el *ReasonableErrorList) Aggregate() ReasonableError {
 		sb.WriteString("\n")
 		sb.WriteString(e.Error())
 	}
-	return WithReason(errors.New(sb.String()), reason)
+	return &AggregatedError{
+		error:     errors.New(sb.String()),
+		errs:      slices.Clone(el.Errs),
+		reasons:   slices.Clone(el.Reasons),
+		separator: el.reasonSeparator,
+	}
+}
+
+// JoinReasons configures the list to join all reasons with the given separator, instead of using only the first one.
+// This affects the list's Reason method as well as the error returned by Aggregate.
+// Passing in the empty string restores the default behavior.
+// Returns the receiver for chaining.
+func (el *ReasonableErrorList) JoinReasons(separator string) *ReasonableErrorList {
+	el.reasonSeparator = separator
+	return el
 }
 
 // Append appends all given errors to the ErrorListWithReasons.
 // This modifies the receiver object.
-// If a given error is of type ErrorWithReason, its reason is added to the list of reasons.
+// If a given error is a ReasonableError with a non-empty reason, its reason is added to the list of reasons.
 // nil pointers in the arguments are ignored.
 // Returns the receiver for chaining.
 func (el *ReasonableErrorList) Append(errs ...error) *ReasonableErrorList {
 	for _, e := range errs {
 		if e != nil {
 			el.Errs = append(el.Errs, e)
-			if ewr, ok := e.(ReasonableError); ok {
+			if ewr, ok := e.(ReasonableError); ok && ewr.Reason() != "" {
 				el.Reasons = append(el.Reasons, ewr.Reason())
 			}
 		}
@@ -120,12 +194,13 @@ func (el *ReasonableErrorList) Append(errs ...error) *ReasonableErrorList {
 	return el
 }
 
-// Reason returns the first reason from the list of reasons contained in this error list.
+// Reason returns the first reason from the list of reasons contained in this error list,
+// or all reasons joined with the separator, if one has been configured via JoinReasons.
 // If the list is nil or no reasons are contained, the empty string is returned.
 // This is equivalent to el.Aggregate().Reason(), except that it also works for an empty error list.
 func (el *ReasonableErrorList) Reason() string {
-	if el == nil || len(el.Reasons) == 0 {
+	if el == nil {
 		return ""
 	}
-	return el.Reasons[0]
+	return joinReasons(el.Reasons, el.reasonSeparator)
 }
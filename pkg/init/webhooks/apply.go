@@ -70,6 +70,15 @@ func applyValidatingWebhook(ctx context.Context, opts *installOptions, obj clien
 			}
 		}
 
+		if opts.failurePolicy != nil {
+			webhook.FailurePolicy = ptr.To(*opts.failurePolicy)
+		}
+		if opts.timeoutSeconds != nil {
+			webhook.TimeoutSeconds = ptr.To(*opts.timeoutSeconds)
+		}
+		webhook.NamespaceSelector = opts.namespaceSelector.DeepCopy()
+		webhook.ObjectSelector = opts.objectSelector.DeepCopy()
+
 		if mutate != nil {
 			if err := mutate(&webhook); err != nil {
 				return fmt.Errorf("error applying mutation to webhook: %w", err)
@@ -134,6 +143,15 @@ func applyMutatingWebhook(ctx context.Context, opts *installOptions, obj client.
 			webhook.ClientConfig.URL = ptr.To(*opts.customBaseUrl + webhookPath)
 		}
 
+		if opts.failurePolicy != nil {
+			webhook.FailurePolicy = ptr.To(*opts.failurePolicy)
+		}
+		if opts.timeoutSeconds != nil {
+			webhook.TimeoutSeconds = ptr.To(*opts.timeoutSeconds)
+		}
+		webhook.NamespaceSelector = opts.namespaceSelector.DeepCopy()
+		webhook.ObjectSelector = opts.objectSelector.DeepCopy()
+
 		if mutate != nil {
 			if err := mutate(&webhook); err != nil {
 				return fmt.Errorf("error applying mutation to webhook: %w", err)
@@ -21,7 +21,14 @@ type generatedCert struct {
 	expiresAt  time.Time
 }
 
+// defaultCertValidity is the validity of generated webhook certificates.
+const defaultCertValidity = 10 * 365 * 24 * time.Hour // 10 years
+
 func generateCert(webhookService types.NamespacedName, additionalDNSNames []string) (*generatedCert, error) {
+	return generateCertWithValidity(webhookService, additionalDNSNames, defaultCertValidity)
+}
+
+func generateCertWithValidity(webhookService types.NamespacedName, additionalDNSNames []string, validity time.Duration) (*generatedCert, error) {
 	serial, err := rand.Int(rand.Reader, new(big.Int).SetInt64(math.MaxInt64))
 	if err != nil {
 		return nil, err
@@ -29,7 +36,7 @@ func generateCert(webhookService types.NamespacedName, additionalDNSNames []stri
 
 	dnsNames := getServiceCertDNSNames(webhookService)
 	dnsNames = append(dnsNames, additionalDNSNames...)
-	expiresAt := time.Now().Add(10 * 365 * 24 * time.Hour).UTC() // 10 years
+	expiresAt := time.Now().Add(validity).UTC()
 	cert := x509.Certificate{
 		Subject: pkix.Name{
 			CommonName: dnsNames[0],
@@ -96,3 +103,16 @@ func encodeCertificate(certBytes []byte) ([]byte, error) {
 	})
 	return certPEM.Bytes(), err
 }
+
+// getCertificateExpiration parses the given PEM-encoded certificate and returns its NotAfter timestamp.
+func getCertificateExpiration(certPEM []byte) (time.Time, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return time.Time{}, fmt.Errorf("no PEM-encoded certificate found")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error parsing certificate: %w", err)
+	}
+	return cert.NotAfter, nil
+}
@@ -0,0 +1,29 @@
+package webhooks
+
+import (
+	"context"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CRDNeedsConversion returns true if the CRD with the given name has more than one served version.
+// Only then a conversion webhook is required, so this can be used to check whether installing one is necessary.
+func CRDNeedsConversion(ctx context.Context, c client.Client, crdName string) (bool, error) {
+	// make sure that the client knows about the CustomResourceDefinition type
+	utilruntime.Must(apiextensionsv1.AddToScheme(c.Scheme()))
+
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	if err := c.Get(ctx, client.ObjectKey{Name: crdName}, crd); err != nil {
+		return false, err
+	}
+
+	served := 0
+	for _, v := range crd.Spec.Versions {
+		if v.Served {
+			served++
+		}
+	}
+	return served > 1, nil
+}
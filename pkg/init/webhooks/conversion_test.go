@@ -0,0 +1,82 @@
+package webhooks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_CRDNeedsConversion(t *testing.T) {
+	newCRD := func(name string, versions ...apiextensionsv1.CustomResourceDefinitionVersion) *apiextensionsv1.CustomResourceDefinition {
+		return &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Versions: versions,
+			},
+		}
+	}
+
+	testCases := []struct {
+		desc          string
+		crdName       string
+		expected      bool
+		expectedError bool
+	}{
+		{
+			desc:     "should not need conversion for a single version",
+			crdName:  "single.example.com",
+			expected: false,
+		},
+		{
+			desc:     "should need conversion for multiple served versions",
+			crdName:  "multi.example.com",
+			expected: true,
+		},
+		{
+			desc:     "should not need conversion if only one version is served",
+			crdName:  "multi-unserved.example.com",
+			expected: false,
+		},
+		{
+			desc:          "should return an error if the CRD does not exist",
+			crdName:       "missing.example.com",
+			expectedError: true,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			ctx := context.Background()
+			scheme := runtime.NewScheme()
+			assert.NoError(t, apiextensionsv1.AddToScheme(scheme))
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				newCRD("single.example.com",
+					apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1", Served: true, Storage: true},
+				),
+				newCRD("multi.example.com",
+					apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1", Served: true, Storage: true},
+					apiextensionsv1.CustomResourceDefinitionVersion{Name: "v2", Served: true},
+				),
+				newCRD("multi-unserved.example.com",
+					apiextensionsv1.CustomResourceDefinitionVersion{Name: "v1", Served: true, Storage: true},
+					apiextensionsv1.CustomResourceDefinitionVersion{Name: "v2", Served: false},
+				),
+			).Build()
+
+			needsConversion, err := CRDNeedsConversion(ctx, c, tC.crdName)
+			if tC.expectedError {
+				assert.True(t, apierrors.IsNotFound(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tC.expected, needsConversion)
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"log"
+	"time"
 
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -40,10 +41,22 @@ func GenerateCertificate(ctx context.Context, c client.Client, options ...CertOp
 		return err
 	}
 
-	if _, ok := secret.Data[corev1.TLSCertKey]; ok {
+	if certData, ok := secret.Data[corev1.TLSCertKey]; ok {
 		// cert exists
-		log.Println("Webhook secret exists. Doing nothing.")
-		return nil
+		if opts.rotationThreshold <= 0 {
+			log.Println("Webhook secret exists. Doing nothing.")
+			return nil
+		}
+		expiresAt, err := getCertificateExpiration(certData)
+		if err == nil && time.Until(expiresAt) > opts.rotationThreshold {
+			log.Printf("Webhook secret exists and certificate expires at %s. Doing nothing.", expiresAt)
+			return nil
+		}
+		if err != nil {
+			log.Printf("Unable to determine expiration of existing webhook certificate, certificate will be regenerated: %v", err)
+		} else {
+			log.Printf("Existing webhook certificate expires at %s, which is within the rotation threshold of %s. Certificate will be regenerated.", expiresAt, opts.rotationThreshold)
+		}
 	}
 
 	log.Println("Generating webhook certificate...")
@@ -71,8 +84,10 @@ type APITypes struct {
 	// Obj is the API type object.
 	Obj client.Object
 	// Validator indicates whether the type has a validating webhook.
+	// A ValidatingWebhookConfiguration is only created for the type if this is true.
 	Validator bool
 	// Defaulter indicates whether the type has a mutating webhook.
+	// A MutatingWebhookConfiguration is only created for the type if this is true.
 	Defaulter bool
 	// Mutation allows to mutate the webhooks before they are created or updated. Use with caution, as it may break the webhooks or interfere with the library's management of the resources.
 	Mutation Mutation
@@ -4,13 +4,16 @@ import (
 	"context"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -24,6 +27,35 @@ func setEnv() {
 	os.Setenv("WEBHOOK_SECRET_NAMESPACE", "mynamespace")
 }
 
+// createCertSecret creates the webhook namespace and secret, containing a certificate which expires after the given duration.
+// The private key is replaced by a marker value, so that tests can detect whether the secret has been overwritten.
+func createCertSecret(ctx context.Context, c client.Client, validity time.Duration) error {
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "mynamespace",
+		},
+	}
+	if err := c.Create(ctx, ns); err != nil {
+		return err
+	}
+
+	cert, err := generateCertWithValidity(types.NamespacedName{Name: "myservice", Namespace: "mynamespace"}, nil, validity)
+	if err != nil {
+		return err
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "mysecret",
+			Namespace: "mynamespace",
+		},
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       cert.publicKey,
+			corev1.TLSPrivateKeyKey: []byte("seeded-key"),
+		},
+	}
+	return c.Create(ctx, secret)
+}
+
 func Test_GenerateCertificate(t *testing.T) {
 	testCases := []struct {
 		desc     string
@@ -140,6 +172,54 @@ func Test_GenerateCertificate(t *testing.T) {
 				return nil
 			},
 		},
+		{
+			desc: "should replace existing certificate which expires within rotation threshold",
+			setup: func(ctx context.Context, c client.Client) error {
+				setEnv()
+				return createCertSecret(ctx, c, time.Hour)
+			},
+			validate: func(ctx context.Context, c client.Client, t *testing.T, testErr error) error {
+				assert.NoError(t, testErr)
+
+				secret := &corev1.Secret{}
+				if err := c.Get(ctx, client.ObjectKey{Name: "mysecret", Namespace: "mynamespace"}, secret); err != nil {
+					return err
+				}
+
+				assert.NotEqual(t, []byte("seeded-key"), secret.Data[corev1.TLSPrivateKeyKey])
+				expiresAt, err := getCertificateExpiration(secret.Data[corev1.TLSCertKey])
+				assert.NoError(t, err)
+				assert.True(t, expiresAt.After(time.Now().Add(365*24*time.Hour)), "certificate should have been regenerated with default validity")
+				return nil
+			},
+			options: []CertOption{
+				WithRotationThreshold(24 * time.Hour),
+			},
+		},
+		{
+			desc: "should not replace existing certificate which does not expire within rotation threshold",
+			setup: func(ctx context.Context, c client.Client) error {
+				setEnv()
+				return createCertSecret(ctx, c, 30*24*time.Hour)
+			},
+			validate: func(ctx context.Context, c client.Client, t *testing.T, testErr error) error {
+				assert.NoError(t, testErr)
+
+				secret := &corev1.Secret{}
+				if err := c.Get(ctx, client.ObjectKey{Name: "mysecret", Namespace: "mynamespace"}, secret); err != nil {
+					return err
+				}
+
+				assert.Equal(t, []byte("seeded-key"), secret.Data[corev1.TLSPrivateKeyKey])
+				expiresAt, err := getCertificateExpiration(secret.Data[corev1.TLSCertKey])
+				assert.NoError(t, err)
+				assert.True(t, expiresAt.Before(time.Now().Add(31*24*time.Hour)), "certificate should not have been regenerated")
+				return nil
+			},
+			options: []CertOption{
+				WithRotationThreshold(24 * time.Hour),
+			},
+		},
 	}
 	for _, tC := range testCases {
 		t.Run(tC.desc, func(t *testing.T) {
@@ -165,6 +245,7 @@ func Test_Install(t *testing.T) {
 		setup    func(ctx context.Context, c client.Client) error
 		validate func(ctx context.Context, c client.Client, t *testing.T, testErr error) error
 		options  []InstallOption
+		apiTypes []APITypes
 	}{
 		{
 			desc: "should create webhook configurations for TestObj",
@@ -201,6 +282,8 @@ func Test_Install(t *testing.T) {
 					Path:      ptr.To(generateValidatePath(testObjGVK)),
 					Port:      ptr.To[int32](443),
 				})
+				assert.Equal(t, ptr.To(admissionregistrationv1.Fail), vwc.Webhooks[0].FailurePolicy)
+				assert.Nil(t, vwc.Webhooks[0].TimeoutSeconds)
 
 				mwc := &admissionregistrationv1.MutatingWebhookConfiguration{
 					ObjectMeta: metav1.ObjectMeta{
@@ -217,6 +300,8 @@ func Test_Install(t *testing.T) {
 					Path:      ptr.To(generateMutatePath(testObjGVK)),
 					Port:      ptr.To[int32](443),
 				})
+				assert.Equal(t, ptr.To(admissionregistrationv1.Fail), mwc.Webhooks[0].FailurePolicy)
+				assert.Nil(t, mwc.Webhooks[0].TimeoutSeconds)
 
 				return nil
 			},
@@ -226,6 +311,8 @@ func Test_Install(t *testing.T) {
 			options: []InstallOption{
 				WithoutCA,
 				WithCustomBaseURL("https://webhooks.example.com"),
+				WithFailurePolicy(admissionregistrationv1.Ignore),
+				WithTimeoutSeconds(5),
 			},
 			setup: func(ctx context.Context, c client.Client) error { return nil },
 			validate: func(ctx context.Context, c client.Client, t *testing.T, testErr error) error {
@@ -242,6 +329,8 @@ func Test_Install(t *testing.T) {
 				assert.Nil(t, vwc.Webhooks[0].ClientConfig.CABundle)
 				assert.Nil(t, vwc.Webhooks[0].ClientConfig.Service)
 				assert.Equal(t, *vwc.Webhooks[0].ClientConfig.URL, "https://webhooks.example.com"+generateValidatePath(testObjGVK))
+				assert.Equal(t, ptr.To(admissionregistrationv1.Ignore), vwc.Webhooks[0].FailurePolicy)
+				assert.Equal(t, ptr.To[int32](5), vwc.Webhooks[0].TimeoutSeconds)
 
 				mwc := &admissionregistrationv1.MutatingWebhookConfiguration{
 					ObjectMeta: metav1.ObjectMeta{
@@ -255,6 +344,95 @@ func Test_Install(t *testing.T) {
 				assert.Nil(t, mwc.Webhooks[0].ClientConfig.CABundle)
 				assert.Nil(t, mwc.Webhooks[0].ClientConfig.Service)
 				assert.Equal(t, *mwc.Webhooks[0].ClientConfig.URL, "https://webhooks.example.com"+generateMutatePath(testObjGVK))
+				assert.Equal(t, ptr.To(admissionregistrationv1.Ignore), mwc.Webhooks[0].FailurePolicy)
+				assert.Equal(t, ptr.To[int32](5), mwc.Webhooks[0].TimeoutSeconds)
+
+				return nil
+			},
+		},
+		{
+			desc: "should set selectors on webhook configurations for TestObj",
+			options: []InstallOption{
+				WithoutCA,
+				WithNamespaceSelector(&metav1.LabelSelector{MatchLabels: map[string]string{"webhooks.example.org/enabled": "true"}}),
+				WithObjectSelector(&metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "example.org/ignore", Operator: metav1.LabelSelectorOpDoesNotExist},
+					},
+				}),
+			},
+			setup: func(ctx context.Context, c client.Client) error { return nil },
+			validate: func(ctx context.Context, c client.Client, t *testing.T, testErr error) error {
+				assert.NoError(t, testErr)
+
+				expectedNamespaceSelector := &metav1.LabelSelector{MatchLabels: map[string]string{"webhooks.example.org/enabled": "true"}}
+				expectedObjectSelector := &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "example.org/ignore", Operator: metav1.LabelSelectorOpDoesNotExist},
+					},
+				}
+
+				vwc := &admissionregistrationv1.ValidatingWebhookConfiguration{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: generateValidateName(testObjGVK),
+					},
+				}
+				err := c.Get(ctx, client.ObjectKeyFromObject(vwc), vwc)
+				assert.NoError(t, err)
+				assert.Len(t, vwc.Webhooks, 1)
+				assert.Equal(t, expectedNamespaceSelector, vwc.Webhooks[0].NamespaceSelector)
+				assert.Equal(t, expectedObjectSelector, vwc.Webhooks[0].ObjectSelector)
+
+				mwc := &admissionregistrationv1.MutatingWebhookConfiguration{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: generateMutateName(testObjGVK),
+					},
+				}
+				err = c.Get(ctx, client.ObjectKeyFromObject(mwc), mwc)
+				assert.NoError(t, err)
+				assert.Len(t, mwc.Webhooks, 1)
+				assert.Equal(t, expectedNamespaceSelector, mwc.Webhooks[0].NamespaceSelector)
+				assert.Equal(t, expectedObjectSelector, mwc.Webhooks[0].ObjectSelector)
+
+				return nil
+			},
+		},
+		{
+			desc:     "should only create validating webhook configuration for validate-only TestObj",
+			options:  []InstallOption{WithoutCA},
+			apiTypes: []APITypes{{Obj: &TestObj{}, Validator: true}},
+			setup:    func(ctx context.Context, c client.Client) error { return nil },
+			validate: func(ctx context.Context, c client.Client, t *testing.T, testErr error) error {
+				assert.NoError(t, testErr)
+
+				vwc := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+				err := c.Get(ctx, client.ObjectKey{Name: generateValidateName(testObjGVK)}, vwc)
+				assert.NoError(t, err)
+				assert.Len(t, vwc.Webhooks, 1)
+
+				mwc := &admissionregistrationv1.MutatingWebhookConfiguration{}
+				err = c.Get(ctx, client.ObjectKey{Name: generateMutateName(testObjGVK)}, mwc)
+				assert.True(t, apierrors.IsNotFound(err), "expected NotFound error for mutating webhook configuration, got: %v", err)
+
+				return nil
+			},
+		},
+		{
+			desc:     "should only create mutating webhook configuration for defaulter-only TestObj",
+			options:  []InstallOption{WithoutCA},
+			apiTypes: []APITypes{{Obj: &TestObj{}, Defaulter: true}},
+			setup:    func(ctx context.Context, c client.Client) error { return nil },
+			validate: func(ctx context.Context, c client.Client, t *testing.T, testErr error) error {
+				assert.NoError(t, testErr)
+
+				mwc := &admissionregistrationv1.MutatingWebhookConfiguration{}
+				err := c.Get(ctx, client.ObjectKey{Name: generateMutateName(testObjGVK)}, mwc)
+				assert.NoError(t, err)
+				assert.Len(t, mwc.Webhooks, 1)
+
+				vwc := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+				err = c.Get(ctx, client.ObjectKey{Name: generateValidateName(testObjGVK)}, vwc)
+				assert.True(t, apierrors.IsNotFound(err), "expected NotFound error for validating webhook configuration, got: %v", err)
 
 				return nil
 			},
@@ -270,12 +448,15 @@ func Test_Install(t *testing.T) {
 				t.Fatal(err)
 			}
 
-			apiTypes := []APITypes{
-				{
-					Obj:       &TestObj{},
-					Validator: true,
-					Defaulter: true,
-				},
+			apiTypes := tC.apiTypes
+			if apiTypes == nil {
+				apiTypes = []APITypes{
+					{
+						Obj:       &TestObj{},
+						Validator: true,
+						Defaulter: true,
+					},
+				}
 			}
 			testErr := Install(ctx, c, c.Scheme(), apiTypes, tC.options...)
 
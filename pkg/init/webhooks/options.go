@@ -1,6 +1,10 @@
 package webhooks
 
 import (
+	"time"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -24,6 +28,10 @@ type installOptions struct {
 	webhookServicePort int32
 	managedLabels      map[string]string
 	managedService     *WithManagedWebhookService
+	failurePolicy      *admissionregistrationv1.FailurePolicyType
+	timeoutSeconds     *int32
+	namespaceSelector  *metav1.LabelSelector
+	objectSelector     *metav1.LabelSelector
 }
 
 type InstallOption interface {
@@ -38,6 +46,7 @@ type certOptions struct {
 	webhookService     types.NamespacedName
 	webhookSecret      types.NamespacedName
 	additionalDNSNames []string
+	rotationThreshold  time.Duration
 }
 
 type CertOption interface {
@@ -138,6 +147,19 @@ func (opt WithAdditionalDNSNames) ApplyToCertOptions(o *certOptions) {
 	o.additionalDNSNames = opt
 }
 
+//
+// Certificate Rotation
+//
+
+// WithRotationThreshold causes the certificate to be regenerated if the existing one expires within the given duration.
+// The existing certificate is also regenerated if it cannot be parsed.
+// Without this option, an existing certificate is never replaced.
+type WithRotationThreshold time.Duration
+
+func (opt WithRotationThreshold) ApplyToCertOptions(o *certOptions) {
+	o.rotationThreshold = time.Duration(opt)
+}
+
 //
 // Managed Webhook Service
 //
@@ -152,6 +174,64 @@ func (opt WithManagedWebhookService) ApplyToInstallOptions(o *installOptions) {
 	o.managedService = &opt
 }
 
+//
+// Failure Policy
+//
+
+// WithFailurePolicy sets the failurePolicy of the generated webhooks.
+// Defaults to 'Fail' if not specified.
+type WithFailurePolicy admissionregistrationv1.FailurePolicyType
+
+func (opt WithFailurePolicy) ApplyToInstallOptions(o *installOptions) {
+	o.failurePolicy = ptr.To(admissionregistrationv1.FailurePolicyType(opt))
+}
+
+//
+// Timeout
+//
+
+// WithTimeoutSeconds sets the timeoutSeconds of the generated webhooks.
+// If not specified, the field is left empty and the API server's default is used.
+type WithTimeoutSeconds int32
+
+func (opt WithTimeoutSeconds) ApplyToInstallOptions(o *installOptions) {
+	o.timeoutSeconds = ptr.To(int32(opt))
+}
+
+//
+// Namespace Selector
+//
+
+type withNamespaceSelector struct {
+	selector *metav1.LabelSelector
+}
+
+// WithNamespaceSelector sets the namespaceSelector of the generated webhooks.
+func WithNamespaceSelector(selector *metav1.LabelSelector) InstallOption {
+	return withNamespaceSelector{selector: selector}
+}
+
+func (opt withNamespaceSelector) ApplyToInstallOptions(o *installOptions) {
+	o.namespaceSelector = opt.selector
+}
+
+//
+// Object Selector
+//
+
+type withObjectSelector struct {
+	selector *metav1.LabelSelector
+}
+
+// WithObjectSelector sets the objectSelector of the generated webhooks.
+func WithObjectSelector(selector *metav1.LabelSelector) InstallOption {
+	return withObjectSelector{selector: selector}
+}
+
+func (opt withObjectSelector) ApplyToInstallOptions(o *installOptions) {
+	o.objectSelector = opt.selector
+}
+
 //
 // Managed Labels
 //
@@ -0,0 +1,21 @@
+package webhooks
+
+import (
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// MergeWarnings combines the given admission warnings into a single list.
+// Duplicate and empty warnings are removed and the result is sorted alphabetically.
+// Returns nil if there are no warnings.
+func MergeWarnings(warnings ...admission.Warnings) admission.Warnings {
+	res := sets.New[string]()
+	for _, ws := range warnings {
+		res.Insert(ws...)
+	}
+	res.Delete("")
+	if res.Len() == 0 {
+		return nil
+	}
+	return sets.List(res)
+}
@@ -0,0 +1,42 @@
+package webhooks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func Test_MergeWarnings(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		input    []admission.Warnings
+		expected admission.Warnings
+	}{
+		{
+			desc:     "should return nil for no input",
+			input:    nil,
+			expected: nil,
+		},
+		{
+			desc:     "should return nil if all warnings are empty",
+			input:    []admission.Warnings{nil, {}, {""}},
+			expected: nil,
+		},
+		{
+			desc:     "should sort warnings of a single list",
+			input:    []admission.Warnings{{"b", "c", "a"}},
+			expected: admission.Warnings{"a", "b", "c"},
+		},
+		{
+			desc:     "should merge, deduplicate and sort warnings of multiple lists",
+			input:    []admission.Warnings{{"field foo is deprecated", "b"}, nil, {"b", "", "a", "field foo is deprecated"}},
+			expected: admission.Warnings{"a", "b", "field foo is deprecated"},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			assert.Equal(t, tC.expected, MergeWarnings(tC.input...))
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package jsonpatch
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	jpapi "github.com/openmcp-project/controller-utils/api/jsonpatch"
+)
+
+// ApplyBatch applies the same patches to each of the given JSON documents, using a pool of the given number of workers.
+// If workers is less than 1, a single worker is used.
+// The returned documents are in the same order as the given ones.
+// If applying the patches fails for some documents, the corresponding entries in the result are nil
+// and the returned error aggregates the errors for all failed documents, each prefixed with the index of the document.
+// The given documents are never modified.
+func ApplyBatch(docs [][]byte, patches jpapi.JSONPatches, workers int, opts ...Option) ([][]byte, error) {
+	if workers < 1 {
+		workers = 1
+	}
+	patch := New(patches...)
+	res := make([][]byte, len(docs))
+	errs := make([]error, len(docs))
+
+	indices := make(chan int)
+	wg := &sync.WaitGroup{}
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				patched, err := patch.Apply(docs[i], opts...)
+				if err != nil {
+					errs[i] = fmt.Errorf("document %d: %w", i, err)
+					continue
+				}
+				res[i] = patched
+			}
+		}()
+	}
+	for i := range docs {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+
+	return res, errors.Join(errs...)
+}
@@ -0,0 +1,57 @@
+package jsonpatch_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	jpapi "github.com/openmcp-project/controller-utils/api/jsonpatch"
+	"github.com/openmcp-project/controller-utils/pkg/jsonpatch"
+)
+
+var _ = Describe("ApplyBatch", func() {
+
+	It("should apply the patches to all documents and preserve their order", func() {
+		docs := make([][]byte, 100)
+		for i := range docs {
+			docs[i] = fmt.Appendf(nil, `{"index":%d}`, i)
+		}
+		patches := newPatches(newPatch(jpapi.ADD, "/foo", "bar", ""))
+
+		res, err := jsonpatch.ApplyBatch(docs, patches, 8)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(HaveLen(len(docs)))
+		for i := range res {
+			Expect(res[i]).To(MatchJSON(fmt.Sprintf(`{"index":%d,"foo":"bar"}`, i)))
+			Expect(docs[i]).To(MatchJSON(fmt.Sprintf(`{"index":%d}`, i)))
+		}
+	})
+
+	It("should report the indices of the documents that could not be patched", func() {
+		docs := [][]byte{
+			[]byte(`{"foo":"bar"}`),
+			[]byte(`{"foo":"baz"}`),
+			[]byte(`{"foo":"bar"}`),
+			[]byte(`{"foo":"baz"}`),
+		}
+		patches := newPatches(
+			newPatch(jpapi.TEST, "/foo", "bar", ""),
+			newPatch(jpapi.ADD, "/abc", "def", ""),
+		)
+
+		res, err := jsonpatch.ApplyBatch(docs, patches, 0)
+		Expect(err).To(HaveOccurred())
+		Expect(jsonpatch.IsTestFailure(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("document 1:"))
+		Expect(err.Error()).To(ContainSubstring("document 3:"))
+		Expect(err.Error()).ToNot(ContainSubstring("document 0:"))
+		Expect(err.Error()).ToNot(ContainSubstring("document 2:"))
+		Expect(res).To(HaveLen(len(docs)))
+		Expect(res[0]).To(MatchJSON(`{"foo":"bar","abc":"def"}`))
+		Expect(res[1]).To(BeNil())
+		Expect(res[2]).To(MatchJSON(`{"foo":"bar","abc":"def"}`))
+		Expect(res[3]).To(BeNil())
+	})
+
+})
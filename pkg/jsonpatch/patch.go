@@ -1,17 +1,29 @@
 package jsonpatch
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
 
 	jplib "github.com/evanphx/json-patch/v5"
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/yaml"
 
 	jpapi "github.com/openmcp-project/controller-utils/api/jsonpatch"
 )
 
+// ErrTestOperationFailed is returned (wrapped) when applying a patch fails because one of its 'test' operations did not match the document.
+// Use IsTestFailure or errors.Is to check for it.
+var ErrTestOperationFailed = errors.New("JSONPatch test operation failed")
+
+// IsTestFailure returns true if the given error (or any error it wraps) is ErrTestOperationFailed.
+func IsTestFailure(err error) bool {
+	return errors.Is(err, ErrTestOperationFailed)
+}
+
 type PatchValueData = apiextensionsv1.JSON
 
 type Untyped = []byte
@@ -53,6 +65,8 @@ func NewTyped[T any](patches ...jpapi.JSONPatch) *TypedPatch[T] {
 // If the generic type is Untyped (which is an alias for []byte),
 // it will treat the document as raw JSON bytes.
 // Otherwise, doc is marshalled to JSON before applying the patch and then again unmarshalled back to the original type afterwards.
+// If a 'test' operation of the patch does not match, the remaining operations are not applied and an error wrapping ErrTestOperationFailed is returned.
+// The given document is never modified.
 func (p *TypedPatch[T]) Apply(doc T, options ...Option) (T, error) {
 	var result T
 	var rawDoc []byte
@@ -67,6 +81,79 @@ func (p *TypedPatch[T]) Apply(doc T, options ...Option) (T, error) {
 		rawDoc = tmp
 	}
 
+	rawDoc, err := p.applyRaw(rawDoc, options...)
+	if err != nil {
+		return result, err
+	}
+
+	if isUntyped {
+		return any(rawDoc).(T), nil
+	}
+	if err := json.Unmarshal(rawDoc, &result); err != nil {
+		return result, fmt.Errorf("failed to unmarshal result into type %T: %w", result, err)
+	}
+	return result, nil
+}
+
+// ApplyYAML applies the patch to the given YAML document.
+// The document is converted to JSON, the patch is applied, and the result is converted back to YAML.
+// This works independently of the generic type of the patch.
+// It takes the same options as Apply, but note that the Indent option has no effect on the YAML output.
+// Also note that the conversion does neither preserve comments nor the order of keys, the keys in the result are sorted alphabetically.
+func (p *TypedPatch[T]) ApplyYAML(doc []byte, options ...Option) ([]byte, error) {
+	rawDoc, err := yaml.YAMLToJSON(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert YAML document to JSON: %w", err)
+	}
+	rawDoc, err = p.applyRaw(rawDoc, options...)
+	if err != nil {
+		return nil, err
+	}
+	res, err := yaml.JSONToYAML(rawDoc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert result to YAML: %w", err)
+	}
+	return res, nil
+}
+
+// ApplyIfChanged applies the patch to the given JSON document, like Apply does for Untyped patches.
+// The returned bool indicates whether the result differs from the given document.
+// Both documents are canonicalized before comparing them, so differences in formatting or key order do not count as change.
+// This works independently of the generic type of the patch.
+// Callers can use it to skip persisting the result if the patch did not change anything.
+func (p *TypedPatch[T]) ApplyIfChanged(doc []byte, options ...Option) ([]byte, bool, error) {
+	res, err := p.applyRaw(doc, options...)
+	if err != nil {
+		return nil, false, err
+	}
+	canonicalDoc, err := canonicalizeJSON(doc)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to canonicalize document: %w", err)
+	}
+	canonicalRes, err := canonicalizeJSON(res)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to canonicalize result: %w", err)
+	}
+	return res, !bytes.Equal(canonicalDoc, canonicalRes), nil
+}
+
+// canonicalizeJSON converts the given JSON document into a canonical form, with sorted keys and without insignificant whitespace.
+// Numbers are kept as they are, so that large integers don't lose precision by being converted to float64.
+func canonicalizeJSON(doc []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber()
+	var data any
+	if err := dec.Decode(&data); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("unexpected data after top-level JSON value")
+	}
+	return json.Marshal(data)
+}
+
+// applyRaw applies the patch to the given JSON document.
+func (p *TypedPatch[T]) applyRaw(rawDoc []byte, options ...Option) ([]byte, error) {
 	opts := &Options{
 		ApplyOptions: jplib.NewApplyOptions(),
 	}
@@ -76,11 +163,11 @@ func (p *TypedPatch[T]) Apply(doc T, options ...Option) (T, error) {
 
 	rawPatch, err := json.Marshal(p)
 	if err != nil {
-		return result, fmt.Errorf("failed to marshal JSONPatch: %w", err)
+		return nil, fmt.Errorf("failed to marshal JSONPatch: %w", err)
 	}
 	patch, err := jplib.DecodePatch(rawPatch)
 	if err != nil {
-		return result, fmt.Errorf("failed to decode JSONPatch: %w", err)
+		return nil, fmt.Errorf("failed to decode JSONPatch: %w", err)
 	}
 
 	if opts.Indent != "" {
@@ -89,16 +176,12 @@ func (p *TypedPatch[T]) Apply(doc T, options ...Option) (T, error) {
 		rawDoc, err = patch.ApplyWithOptions(rawDoc, opts.ApplyOptions)
 	}
 	if err != nil {
-		return result, fmt.Errorf("failed to apply JSONPatch: %w", err)
-	}
-
-	if isUntyped {
-		return any(rawDoc).(T), nil
-	}
-	if err := json.Unmarshal(rawDoc, &result); err != nil {
-		return result, fmt.Errorf("failed to unmarshal result into type %T: %w", result, err)
+		if errors.Is(err, jplib.ErrTestFailed) {
+			return nil, fmt.Errorf("failed to apply JSONPatch: %w: %w", ErrTestOperationFailed, err)
+		}
+		return nil, fmt.Errorf("failed to apply JSONPatch: %w", err)
 	}
-	return result, nil
+	return rawDoc, nil
 }
 
 // SupportNegativeIndices decides whether to support non-standard practice of
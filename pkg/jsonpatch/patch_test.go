@@ -11,7 +11,15 @@ import (
 )
 
 const (
-	docBase = `{"foo":"bar","baz":{"foobar":"asdf"},"abc":[{"a":1},{"b":2},{"c":3}]}`
+	docBase     = `{"foo":"bar","baz":{"foobar":"asdf"},"abc":[{"a":1},{"b":2},{"c":3}]}`
+	yamlDocBase = `foo: bar # comment
+baz:
+  foobar: asdf
+abc:
+- a: 1
+- b: 2
+- c: 3
+`
 )
 
 var _ = Describe("JSONPatch", func() {
@@ -111,6 +119,91 @@ var _ = Describe("JSONPatch", func() {
 
 	})
 
+	Context("YAML", func() {
+
+		var yamlDoc []byte
+
+		BeforeEach(func() {
+			yamlDoc = []byte(yamlDocBase)
+		})
+
+		It("should not modify the content if the patch is empty", func() {
+			patch := jsonpatch.New(newPatches()...)
+			result, err := patch.ApplyYAML(yamlDoc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(result)).To(Equal(`abc:
+- a: 1
+- b: 2
+- c: 3
+baz:
+  foobar: asdf
+foo: bar
+`))
+			Expect(yamlDoc).To(Equal([]byte(yamlDocBase)))
+		})
+
+		It("should apply a simple patch", func() {
+			patch := jsonpatch.New(newPatches(newPatch(jpapi.ADD, "/foo", "baz", ""))...)
+			result, err := patch.ApplyYAML(yamlDoc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(result)).To(Equal(`abc:
+- a: 1
+- b: 2
+- c: 3
+baz:
+  foobar: asdf
+foo: baz
+`))
+			Expect(yamlDoc).To(Equal([]byte(yamlDocBase)))
+		})
+
+		It("should apply multiple patches with paths that need conversion in the correct order", func() {
+			patch := jsonpatch.New(newPatches(
+				newPatch(jpapi.ADD, ".foo", "baz", ""),
+				newPatch(jpapi.COPY, "baz.foobar", nil, ".foo"),
+				newPatch(jpapi.REPLACE, "abc[2].c", 6, ""),
+				newPatch(jpapi.REMOVE, ".abc[1]", nil, ""),
+			)...)
+			result, err := patch.ApplyYAML(yamlDoc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(result)).To(Equal(`abc:
+- a: 1
+- c: 6
+baz:
+  foobar: baz
+foo: baz
+`))
+			Expect(yamlDoc).To(Equal([]byte(yamlDocBase)))
+		})
+
+		It("should apply options correctly", func() {
+			patch := jsonpatch.New(newPatches(
+				newPatch(jpapi.REPLACE, "/abc/-1", map[string]any{"d": 4}, ""),
+			)...)
+			result, err := patch.ApplyYAML(yamlDoc, jsonpatch.Indent("  "))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(result)).To(Equal(`abc:
+- a: 1
+- b: 2
+- d: 4
+baz:
+  foobar: asdf
+foo: bar
+`))
+			Expect(yamlDoc).To(Equal([]byte(yamlDocBase)))
+
+			_, err = patch.ApplyYAML(yamlDoc, jsonpatch.SupportNegativeIndices(false))
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should return an error for invalid YAML", func() {
+			patch := jsonpatch.New(newPatches()...)
+			_, err := patch.ApplyYAML([]byte("foo: [bar"))
+			Expect(err).To(HaveOccurred())
+		})
+
+	})
+
 	Context("Typed", func() {
 
 		type abc struct {
@@ -205,6 +298,143 @@ var _ = Describe("JSONPatch", func() {
 
 	})
 
+	Context("ApplyIfChanged", func() {
+
+		It("should report a change if the patch modifies the document", func() {
+			patch := jsonpatch.New(newPatches(newPatch(jpapi.ADD, "/foo", "baz", ""))...)
+			result, changed, err := patch.ApplyIfChanged(doc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changed).To(BeTrue())
+			Expect(result).To(Equal([]byte(`{"foo":"baz","baz":{"foobar":"asdf"},"abc":[{"a":1},{"b":2},{"c":3}]}`)))
+			Expect(doc).To(Equal([]byte(docBase)))
+		})
+
+		It("should not report a change if the patch is effectively a no-op", func() {
+			patch := jsonpatch.New(newPatches(
+				newPatch(jpapi.REPLACE, "/baz/foobar", "asdf", ""),
+				newPatch(jpapi.COPY, "/tmp", nil, "/abc"),
+				newPatch(jpapi.REMOVE, "/tmp", nil, ""),
+			)...)
+			result, changed, err := patch.ApplyIfChanged(doc, jsonpatch.Indent("  "))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changed).To(BeFalse())
+			Expect(result).To(MatchJSON(doc))
+		})
+
+		It("should detect changes of large integers which cannot be represented as float64", func() {
+			largeDoc := []byte(`{"id":9007199254740993}`)
+			patch := jsonpatch.New(newPatches(newPatch(jpapi.REPLACE, "/id", int64(9007199254740992), ""))...)
+			result, changed, err := patch.ApplyIfChanged(largeDoc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changed).To(BeTrue())
+			Expect(result).To(MatchJSON(`{"id":9007199254740992}`))
+
+			patch = jsonpatch.New(newPatches(newPatch(jpapi.REPLACE, "/id", int64(9007199254740993), ""))...)
+			_, changed, err = patch.ApplyIfChanged(largeDoc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changed).To(BeFalse())
+		})
+
+		It("should return an error if the patch cannot be applied", func() {
+			patch := jsonpatch.New(newPatches(newPatch(jpapi.REMOVE, "/doesnotexist", nil, ""))...)
+			_, changed, err := patch.ApplyIfChanged(doc)
+			Expect(err).To(HaveOccurred())
+			Expect(changed).To(BeFalse())
+		})
+
+	})
+
+	Context("Test Operations", func() {
+
+		It("should apply the patch if all test operations succeed", func() {
+			patch := jsonpatch.New(newPatches(
+				newPatch(jpapi.TEST, "/foo", "bar", ""),
+				newPatch(jpapi.TEST, ".abc[0]", map[string]any{"a": 1}, ""),
+				newPatch(jpapi.ADD, "/foo", "baz", ""),
+			)...)
+			result, err := patch.Apply(doc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal([]byte(`{"foo":"baz","baz":{"foobar":"asdf"},"abc":[{"a":1},{"b":2},{"c":3}]}`)))
+			Expect(doc).To(Equal([]byte(docBase)))
+		})
+
+		It("should return ErrTestOperationFailed if a test operation fails", func() {
+			patch := jsonpatch.New(newPatches(
+				newPatch(jpapi.ADD, "/foo", "baz", ""),
+				newPatch(jpapi.TEST, "/baz/foobar", "foobaz", ""),
+				newPatch(jpapi.REMOVE, "/abc", nil, ""),
+			)...)
+			result, err := patch.Apply(doc)
+			Expect(err).To(HaveOccurred())
+			Expect(err).To(MatchError(jsonpatch.ErrTestOperationFailed))
+			Expect(jsonpatch.IsTestFailure(err)).To(BeTrue())
+			Expect(result).To(BeNil())
+			Expect(doc).To(Equal([]byte(docBase)))
+
+			typedPatch := jsonpatch.NewTyped[map[string]any](newPatches(newPatch(jpapi.TEST, "/foo", "baz", ""))...)
+			typedDoc := map[string]any{"foo": "bar"}
+			_, err = typedPatch.Apply(typedDoc)
+			Expect(jsonpatch.IsTestFailure(err)).To(BeTrue())
+			Expect(typedDoc).To(Equal(map[string]any{"foo": "bar"}))
+		})
+
+		It("should not report other errors as test failures", func() {
+			patch := jsonpatch.New(newPatches(newPatch(jpapi.REMOVE, "/doesnotexist", nil, ""))...)
+			_, err := patch.Apply(doc)
+			Expect(err).To(HaveOccurred())
+			Expect(jsonpatch.IsTestFailure(err)).To(BeFalse())
+			Expect(jsonpatch.IsTestFailure(nil)).To(BeFalse())
+		})
+
+	})
+
+	Context("Validate", func() {
+
+		It("should accept a well-formed patch", func() {
+			patch := jsonpatch.New(newPatches(
+				newPatch(jpapi.ADD, "/foo", "baz", ""),
+				newPatch(jpapi.REMOVE, ".abc[1]", nil, ""),
+				newPatch(jpapi.COPY, "/baz/foobar", nil, ".foo"),
+				newPatch(jpapi.TEST, "/foo", "baz", ""),
+			)...)
+			Expect(patch.Validate()).To(Succeed())
+			Expect(jsonpatch.New().Validate()).To(Succeed())
+		})
+
+		It("should report all problems of a malformed patch", func() {
+			patch := jsonpatch.New(newPatches(
+				newPatch(jpapi.ADD, "/foo", "baz", ""),
+				newPatch(jpapi.MOVE, "/foo", nil, ""),
+				newPatch("rename", "/foo", nil, ""),
+				newPatch(jpapi.REPLACE, ".foo[bar", nil, ""),
+			)...)
+			err := patch.Validate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("index 1: 'from' must be set for 'move' operations"))
+			Expect(err.Error()).To(ContainSubstring("index 2: unknown operation 'rename'"))
+			Expect(err.Error()).To(ContainSubstring("'value' must be set for 'replace' operations"))
+			Expect(err.Error()).To(ContainSubstring("invalid 'path'"))
+			Expect(err.Error()).ToNot(ContainSubstring("index 0"))
+		})
+
+		It("should accept an explicit null value, but not a missing one", func() {
+			var apiPatches jpapi.JSONPatches
+			Expect(json.Unmarshal([]byte(`[{"op":"test","path":"/foo","value":null},{"op":"add","path":"/bar"}]`), &apiPatches)).To(Succeed())
+			Expect(apiPatches).To(HaveLen(2))
+			Expect(apiPatches[0].Value).ToNot(BeNil())
+			Expect(apiPatches[1].Value).To(BeNil())
+			err := jsonpatch.New(apiPatches...).Validate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("index 1: 'value' must be set for 'add' operations"))
+			Expect(err.Error()).ToNot(ContainSubstring("index 0"))
+
+			marshalled, err := json.Marshal(apiPatches)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(marshalled).To(MatchJSON(`[{"op":"test","path":"/foo","value":null},{"op":"add","path":"/bar"}]`))
+		})
+
+	})
+
 	Context("API", func() {
 
 		It("should be able to marshal and unmarshal JSONPatches", func() {
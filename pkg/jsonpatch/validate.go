@@ -0,0 +1,57 @@
+package jsonpatch
+
+import (
+	"errors"
+	"fmt"
+
+	jpapi "github.com/openmcp-project/controller-utils/api/jsonpatch"
+)
+
+// Validate checks whether the patch is well-formed, without applying it to a document.
+// All operations are validated using ValidateOperation.
+// Returns an aggregated error listing all problems (prefixed with the index of the respective operation) or nil, if the patch is valid.
+// Note that this cannot detect problems that depend on the target document, e.g. paths that don't exist or failing 'test' operations.
+func (p *TypedPatch[T]) Validate() error {
+	if p == nil {
+		return nil
+	}
+	var errs error
+	for i, op := range p.JSONPatches {
+		if err := ValidateOperation(op); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("invalid operation at index %d: %w", i, err))
+		}
+	}
+	return errs
+}
+
+// ValidateOperation checks whether a single JSONPatch operation is well-formed.
+// It verifies that
+// - the operation is one of the operations known by RFC 6902
+// - 'from' is set for 'copy' and 'move' operations
+// - 'value' is set for 'add', 'replace', and 'test' operations (an explicit 'null' value is valid, see jpapi.JSONPatches.UnmarshalJSON)
+// - 'path' and 'from' can be converted by ConvertPath
+//
+// Returns an aggregated error listing all problems or nil, if the operation is valid.
+func ValidateOperation(op jpapi.JSONPatch) error {
+	var errs error
+	switch op.Op {
+	case jpapi.ADD, jpapi.REPLACE, jpapi.TEST:
+		if op.Value == nil {
+			errs = errors.Join(errs, fmt.Errorf("'value' must be set for '%s' operations", op.Op))
+		}
+	case jpapi.COPY, jpapi.MOVE:
+		if op.From == "" {
+			errs = errors.Join(errs, fmt.Errorf("'from' must be set for '%s' operations", op.Op))
+		}
+	case jpapi.REMOVE:
+	default:
+		errs = errors.Join(errs, fmt.Errorf("unknown operation '%s'", op.Op))
+	}
+	if _, err := ConvertPath(op.Path); err != nil {
+		errs = errors.Join(errs, fmt.Errorf("invalid 'path': %w", err))
+	}
+	if _, err := ConvertPath(op.From); err != nil {
+		errs = errors.Join(errs, fmt.Errorf("invalid 'from': %w", err))
+	}
+	return errs
+}
@@ -107,3 +107,48 @@ func (kcpl KeyConflictPreventionLayer) WithName(name string) logr.LogSink {
 	}
 	return kcpl.wrapKeyConflictLayer(kcpl.LogSink.WithName(name))
 }
+
+var _ logr.LogSink = levelFilterLayer{}
+
+// levelFilterLayer is a helper struct. It implements logr.LogSink by containing a LogSink internally,
+// to which all method calls are forwarded. It suppresses all info messages with a verbosity above maxVerbosity.
+// Error messages are never suppressed.
+type levelFilterLayer struct {
+	logr.LogSink
+	maxVerbosity int
+}
+
+// filterLevel wraps a levelFilterLayer around the given logger's LogSink, so that all messages below the given LogLevel are suppressed.
+// If the LogSink already is a levelFilterLayer, it is replaced instead of being wrapped again.
+func filterLevel(log logr.Logger, level LogLevel) logr.Logger {
+	sink := log.GetSink()
+	if sink == nil {
+		return log
+	}
+	if lfl, ok := sink.(levelFilterLayer); ok {
+		sink = lfl.LogSink
+	}
+	// WithSink doesn't call Init on the sink again, which would modify the call depth of the underlying sink every time the logger is fetched from a context
+	return log.WithSink(levelFilterLayer{
+		LogSink:      sink,
+		maxVerbosity: LevelToVerbosity(level),
+	})
+}
+
+func (lfl levelFilterLayer) Enabled(level int) bool {
+	return level <= lfl.maxVerbosity && lfl.LogSink.Enabled(level)
+}
+
+func (lfl levelFilterLayer) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	return levelFilterLayer{
+		LogSink:      lfl.LogSink.WithValues(keysAndValues...),
+		maxVerbosity: lfl.maxVerbosity,
+	}
+}
+
+func (lfl levelFilterLayer) WithName(name string) logr.LogSink {
+	return levelFilterLayer{
+		LogSink:      lfl.LogSink.WithName(name),
+		maxVerbosity: lfl.maxVerbosity,
+	}
+}
@@ -128,11 +128,31 @@ func (l Logger) WithName(name string) Logger {
 }
 
 // FromContext wraps the result of logr.FromContext into a logging.Logger.
+// If a LogLevel has been stored in the context via WithLevel, all messages below this level are suppressed by the returned logger.
 func FromContext(ctx context.Context) (Logger, error) {
 	log, err := logr.FromContext(ctx)
+	if err == nil {
+		if level, ok := ctx.Value(logLevelContextKey{}).(LogLevel); ok {
+			log = filterLevel(log, level)
+		}
+	}
 	return Wrap(log), err
 }
 
+type logLevelContextKey struct{}
+
+// WithLevel returns a new context which overrides the log level for all loggers fetched from it via the FromContext... functions.
+// Debug and info messages below the given level are suppressed, e.g. WithLevel(ctx, INFO) suppresses all debug messages.
+// Error messages are never suppressed and the override cannot enable messages which are disabled by the underlying logger itself.
+// If the context already contains a logger, it is replaced by a filtered one, so that the override also applies when the logger is fetched via logr.FromContext directly.
+func WithLevel(ctx context.Context, level LogLevel) context.Context {
+	ctx = context.WithValue(ctx, logLevelContextKey{}, level)
+	if log, err := FromContext(ctx); err == nil {
+		ctx = NewContext(ctx, log)
+	}
+	return ctx
+}
+
 // FromContextOrDiscard works like FromContext, but it will return a discard logger if no logger is found in the context.
 func FromContextOrDiscard(ctx context.Context) Logger {
 	log, err := FromContext(ctx)
@@ -208,6 +228,13 @@ func FromContextOrPanic(ctx context.Context) Logger {
 	return log
 }
 
+// WithValues fetches the logger from the context, enriches it with the given key-value-pairs and returns a new context containing the enriched logger.
+// If the context does not contain a logger, a discard logger is used instead.
+func WithValues(ctx context.Context, keysAndValues ...interface{}) context.Context {
+	_, ctx = FromContextOrDiscard(ctx).WithValuesAndContext(ctx, keysAndValues...)
+	return ctx
+}
+
 // NewContextWithDiscard adds a discard logger to the given context and returns the new context.
 func NewContextWithDiscard(ctx context.Context) context.Context {
 	return NewContext(ctx, Discard())
@@ -5,12 +5,14 @@
 package logging_test
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"testing"
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -47,6 +49,84 @@ var _ = Describe("Logging Framework Tests", func() {
 		Expect(reflect.DeepEqual(log, compareToLogger)).To(BeTrue(), "calling log.WithValues should not modify the logger")
 	})
 
+	Context("WithValues", func() {
+
+		It("should add the values to the logger in the returned context", func() {
+			var lines []string
+			log := logging.Wrap(funcr.NewJSON(func(obj string) {
+				lines = append(lines, obj)
+			}, funcr.Options{}))
+			ctx := logging.NewContext(context.Background(), log)
+
+			enrichedCtx := logging.WithValues(ctx, "foo", "bar", "answer", 42)
+			logging.FromContextOrPanic(enrichedCtx).Info("enriched")
+			logging.FromContextOrPanic(ctx).Info("original")
+			Expect(lines).To(HaveLen(2))
+			Expect(lines[0]).To(And(ContainSubstring(`"msg":"enriched"`), ContainSubstring(`"foo":"bar"`), ContainSubstring(`"answer":42`)))
+			Expect(lines[1]).To(And(ContainSubstring(`"msg":"original"`), Not(ContainSubstring(`"foo"`))))
+		})
+
+		It("should return a context containing a logger, even if the original context did not contain one", func() {
+			ctx := logging.WithValues(context.Background(), "foo", "bar")
+			_, err := logging.FromContext(ctx)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+	})
+
+	Context("WithLevel", func() {
+
+		var sink *TestLogSink
+		var ctx context.Context
+
+		BeforeEach(func() {
+			sink = NewTestLogSink(logging.DEBUG)
+			ctx = logging.NewContext(context.Background(), logging.Wrap(logr.New(sink)))
+		})
+
+		It("should suppress messages below the level stored in the context", func() {
+			log := logging.FromContextOrDiscard(logging.WithLevel(ctx, logging.INFO))
+			log.Debug("debug")
+			log.Info("info")
+			log.Error(nil, "error")
+			Expect(sink.Messages.Size()).To(Equal(2))
+			Expect(sink.Messages.Poll().Message).To(Equal("info"))
+			Expect(sink.Messages.Poll().Message).To(Equal("error"))
+
+			log = logging.FromContextOrDiscard(logging.WithLevel(ctx, logging.ERROR))
+			log.Debug("debug")
+			log.Info("info")
+			log.Error(nil, "error")
+			Expect(sink.Messages.Size()).To(Equal(1))
+			Expect(sink.Messages.Poll().Message).To(Equal("error"))
+		})
+
+		It("should not affect the original context", func() {
+			_ = logging.WithLevel(ctx, logging.ERROR)
+			logging.FromContextOrDiscard(ctx).Debug("debug")
+			Expect(sink.Messages.Size()).To(Equal(1))
+		})
+
+		It("should apply to loggers added to the context afterwards and to loggers fetched via logr directly", func() {
+			levelCtx := logging.WithLevel(context.Background(), logging.INFO)
+			levelCtx = logging.NewContext(levelCtx, logging.Wrap(logr.New(sink)))
+			logging.FromContextOrDiscard(levelCtx).Debug("debug")
+			Expect(sink.Messages.Size()).To(Equal(0))
+
+			levelCtx = logging.WithLevel(ctx, logging.INFO)
+			logr.FromContextOrDiscard(levelCtx).V(logging.LevelToVerbosity(logging.DEBUG)).Info("debug")
+			Expect(sink.Messages.Size()).To(Equal(0))
+		})
+
+		It("should replace a previous override instead of stacking it", func() {
+			levelCtx := logging.WithLevel(ctx, logging.ERROR)
+			levelCtx = logging.WithLevel(levelCtx, logging.DEBUG)
+			logging.FromContextOrDiscard(levelCtx).Debug("debug")
+			Expect(sink.Messages.Size()).To(Equal(1))
+		})
+
+	})
+
 	Context("LogRequeue", func() {
 
 		var log logging.Logger
@@ -91,6 +91,28 @@ func PairsToMap[K comparable, V any](pairs []Pair[K, V]) map[K]V {
 	return res
 }
 
+// FilterPairs returns a new list containing only the pairs for which the given function returns true.
+// The order of the pairs is preserved. The given list is not modified.
+func FilterPairs[K comparable, V any](pairs []Pair[K, V], keep func(Pair[K, V]) bool) []Pair[K, V] {
+	res := make([]Pair[K, V], 0, len(pairs))
+	for _, p := range pairs {
+		if keep(p) {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+// MapPairs returns a new list containing the pairs with their values transformed by the given function.
+// The keys and the order of the pairs are preserved. The given list is not modified.
+func MapPairs[K comparable, V any, W any](pairs []Pair[K, V], transform func(Pair[K, V]) W) []Pair[K, W] {
+	res := make([]Pair[K, W], len(pairs))
+	for i, p := range pairs {
+		res[i] = New(p.Key, transform(p))
+	}
+	return res
+}
+
 func (p Pair[K, V]) String() string {
 	return fmt.Sprintf("%v: %v", p.Key, p.Value)
 }
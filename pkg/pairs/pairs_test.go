@@ -126,6 +126,52 @@ var _ = Describe("Pairs", func() {
 			Expect(m).To(HaveKeyWithValue("baz", "asdf"))
 		})
 
+		It("should round-trip a map through MapToPairs and PairsToMap", func() {
+			src := map[string]string{
+				"foo": "bar",
+				"baz": "asdf",
+				"abc": "",
+			}
+			Expect(pairs.PairsToMap(pairs.MapToPairs(src))).To(Equal(src))
+			Expect(pairs.PairsToMap(pairs.MapToPairs(map[string]string{}))).To(BeEmpty())
+		})
+
+	})
+
+	Context("Transformation", func() {
+
+		It("should filter pairs", func() {
+			src := []pairs.Pair[string, string]{
+				pairs.New("foo", "bar"),
+				pairs.New("empty", ""),
+				pairs.New("baz", "asdf"),
+				pairs.New("also-empty", ""),
+			}
+			res := pairs.FilterPairs(src, func(p pairs.Pair[string, string]) bool {
+				return p.Value != ""
+			})
+			Expect(res).To(Equal([]pairs.Pair[string, string]{
+				pairs.New("foo", "bar"),
+				pairs.New("baz", "asdf"),
+			}))
+			Expect(src).To(HaveLen(4), "the original list should not be modified")
+			Expect(pairs.FilterPairs(src, func(_ pairs.Pair[string, string]) bool { return false })).To(BeEmpty())
+		})
+
+		It("should transform the values of pairs", func() {
+			src := []pairs.Pair[string, string]{
+				pairs.New("foo", "bar"),
+				pairs.New("baz", "asdf"),
+			}
+			res := pairs.MapPairs(src, func(p pairs.Pair[string, string]) int {
+				return len(p.Value)
+			})
+			Expect(res).To(Equal([]pairs.Pair[string, int]{
+				pairs.New("foo", 3),
+				pairs.New("baz", 4),
+			}))
+		})
+
 	})
 
 })
@@ -6,6 +6,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	v1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -82,4 +83,16 @@ var _ = Describe("ClusterRoleMutator", func() {
 		Expect(fakeClient.Get(ctx, client.ObjectKey{Name: "test-clusterrole"}, retrievedClusterRole)).To(Succeed())
 		Expect(retrievedClusterRole).To(Equal(clusterRole))
 	})
+
+	It("should delete the cluster role idempotently using DeleteResource", func() {
+		Expect(resources.CreateOrUpdateResource(ctx, fakeClient, mutator)).To(Succeed())
+		Expect(fakeClient.Get(ctx, client.ObjectKey{Name: "test-clusterrole"}, &v1.ClusterRole{})).To(Succeed())
+
+		Expect(resources.DeleteResource(ctx, fakeClient, mutator)).To(Succeed())
+		err := fakeClient.Get(ctx, client.ObjectKey{Name: "test-clusterrole"}, &v1.ClusterRole{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue(), "expected NotFound error, got: %v", err)
+
+		// deleting a non-existing resource should be a no-op
+		Expect(resources.DeleteResource(ctx, fakeClient, mutator)).To(Succeed())
+	})
 })
@@ -6,6 +6,8 @@ import (
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/openmcp-project/controller-utils/pkg/errors"
 )
 
 type Mutator[K client.Object] interface {
@@ -34,6 +36,8 @@ func CreateOrUpdateResource[K client.Object](ctx context.Context, clt client.Cli
 	return nil
 }
 
+// DeleteResource deletes the resource described by the given mutator's Empty object.
+// It is idempotent: if the resource does not exist, nil is returned.
 func DeleteResource[K client.Object](ctx context.Context, clt client.Client, m Mutator[K], opts ...client.DeleteOption) error {
 	res := m.Empty()
 	if err := clt.Delete(ctx, res, opts...); client.IgnoreNotFound(err) != nil {
@@ -41,3 +45,50 @@ func DeleteResource[K client.Object](ctx context.Context, clt client.Client, m M
 	}
 	return nil
 }
+
+// CreateOrUpdateResources calls CreateOrUpdateResource for each of the given mutators, in the given order.
+// If continueOnError is false, it stops at the first error and returns it.
+// Otherwise, all mutators are applied and the errors are aggregated into a single ReasonableError.
+// Since the mutators usually have different resource types, they have to be converted via Untyped first.
+func CreateOrUpdateResources(ctx context.Context, clt client.Client, continueOnError bool, mutators ...Mutator[client.Object]) error {
+	errs := errors.NewReasonableErrorList()
+	for _, m := range mutators {
+		if err := CreateOrUpdateResource(ctx, clt, m); err != nil {
+			if !continueOnError {
+				return err
+			}
+			errs.Append(err)
+		}
+	}
+	return errs.Aggregate()
+}
+
+// Untyped converts a typed Mutator into a Mutator[client.Object].
+// This allows to pass mutators for different resource types to functions like CreateOrUpdateResources.
+func Untyped[K client.Object](m Mutator[K]) Mutator[client.Object] {
+	return &untypedMutator[K]{m: m}
+}
+
+type untypedMutator[K client.Object] struct {
+	m Mutator[K]
+}
+
+func (u *untypedMutator[K]) Empty() client.Object {
+	return u.m.Empty()
+}
+
+func (u *untypedMutator[K]) Mutate(res client.Object) error {
+	typed, ok := res.(K)
+	if !ok {
+		return fmt.Errorf("unexpected type %T for %s", res, u.m.String())
+	}
+	return u.m.Mutate(typed)
+}
+
+func (u *untypedMutator[K]) String() string {
+	return u.m.String()
+}
+
+func (u *untypedMutator[K]) MetadataMutator() MetadataMutator {
+	return u.m.MetadataMutator()
+}
@@ -6,9 +6,11 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/openmcp-project/controller-utils/pkg/errors"
 	"github.com/openmcp-project/controller-utils/pkg/resources"
 	"github.com/openmcp-project/controller-utils/pkg/testing"
 )
@@ -64,4 +66,69 @@ var _ = Describe("Resource Functions", func() {
 		_, err = resources.GetResource(ctx, fakeClient, mutator)
 		Expect(err).To(HaveOccurred())
 	})
+
+	Context("CreateOrUpdateResources", func() {
+
+		BeforeEach(func() {
+			Expect(rbacv1.AddToScheme(scheme)).To(Succeed())
+			var err error
+			fakeClient, err = testing.GetFakeClient(scheme)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should create or update all resources", func() {
+			rules := []rbacv1.PolicyRule{
+				{
+					APIGroups: []string{""},
+					Resources: []string{"configmaps"},
+					Verbs:     []string{"get", "list"},
+				},
+			}
+			subjects := []rbacv1.Subject{
+				{
+					Kind:      rbacv1.ServiceAccountKind,
+					Name:      "test-sa",
+					Namespace: "test-namespace",
+				},
+			}
+			roleMutator := resources.NewRoleMutator("test-role", "test-namespace", rules)
+			roleBindingMutator := resources.NewRoleBindingMutator("test-role", "test-namespace", subjects, resources.NewRoleRef("test-role"))
+
+			Expect(resources.CreateOrUpdateResources(ctx, fakeClient, false, resources.Untyped(roleMutator), resources.Untyped(roleBindingMutator), resources.Untyped(mutator))).To(Succeed())
+
+			role, err := resources.GetResource(ctx, fakeClient, roleMutator)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(role.Rules).To(Equal(rules))
+			roleBinding, err := resources.GetResource(ctx, fakeClient, roleBindingMutator)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(roleBinding.Subjects).To(Equal(subjects))
+			Expect(roleBinding.RoleRef).To(Equal(resources.NewRoleRef("test-role")))
+			configMap, err := resources.GetResource(ctx, fakeClient, mutator)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(configMap.Data).To(Equal(data))
+		})
+
+		It("should stop at the first error unless continueOnError is set", func() {
+			// the scheme does not know about ClusterRoles, so applying this mutator fails
+			failingMutator := resources.NewClusterRoleMutator("test-clusterrole", nil)
+			scheme = runtime.NewScheme()
+			Expect(corev1.AddToScheme(scheme)).To(Succeed())
+			var err error
+			fakeClient, err = testing.GetFakeClient(scheme)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = resources.CreateOrUpdateResources(ctx, fakeClient, false, resources.Untyped(failingMutator), resources.Untyped(mutator))
+			Expect(err).To(HaveOccurred())
+			_, err = resources.GetResource(ctx, fakeClient, mutator)
+			Expect(err).To(HaveOccurred(), "resources after the failing one should not have been applied")
+
+			err = resources.CreateOrUpdateResources(ctx, fakeClient, true, resources.Untyped(failingMutator), resources.Untyped(mutator), resources.Untyped(failingMutator))
+			Expect(err).To(HaveOccurred())
+			Expect(err).To(BeAssignableToTypeOf(&errors.AggregatedError{}))
+			Expect(err.(*errors.AggregatedError).Unwrap()).To(HaveLen(2))
+			_, err = resources.GetResource(ctx, fakeClient, mutator)
+			Expect(err).ToNot(HaveOccurred(), "resources after the failing one should have been applied")
+		})
+
+	})
 })
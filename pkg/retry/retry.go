@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -18,6 +19,9 @@ type Client struct {
 	maxAttempts       int
 	timeout           time.Duration
 	context           context.Context
+	createIdempotency bool
+	retryOn           []func(error) bool
+	stopOn            []func(error) bool
 }
 
 // NewRetryingClient returns a retry.Client that implements client.Client, but retries each operation that can fail with the specified parameters.
@@ -27,6 +31,8 @@ type Client struct {
 // - backoffMultiplier: 1.0 (no backoff)
 // - maxAttempts: 0 (no limit on attempts)
 // - timeout: 1 second (timeout for retries)
+// - createIdempotency: false (AlreadyExists errors on retried creates are returned)
+// - retry policies: none (all errors are retried)
 // Use the builder-style With... methods to adapt the parameters.
 func NewRetryingClient(c client.Client) *Client {
 	if c == nil {
@@ -68,6 +74,11 @@ func (rc *Client) Timeout() time.Duration {
 	return rc.timeout
 }
 
+// CreateIdempotency returns whether AlreadyExists errors on retried Create calls are treated as success.
+func (rc *Client) CreateIdempotency() bool {
+	return rc.createIdempotency
+}
+
 /////////////
 // SETTERS //
 /////////////
@@ -120,6 +131,81 @@ func (rc *Client) WithTimeout(timeout time.Duration) *Client {
 	return rc
 }
 
+// WithCreateIdempotency configures how AlreadyExists errors are handled when a Create call is retried.
+// If a Create fails on the client side (e.g. due to a network error) but actually succeeded on the server side, the retried Create will fail with an AlreadyExists error.
+// If enabled, an AlreadyExists error that occurs on a retry after a different error is treated as success and the object is fetched from the cluster instead.
+// If the first attempt already fails with AlreadyExists, the error is returned as usual.
+// Note that this cannot distinguish between the object having been created by the previous attempt or by someone else in the meantime.
+// Default is false.
+// It returns the Client for chaining.
+func (rc *Client) WithCreateIdempotency(enabled bool) *Client {
+	rc.createIdempotency = enabled
+	return rc
+}
+
+// WithRetryOn restricts retrying to errors matching the given predicate.
+// If called multiple times, an error is retried if it matches any of the predicates.
+// If it is never called, all errors are retried, unless they match a predicate passed to WithStopOn.
+// Nil predicates are ignored.
+// It returns the Client for chaining.
+func (rc *Client) WithRetryOn(retryOn func(error) bool) *Client {
+	if retryOn != nil {
+		rc.retryOn = append(rc.retryOn, retryOn)
+	}
+	return rc
+}
+
+// WithStopOn prevents errors matching the given predicate from being retried.
+// It takes precedence over WithRetryOn.
+// If called multiple times, an error is not retried if it matches any of the predicates.
+// Nil predicates are ignored.
+// It returns the Client for chaining.
+func (rc *Client) WithStopOn(stopOn func(error) bool) *Client {
+	if stopOn != nil {
+		rc.stopOn = append(rc.stopOn, stopOn)
+	}
+	return rc
+}
+
+// WithRetryOnConflict restricts retrying to conflict errors, see WithRetryOn.
+// This is useful for updates based on an outdated version of an object.
+// It returns the Client for chaining.
+func (rc *Client) WithRetryOnConflict() *Client {
+	return rc.WithRetryOn(apierrors.IsConflict)
+}
+
+// WithRetryOnServerTimeout restricts retrying to server timeout and gateway timeout errors, see WithRetryOn.
+// It returns the Client for chaining.
+func (rc *Client) WithRetryOnServerTimeout() *Client {
+	return rc.WithRetryOn(func(err error) bool {
+		return apierrors.IsServerTimeout(err) || apierrors.IsTimeout(err)
+	})
+}
+
+// WithStopOnNotFound prevents NotFound errors from being retried, see WithStopOn.
+// It returns the Client for chaining.
+func (rc *Client) WithStopOnNotFound() *Client {
+	return rc.WithStopOn(apierrors.IsNotFound)
+}
+
+// shouldRetry returns whether the given error should be retried according to the configured retry policies.
+func (rc *Client) shouldRetry(err error) bool {
+	for _, stopOn := range rc.stopOn {
+		if stopOn(err) {
+			return false
+		}
+	}
+	if len(rc.retryOn) == 0 {
+		return true
+	}
+	for _, retryOn := range rc.retryOn {
+		if retryOn(err) {
+			return true
+		}
+	}
+	return false
+}
+
 // WithContext sets the context for the next call of either GroupVersionKindFor or IsObjectNamespaced.
 // Since the signature of these methods does not allow passing a context, and the retrying can not be cancelled without one,
 // this method is required to inject the context to be used for the aforementioned methods.
@@ -130,6 +216,7 @@ func (rc *Client) WithTimeout(timeout time.Duration) *Client {
 //	c.WithContext(ctx).IsObjectNamespaced(obj)
 //
 // If no context is injected via this method, both GroupVersionKindFor and IsObjectNamespaced will use the default context.Background().
+// Prefer GroupVersionKindForCtx and IsObjectNamespacedCtx, which take the context as argument instead.
 // It returns the Client for chaining.
 func (rc *Client) WithContext(ctx context.Context) *Client {
 	if ctx == nil {
@@ -181,6 +268,9 @@ func (op *operation) try(ctx context.Context) (bool, time.Duration) {
 
 	// if the operation failed, check if we should retry
 	op.attempts++
+	if !op.parent.shouldRetry(err) {
+		return false, 0
+	}
 	retryAfter := op.interval
 	op.interval = time.Duration(float64(op.interval) * op.parent.backoffMultiplier)
 	if (op.parent.maxAttempts > 0 && op.attempts >= op.parent.maxAttempts) || (op.parent.timeout > 0 && time.Now().Add(retryAfter).After(op.startTime.Add(op.parent.timeout))) {
@@ -192,8 +282,17 @@ func (op *operation) try(ctx context.Context) (bool, time.Duration) {
 }
 
 // retry executes the given method with the provided arguments, retrying on failure.
+// It resets the context injected via WithContext.
 func (rc *Client) retry(ctx context.Context, cfn callbackFn) {
 	rc.WithContext(context.Background()) // reset context
+	rc.retryWithoutReset(ctx, cfn)
+}
+
+// retryWithoutReset works like retry, but it does not reset the context injected via WithContext.
+func (rc *Client) retryWithoutReset(ctx context.Context, cfn callbackFn) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
 	op := rc.newOperation(cfn)
 	if rc.Timeout() > 0 {
 		var cancel context.CancelFunc
@@ -234,9 +333,16 @@ func (rc *Client) CreateOrPatch(ctx context.Context, obj client.Object, f contro
 }
 
 // Create wraps the client's Create method and retries it on failure.
+// See WithCreateIdempotency for how AlreadyExists errors on retries can be handled.
 func (rc *Client) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) (err error) {
+	var previousErr error
 	rc.retry(ctx, func(ctx context.Context) error {
 		err = rc.internal.Create(ctx, obj, opts...)
+		if rc.createIdempotency && apierrors.IsAlreadyExists(err) && previousErr != nil && !apierrors.IsAlreadyExists(previousErr) {
+			// a previous attempt has probably succeeded on the server side, fetch the object to mimic a successful create
+			err = rc.internal.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+		}
+		previousErr = err
 		return err
 	})
 	return
@@ -323,6 +429,26 @@ func (rc *Client) IsObjectNamespaced(obj runtime.Object) (namespaced bool, err e
 	return
 }
 
+// GroupVersionKindForCtx works like GroupVersionKindFor, but uses the given context for retrying instead of the one injected via WithContext.
+// The context injected via WithContext is neither used nor reset by this method.
+func (rc *Client) GroupVersionKindForCtx(ctx context.Context, obj runtime.Object) (gvk schema.GroupVersionKind, err error) {
+	rc.retryWithoutReset(ctx, func(ctx context.Context) error {
+		gvk, err = rc.internal.GroupVersionKindFor(obj)
+		return err
+	})
+	return
+}
+
+// IsObjectNamespacedCtx works like IsObjectNamespaced, but uses the given context for retrying instead of the one injected via WithContext.
+// The context injected via WithContext is neither used nor reset by this method.
+func (rc *Client) IsObjectNamespacedCtx(ctx context.Context, obj runtime.Object) (namespaced bool, err error) {
+	rc.retryWithoutReset(ctx, func(ctx context.Context) error {
+		namespaced, err = rc.internal.IsObjectNamespaced(obj)
+		return err
+	})
+	return
+}
+
 // RESTMapper calls the internal client's RESTMapper method.
 func (rc *Client) RESTMapper() meta.RESTMapper {
 	return rc.internal.RESTMapper()
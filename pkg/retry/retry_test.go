@@ -11,6 +11,7 @@ import (
 	. "github.com/onsi/gomega/gstruct"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
@@ -330,6 +331,46 @@ var _ = Describe("Client", func() {
 		Expect(after.Sub(now)).To(BeNumerically(">", 300*time.Millisecond))
 	})
 
+	It("should use the given context in GroupVersionKindForCtx and IsObjectNamespacedCtx", func() {
+		env, mc := defaultTestSetup()
+		c := retry.NewRetryingClient(env.Client()).WithMaxAttempts(0).WithTimeout(500 * time.Millisecond)
+
+		type dummy struct {
+			corev1.Namespace
+		}
+
+		mc.reset(-1)
+		cancelledCtx, cancel := context.WithCancel(env.Ctx)
+		cancel()
+		now := time.Now()
+		_, err := c.GroupVersionKindForCtx(cancelledCtx, &dummy{})
+		Expect(err).To(HaveOccurred())
+		_, err = c.IsObjectNamespacedCtx(cancelledCtx, &dummy{})
+		Expect(err).To(HaveOccurred())
+		after := time.Now()
+		Expect(after.Sub(now)).To(BeNumerically("<", 300*time.Millisecond))
+
+		// the given context should not be used for subsequent calls
+		now = time.Now()
+		_, err = c.GroupVersionKindFor(&dummy{})
+		Expect(err).To(HaveOccurred())
+		after = time.Now()
+		Expect(after.Sub(now)).To(BeNumerically(">", 300*time.Millisecond))
+
+		// the context injected via WithContext should neither be used nor reset
+		c.WithContext(cancelledCtx)
+		now = time.Now()
+		_, err = c.IsObjectNamespacedCtx(env.Ctx, &dummy{})
+		Expect(err).To(HaveOccurred())
+		after = time.Now()
+		Expect(after.Sub(now)).To(BeNumerically(">", 300*time.Millisecond))
+		now = time.Now()
+		_, err = c.IsObjectNamespaced(&dummy{})
+		Expect(err).To(HaveOccurred())
+		after = time.Now()
+		Expect(after.Sub(now)).To(BeNumerically("<", 300*time.Millisecond))
+	})
+
 	It("should pass the arguments through correctly", func() {
 		env, mc := defaultTestSetup()
 		c := retry.NewRetryingClient(env.Client())
@@ -429,4 +470,135 @@ var _ = Describe("Client", func() {
 		Expect(env.Client().Delete(env.Ctx, ns)).To(Succeed())
 	})
 
+	It("should treat AlreadyExists on retried creates as success if create idempotency is enabled", func() {
+		// the first create succeeds on the server side, but reports an error to the caller
+		createCalls := 0
+		env := testutils.NewEnvironmentBuilder().
+			WithFakeClient(nil).
+			WithFakeClientBuilderCall("WithInterceptorFuncs", interceptor.Funcs{
+				Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+					createCalls++
+					if createCalls == 1 {
+						// create a copy, so that the passed-in object is not modified, as if the response got lost
+						if err := c.Create(ctx, obj.DeepCopyObject().(client.Object), opts...); err != nil {
+							return err
+						}
+						return errMock
+					}
+					return c.Create(ctx, obj, opts...)
+				},
+			}).
+			Build()
+
+		ns := &corev1.Namespace{}
+		ns.Name = "test"
+		c := retry.NewRetryingClient(env.Client()).WithMaxAttempts(5).WithTimeout(0).WithCreateIdempotency(true)
+		Expect(c.CreateIdempotency()).To(BeTrue())
+		Expect(c.Create(env.Ctx, ns)).To(Succeed())
+		Expect(createCalls).To(Equal(2))
+		Expect(ns.ResourceVersion).ToNot(BeEmpty())
+
+		// AlreadyExists on the first attempt is not swallowed, not even when it is retried
+		createCalls = 1
+		ns = &corev1.Namespace{}
+		ns.Name = "test"
+		err := c.Create(env.Ctx, ns)
+		Expect(apierrors.IsAlreadyExists(err)).To(BeTrue())
+		Expect(createCalls).To(Equal(6))
+	})
+
+	It("should return AlreadyExists on retried creates if create idempotency is disabled", func() {
+		createCalls := 0
+		env := testutils.NewEnvironmentBuilder().
+			WithFakeClient(nil).
+			WithFakeClientBuilderCall("WithInterceptorFuncs", interceptor.Funcs{
+				Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+					createCalls++
+					if createCalls == 1 {
+						// create a copy, so that the passed-in object is not modified, as if the response got lost
+						if err := c.Create(ctx, obj.DeepCopyObject().(client.Object), opts...); err != nil {
+							return err
+						}
+						return errMock
+					}
+					return c.Create(ctx, obj, opts...)
+				},
+			}).
+			Build()
+
+		ns := &corev1.Namespace{}
+		ns.Name = "test"
+		c := retry.NewRetryingClient(env.Client()).WithMaxAttempts(2).WithTimeout(0)
+		Expect(c.CreateIdempotency()).To(BeFalse())
+		err := c.Create(env.Ctx, ns)
+		Expect(apierrors.IsAlreadyExists(err)).To(BeTrue())
+		Expect(createCalls).To(Equal(2))
+	})
+
+	Context("Retry Policies", func() {
+
+		// policyTestSetup returns a fake client whose Get calls fail with the provided errors before succeeding.
+		policyTestSetup := func(errs ...error) (*testutils.Environment, *int) {
+			calls := 0
+			env := testutils.NewEnvironmentBuilder().
+				WithFakeClient(nil).
+				WithFakeClientBuilderCall("WithInterceptorFuncs", interceptor.Funcs{
+					Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+						calls++
+						if calls <= len(errs) {
+							return errs[calls-1]
+						}
+						return c.Get(ctx, key, obj, opts...)
+					},
+				}).
+				Build()
+			ns := &corev1.Namespace{}
+			ns.Name = "test"
+			Expect(env.Client().Create(env.Ctx, ns)).To(Succeed())
+			return env, &calls
+		}
+		gr := corev1.Resource("namespaces")
+		conflictErr := apierrors.NewConflict(gr, "test", fmt.Errorf("conflict"))
+		notFoundErr := apierrors.NewNotFound(gr, "test")
+		timeoutErr := apierrors.NewServerTimeout(gr, "get", 1)
+
+		It("should retry conflicts with WithRetryOnConflict", func() {
+			env, calls := policyTestSetup(conflictErr)
+			c := retry.NewRetryingClient(env.Client()).WithMaxAttempts(5).WithTimeout(0).WithRetryOnConflict()
+			Expect(c.Get(env.Ctx, client.ObjectKey{Name: "test"}, &corev1.Namespace{})).To(Succeed())
+			Expect(*calls).To(Equal(2))
+		})
+
+		It("should not retry other errors with WithRetryOnConflict", func() {
+			env, calls := policyTestSetup(errMock)
+			c := retry.NewRetryingClient(env.Client()).WithMaxAttempts(5).WithTimeout(0).WithRetryOnConflict()
+			Expect(c.Get(env.Ctx, client.ObjectKey{Name: "test"}, &corev1.Namespace{})).To(MatchError(errMock))
+			Expect(*calls).To(Equal(1))
+		})
+
+		It("should retry errors matching any of the retry policies", func() {
+			env, calls := policyTestSetup(conflictErr, timeoutErr, conflictErr)
+			c := retry.NewRetryingClient(env.Client()).WithMaxAttempts(5).WithTimeout(0).WithRetryOnConflict().WithRetryOnServerTimeout()
+			Expect(c.Get(env.Ctx, client.ObjectKey{Name: "test"}, &corev1.Namespace{})).To(Succeed())
+			Expect(*calls).To(Equal(4))
+		})
+
+		It("should not retry NotFound errors with WithStopOnNotFound", func() {
+			env, calls := policyTestSetup(conflictErr, notFoundErr)
+			c := retry.NewRetryingClient(env.Client()).WithMaxAttempts(5).WithTimeout(0).WithStopOnNotFound()
+			err := c.Get(env.Ctx, client.ObjectKey{Name: "test"}, &corev1.Namespace{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			Expect(*calls).To(Equal(2))
+		})
+
+		It("should prefer stop policies over retry policies", func() {
+			env, calls := policyTestSetup(conflictErr)
+			c := retry.NewRetryingClient(env.Client()).WithMaxAttempts(5).WithTimeout(0).WithRetryOnConflict().WithStopOn(apierrors.IsConflict)
+			err := c.Get(env.Ctx, client.ObjectKey{Name: "test"}, &corev1.Namespace{})
+			Expect(apierrors.IsConflict(err)).To(BeTrue())
+			Expect(*calls).To(Equal(1))
+		})
+
+	})
+
 })
@@ -0,0 +1,128 @@
+package testing
+
+import (
+	"context"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+const (
+	CallGet         = "get"
+	CallList        = "list"
+	CallCreate      = "create"
+	CallUpdate      = "update"
+	CallPatch       = "patch"
+	CallApply       = "apply"
+	CallDelete      = "delete"
+	CallDeleteAllOf = "deleteallof"
+	CallWatch       = "watch"
+)
+
+// CallRecorder counts the calls that are performed via a client, per verb.
+// In contrast to the OperationRecorder, it records all calls, including reads and failed calls,
+// which makes it useful for testing retry or idempotency logic.
+// Calls to subresources are counted as '<subresource>/<verb>', e.g. 'status/update'.
+// Use ComplexEnvironmentBuilder.WithCallRecorder to install it for a cluster of a test environment.
+type CallRecorder struct {
+	lock   sync.Mutex
+	counts map[string]int
+}
+
+// NewCallRecorder creates a new CallRecorder without any recorded calls.
+func NewCallRecorder() *CallRecorder {
+	return &CallRecorder{
+		counts: map[string]int{},
+	}
+}
+
+// Count returns how often the given verb has been called.
+// Use the Call* constants for the verbs.
+func (r *CallRecorder) Count(verb string) int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.counts[verb]
+}
+
+// Reset removes all recorded calls.
+func (r *CallRecorder) Reset() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.counts = map[string]int{}
+}
+
+// Wrap returns a client that counts all calls performed via it and forwards them to the given client.
+func (r *CallRecorder) Wrap(c client.WithWatch) client.WithWatch {
+	return interceptor.NewClient(c, r.Funcs())
+}
+
+// Funcs returns interceptor functions which count the calls.
+func (r *CallRecorder) Funcs() interceptor.Funcs {
+	return interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			r.record(CallGet)
+			return c.Get(ctx, key, obj, opts...)
+		},
+		List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+			r.record(CallList)
+			return c.List(ctx, list, opts...)
+		},
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			r.record(CallCreate)
+			return c.Create(ctx, obj, opts...)
+		},
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			r.record(CallUpdate)
+			return c.Update(ctx, obj, opts...)
+		},
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			r.record(CallPatch)
+			return c.Patch(ctx, obj, patch, opts...)
+		},
+		Apply: func(ctx context.Context, c client.WithWatch, obj runtime.ApplyConfiguration, opts ...client.ApplyOption) error {
+			r.record(CallApply)
+			return c.Apply(ctx, obj, opts...)
+		},
+		Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+			r.record(CallDelete)
+			return c.Delete(ctx, obj, opts...)
+		},
+		DeleteAllOf: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteAllOfOption) error {
+			r.record(CallDeleteAllOf)
+			return c.DeleteAllOf(ctx, obj, opts...)
+		},
+		Watch: func(ctx context.Context, c client.WithWatch, obj client.ObjectList, opts ...client.ListOption) (watch.Interface, error) {
+			r.record(CallWatch)
+			return c.Watch(ctx, obj, opts...)
+		},
+		SubResourceGet: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, subResource client.Object, opts ...client.SubResourceGetOption) error {
+			r.record(subResourceName + "/" + CallGet)
+			return c.SubResource(subResourceName).Get(ctx, obj, subResource, opts...)
+		},
+		SubResourceCreate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+			r.record(subResourceName + "/" + CallCreate)
+			return c.SubResource(subResourceName).Create(ctx, obj, subResource, opts...)
+		},
+		SubResourceUpdate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+			r.record(subResourceName + "/" + CallUpdate)
+			return c.SubResource(subResourceName).Update(ctx, obj, opts...)
+		},
+		SubResourcePatch: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+			r.record(subResourceName + "/" + CallPatch)
+			return c.SubResource(subResourceName).Patch(ctx, obj, patch, opts...)
+		},
+		SubResourceApply: func(ctx context.Context, c client.Client, subResourceName string, obj runtime.ApplyConfiguration, opts ...client.SubResourceApplyOption) error {
+			r.record(subResourceName + "/" + CallApply)
+			return c.SubResource(subResourceName).Apply(ctx, obj, opts...)
+		},
+	}
+}
+
+func (r *CallRecorder) record(verb string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.counts[verb]++
+}
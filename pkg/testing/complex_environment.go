@@ -3,17 +3,25 @@ package testing
 import (
 	"context"
 	"fmt"
+	"maps"
 	"path"
 	"reflect"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/onsi/gomega"
 	"github.com/onsi/gomega/types"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/uuid"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -48,6 +56,20 @@ type ComplexEnvironment struct {
 	Log         logging.Logger
 	Clusters    map[string]client.Client
 	Reconcilers map[string]reconcile.Reconciler
+	// ReconcilersByType maps object types to the names of the reconcilers responsible for them.
+	// It is used by ReconcileObject.
+	ReconcilersByType map[schema.GroupVersionKind]string
+	// EventRecorders contains the fake event recorders, mapped to the names of the clusters they belong to.
+	EventRecorders map[string]*events.FakeRecorder
+
+	// reconcilerTargets maps reconciler names to the names of their target clusters.
+	reconcilerTargets map[string][]string
+	// constructedReconcilers contains the names of all reconcilers that have been created from a ReconcilerConstructor.
+	constructedReconcilers map[string]bool
+	// fakeClusterStatusObjects contains the objects for which the status subresource has been enabled, for each cluster with a fake client.
+	fakeClusterStatusObjects map[string][]client.Object
+	// builder is the builder which was used to construct this environment.
+	builder *ComplexEnvironmentBuilder
 }
 
 // Client returns the cluster client for the cluster with the given name.
@@ -60,6 +82,32 @@ func (e *ComplexEnvironment) Reconciler(name string) reconcile.Reconciler {
 	return e.Reconcilers[name]
 }
 
+// EventRecorder returns the fake event recorder for the cluster with the given name.
+// Returns nil if no event recorder has been configured for the cluster via the builder's WithEventRecorder method.
+func (e *ComplexEnvironment) EventRecorder(name string) *events.FakeRecorder {
+	return e.EventRecorders[name]
+}
+
+// RecordedEvents returns all events which have been recorded by the fake event recorder for the cluster with the given name since the last call of this method.
+// The events are removed from the recorder, so each event is returned only once.
+// Each event is formatted as '<type> <reason> <message>', see events.FakeRecorder for details.
+// Returns nil if no event recorder has been configured for the cluster.
+func (e *ComplexEnvironment) RecordedEvents(name string) []string {
+	rec := e.EventRecorders[name]
+	if rec == nil {
+		return nil
+	}
+	res := []string{}
+	for {
+		select {
+		case event := <-rec.Events:
+			res = append(res, event)
+		default:
+			return res
+		}
+	}
+}
+
 // ShouldReconcile calls the given reconciler with the given request and expects no error.
 func (e *ComplexEnvironment) ShouldReconcile(reconciler string, req reconcile.Request, optionalDescription ...interface{}) reconcile.Result {
 	return e.shouldReconcile(reconciler, req, optionalDescription...)
@@ -86,6 +134,51 @@ func (e *ComplexEnvironment) shouldEventuallyReconcile(reconciler string, req re
 	return res
 }
 
+// ShouldReconcileUntil calls the given reconciler with the given request repeatedly until the given condition is met or the timeout is reached.
+// Each reconciliation is expected to succeed, the condition is evaluated after each successful reconciliation.
+// The condition is called with the client of the reconciler's first target cluster.
+// If the reconciler has been passed in directly, the environment must contain exactly one cluster, whose client is used then.
+func (e *ComplexEnvironment) ShouldReconcileUntil(reconciler string, req reconcile.Request, cond func(client.Client) (bool, error), timeout, poll time.Duration, optionalDescription ...interface{}) reconcile.Result {
+	return e.shouldReconcileUntil(reconciler, req, cond, timeout, poll, optionalDescription...)
+}
+
+func (e *ComplexEnvironment) shouldReconcileUntil(reconciler string, req reconcile.Request, cond func(client.Client) (bool, error), timeout, poll time.Duration, optionalDescription ...interface{}) reconcile.Result {
+	c := e.reconcilerClient(reconciler)
+	gomega.ExpectWithOffset(2, c).ToNot(gomega.BeNil(), "unable to determine the cluster for reconciler '%s'", reconciler)
+	var res reconcile.Result
+	gomega.EventuallyWithOffset(2, func() error {
+		var err error
+		res, err = e.Reconcilers[reconciler].Reconcile(e.Ctx, req)
+		if err != nil {
+			return err
+		}
+		ok, err := cond(c)
+		if err != nil {
+			return fmt.Errorf("error evaluating condition: %w", err)
+		}
+		if !ok {
+			return fmt.Errorf("condition not met")
+		}
+		return nil
+	}, timeout, poll).Should(gomega.Succeed(), optionalDescription...)
+	return res
+}
+
+// reconcilerClient returns the client of the first target cluster of the given reconciler.
+// If the reconciler has no targets, the client of the only cluster is returned.
+// Returns nil if the client cannot be determined.
+func (e *ComplexEnvironment) reconcilerClient(reconciler string) client.Client {
+	if targets := e.reconcilerTargets[reconciler]; len(targets) > 0 {
+		return e.Clusters[targets[0]]
+	}
+	if len(e.Clusters) == 1 {
+		for _, c := range e.Clusters {
+			return c
+		}
+	}
+	return nil
+}
+
 // ShouldNotReconcile calls the given reconciler with the given request and expects an error.
 func (e *ComplexEnvironment) ShouldNotReconcile(reconciler string, req reconcile.Request, optionalDescription ...interface{}) reconcile.Result {
 	return e.shouldNotReconcile(reconciler, req, noMatcher, optionalDescription...)
@@ -122,6 +215,157 @@ func (e *ComplexEnvironment) shouldEventuallyNotReconcile(reconciler string, req
 	return res
 }
 
+// ReconcileObject calls the reconciler that has been registered for the type of the given object (via WithReconcilerForType) with a request for the object.
+// Returns an error if the object's type cannot be determined or no reconciler is registered for it.
+func (e *ComplexEnvironment) ReconcileObject(obj client.Object) (reconcile.Result, error) {
+	gvk, err := e.gvkForObject(obj)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	name, ok := e.ReconcilersByType[gvk]
+	if !ok {
+		return reconcile.Result{}, fmt.Errorf("no reconciler registered for type '%s'", gvk.String())
+	}
+	return e.Reconcilers[name].Reconcile(e.Ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(obj)})
+}
+
+// gvkForObject returns the GroupVersionKind of the given object.
+// If the object doesn't specify its kind, the schemes of the environment's clusters are used to determine it.
+func (e *ComplexEnvironment) gvkForObject(obj client.Object) (schema.GroupVersionKind, error) {
+	if gvk := obj.GetObjectKind().GroupVersionKind(); !gvk.Empty() {
+		return gvk, nil
+	}
+	for _, name := range slices.Sorted(maps.Keys(e.Clusters)) {
+		gvk, err := apiutil.GVKForObject(obj, e.Clusters[name].Scheme())
+		if err == nil {
+			return gvk, nil
+		}
+	}
+	return schema.GroupVersionKind{}, fmt.Errorf("unable to determine the type of object of type '%T', it is not known to any of the clusters' schemes", obj)
+}
+
+// EnableStatusSubresource enables the status subresource for the types of the given objects on the fake client of the cluster with the given name.
+// This is useful if objects of a type are created during a test, but WithDynamicObjectsWithStatus has not been called for the type before Build().
+// As the fake client does not support modifying the status subresource set after its creation, a new fake client is created,
+// which contains all objects (of all types known to the client's scheme) from the current one.
+// The new client replaces the old one in the environment, the client creation callbacks for the cluster are called again,
+// and all reconcilers that have been created from a ReconcilerConstructor targeting the cluster are re-created, which resets any state they hold.
+// Limitations:
+//   - Clients that have been retrieved from the environment before calling this method still point to the old fake client,
+//     which is not updated anymore. The same holds true for reconcilers that have been passed into the builder directly.
+//   - Only objects of types that are registered in the client's scheme (including their list type) are copied over.
+//
+// Returns an error if the cluster does not use a fake client or copying the objects fails.
+func (e *ComplexEnvironment) EnableStatusSubresource(clusterName string, objs ...client.Object) error {
+	statusObjs, ok := e.fakeClusterStatusObjects[clusterName]
+	if !ok {
+		return fmt.Errorf("cluster '%s' does not exist or does not use a fake client", clusterName)
+	}
+	existing, err := listAllObjects(e.Ctx, e.Clusters[clusterName])
+	if err != nil {
+		return fmt.Errorf("error listing existing objects of cluster '%s': %w", clusterName, err)
+	}
+	statusObjs = append(slices.Clone(statusObjs), objs...)
+	c := e.builder.buildFakeClient(clusterName, existing, statusObjs)
+	e.fakeClusterStatusObjects[clusterName] = statusObjs
+	e.Clusters[clusterName] = c
+
+	for _, callback := range e.builder.ClientCreationCallbacks[clusterName] {
+		callback(c)
+	}
+
+	for name := range e.constructedReconcilers {
+		re := e.builder.Reconcilers[name]
+		if !slices.Contains(re.Targets, clusterName) {
+			continue
+		}
+		targets := make([]client.Client, len(re.Targets))
+		for i, target := range re.Targets {
+			targets[i] = e.Clusters[target]
+		}
+		re.Reconciler = re.ReconcilerConstructor(targets...)
+		e.Reconcilers[name] = re.Reconciler
+	}
+	return nil
+}
+
+// listAllObjects lists all objects of all types known to the client's scheme.
+func listAllObjects(ctx context.Context, c client.Client) ([]client.Object, error) {
+	res := []client.Object{}
+	sc := c.Scheme()
+	for gvk := range sc.AllKnownTypes() {
+		if gvk.Version == runtime.APIVersionInternal || !strings.HasSuffix(gvk.Kind, "List") {
+			continue
+		}
+		if !sc.Recognizes(gvk.GroupVersion().WithKind(strings.TrimSuffix(gvk.Kind, "List"))) {
+			continue
+		}
+		rawList, err := sc.New(gvk)
+		if err != nil {
+			return nil, err
+		}
+		list, ok := rawList.(client.ObjectList)
+		if !ok {
+			continue
+		}
+		if err := c.List(ctx, list); err != nil {
+			// some special list types cannot be listed without further information, they cannot contain any objects anyway
+			continue
+		}
+		items, err := apimeta.ExtractList(list)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			if obj, ok := item.(client.Object); ok {
+				res = append(res, obj)
+			}
+		}
+	}
+	return res, nil
+}
+
+// ReconcileOutcome is the result of a single reconciliation performed by ReconcileConcurrently.
+type ReconcileOutcome struct {
+	Request reconcile.Request
+	Result  reconcile.Result
+	Err     error
+}
+
+// ReconcileConcurrently calls the given reconciler for all given requests, using a pool of the given number of workers.
+// The returned outcomes are in the same order as the requests.
+// If workers is less than 1, a single worker is used.
+// Running this with the '-race' flag helps to detect data races in the reconciler.
+func (e *ComplexEnvironment) ReconcileConcurrently(reconciler string, reqs []reconcile.Request, workers int) []ReconcileOutcome {
+	if workers < 1 {
+		workers = 1
+	}
+	rec := e.Reconcilers[reconciler]
+	outcomes := make([]ReconcileOutcome, len(reqs))
+	indices := make(chan int)
+	wg := &sync.WaitGroup{}
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				res, err := rec.Reconcile(e.Ctx, reqs[i])
+				outcomes[i] = ReconcileOutcome{
+					Request: reqs[i],
+					Result:  res,
+					Err:     err,
+				}
+			}
+		}()
+	}
+	for i := range reqs {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+	return outcomes
+}
+
 ///////////////////////////////////
 /// COMPLEX ENVIRONMENT BUILDER ///
 ///////////////////////////////////
@@ -136,6 +380,7 @@ type ComplexEnvironmentBuilder struct {
 	ClusterStatusObjects    map[string][]client.Object
 	ClusterInitObjectPaths  map[string][]string
 	ClientCreationCallbacks map[string][]func(client.Client)
+	ReconcilerTypes         map[string][]client.Object
 	loggerIsSet             bool
 	InjectUIDs              map[string]bool
 }
@@ -180,6 +425,7 @@ func NewComplexEnvironmentBuilder() *ComplexEnvironmentBuilder {
 		ClusterStatusObjects:    map[string][]client.Object{},
 		ClusterInitObjectPaths:  map[string][]string{},
 		ClientCreationCallbacks: map[string][]func(client.Client){},
+		ReconcilerTypes:         map[string][]client.Object{},
 		InjectUIDs:              map[string]bool{},
 	}
 }
@@ -278,6 +524,15 @@ func (eb *ComplexEnvironmentBuilder) WithReconcilerConstructor(name string, cons
 	return eb
 }
 
+// WithReconcilerForType registers the reconciler with the given name as responsible for the types of the given objects.
+// The environment's ReconcileObject method uses this information to dispatch objects to the correct reconciler.
+// The reconciler itself has to be configured via WithReconciler or WithReconcilerConstructor.
+// The objects are only used to determine the types, their content does not matter.
+func (eb *ComplexEnvironmentBuilder) WithReconcilerForType(name string, objects ...client.Object) *ComplexEnvironmentBuilder {
+	eb.ReconcilerTypes[name] = append(eb.ReconcilerTypes[name], objects...)
+	return eb
+}
+
 // WithAfterClientCreationCallback adds a callback function that will be called with the client with the given name as argument after the client has been created (during Build()).
 func (eb *ComplexEnvironmentBuilder) WithAfterClientCreationCallback(name string, callback func(client.Client)) *ComplexEnvironmentBuilder {
 	eb.ClientCreationCallbacks[name] = append(eb.ClientCreationCallbacks[name], callback)
@@ -294,6 +549,36 @@ func (eb *ComplexEnvironmentBuilder) WithUIDs(name string) *ComplexEnvironmentBu
 	return eb
 }
 
+// WithEventRecorder creates a fake event recorder for the cluster with the given name.
+// The recorder buffers up to bufferSize events, recording further events blocks until events are consumed via the environment's RecordedEvents method.
+// Use the builder's EventRecorder method to retrieve the recorder, e.g. to pass it into a reconciler in a ReconcilerConstructor.
+// After Build(), the recorder can also be retrieved via the environment's EventRecorder method.
+func (eb *ComplexEnvironmentBuilder) WithEventRecorder(name string, bufferSize int) *ComplexEnvironmentBuilder {
+	if eb.internal.EventRecorders == nil {
+		eb.internal.EventRecorders = map[string]*events.FakeRecorder{}
+	}
+	eb.internal.EventRecorders[name] = events.NewFakeRecorder(bufferSize)
+	return eb
+}
+
+// EventRecorder returns the fake event recorder for the cluster with the given name.
+// Returns nil if WithEventRecorder has not been called for the cluster before.
+func (eb *ComplexEnvironmentBuilder) EventRecorder(name string) *events.FakeRecorder {
+	return eb.internal.EventRecorders[name]
+}
+
+// WithCallRecorder installs a CallRecorder for the cluster with the given name and returns it.
+// The recorder counts all calls that are made via the cluster's fake client.
+// Note that this function registers an interceptor function via WithFakeClientBuilderCall,
+// so it cannot be combined with other calls to 'WithFakeClientBuilderCall(..., "WithInterceptorFuncs", ...)' or with WithUIDs for the same cluster.
+// In this case, use the CallRecorder's Wrap method instead.
+// Has no effect if the client for the respective cluster is passed in directly.
+func (eb *ComplexEnvironmentBuilder) WithCallRecorder(name string) *CallRecorder {
+	rec := NewCallRecorder()
+	eb.WithFakeClientBuilderCall(name, "WithInterceptorFuncs", rec.Funcs())
+	return rec
+}
+
 // WithFakeClientBuilderCall allows to inject method calls to fake.ClientBuilder when the fake clients are created during Build().
 // The fake clients are usually created using WithScheme(...).WithObjects(...).WithStatusSubresource(...).Build().
 // This function allows to inject additional method calls. It is only required for advanced use-cases.
@@ -341,6 +626,8 @@ func (eb *ComplexEnvironmentBuilder) Build() *ComplexEnvironment {
 	if res.Clusters == nil {
 		res.Clusters = map[string]client.Client{}
 	}
+	res.builder = eb
+	res.fakeClusterStatusObjects = map[string][]client.Object{}
 	for name, ce := range eb.Clusters {
 		if ce == nil {
 			panic(fmt.Errorf("no ClusterEnvironment set for cluster '%s'", name))
@@ -355,7 +642,6 @@ func (eb *ComplexEnvironmentBuilder) Build() *ComplexEnvironment {
 				ce.Scheme = DefaultScheme()
 			}
 			// create fake client
-			fcb := fake.NewClientBuilder().WithScheme(ce.Scheme)
 			objs := []client.Object{}
 			if len(eb.ClusterInitObjectPaths) > 0 {
 				// load objects from paths
@@ -378,26 +664,12 @@ func (eb *ComplexEnvironmentBuilder) Build() *ComplexEnvironment {
 						obj.SetUID(uuid.NewUUID())
 					}
 				}
-				fcb.WithInterceptorFuncs(interceptor.Funcs{
-					Create: InjectUIDOnObjectCreation(nil),
-				})
 			}
 			statusObjs := []client.Object{}
 			statusObjs = append(statusObjs, objs...)
 			statusObjs = append(statusObjs, eb.ClusterStatusObjects[name]...)
-			fcb.WithObjects(objs...).WithStatusSubresource(statusObjs...)
-			for _, call := range ce.FakeClientBuilderMethodCalls {
-				method := reflect.ValueOf(fcb).MethodByName(call.Method)
-				if !method.IsValid() {
-					panic(fmt.Errorf("method '%s' not found on fake.ClientBuilder", call.Method))
-				}
-				args := make([]reflect.Value, len(call.Args))
-				for i, arg := range call.Args {
-					args[i] = reflect.ValueOf(arg)
-				}
-				method.Call(args)
-			}
-			ce.Client = fcb.Build()
+			ce.Client = eb.buildFakeClient(name, objs, statusObjs)
+			res.fakeClusterStatusObjects[name] = statusObjs
 		}
 		res.Clusters[name] = ce.Client
 	}
@@ -406,6 +678,10 @@ func (eb *ComplexEnvironmentBuilder) Build() *ComplexEnvironment {
 	if res.Reconcilers == nil {
 		res.Reconcilers = map[string]reconcile.Reconciler{}
 	}
+	if res.reconcilerTargets == nil {
+		res.reconcilerTargets = map[string][]string{}
+	}
+	res.constructedReconcilers = map[string]bool{}
 	for name, re := range eb.Reconcilers {
 		if re == nil {
 			continue
@@ -426,8 +702,30 @@ func (eb *ComplexEnvironmentBuilder) Build() *ComplexEnvironment {
 				}
 			}
 			re.Reconciler = re.ReconcilerConstructor(targets...)
+			res.constructedReconcilers[name] = true
 		}
 		res.Reconcilers[name] = re.Reconciler
+		res.reconcilerTargets[name] = re.Targets
+	}
+
+	// register reconcilers for types
+	if res.ReconcilersByType == nil {
+		res.ReconcilersByType = map[schema.GroupVersionKind]string{}
+	}
+	for name, objs := range eb.ReconcilerTypes {
+		if _, ok := res.Reconcilers[name]; !ok {
+			panic(fmt.Errorf("unknown reconciler '%s' specified for types", name))
+		}
+		for _, obj := range objs {
+			gvk, err := res.gvkForObject(obj)
+			if err != nil {
+				panic(fmt.Errorf("error registering type for reconciler '%s': %w", name, err))
+			}
+			if other, ok := res.ReconcilersByType[gvk]; ok && other != name {
+				panic(fmt.Errorf("type '%s' is registered for multiple reconcilers: '%s' and '%s'", gvk.String(), other, name))
+			}
+			res.ReconcilersByType[gvk] = name
+		}
 	}
 
 	// call client creation callbacks
@@ -444,6 +742,31 @@ func (eb *ComplexEnvironmentBuilder) Build() *ComplexEnvironment {
 	return res
 }
 
+// buildFakeClient creates a fake client for the cluster with the given name, containing the given objects.
+// The status subresource is enabled for the types of the given status objects.
+func (eb *ComplexEnvironmentBuilder) buildFakeClient(name string, objs, statusObjs []client.Object) client.Client {
+	ce := eb.Clusters[name]
+	fcb := fake.NewClientBuilder().WithScheme(ce.Scheme)
+	if eb.InjectUIDs[name] {
+		fcb.WithInterceptorFuncs(interceptor.Funcs{
+			Create: InjectUIDOnObjectCreation(nil),
+		})
+	}
+	fcb.WithObjects(objs...).WithStatusSubresource(statusObjs...)
+	for _, call := range ce.FakeClientBuilderMethodCalls {
+		method := reflect.ValueOf(fcb).MethodByName(call.Method)
+		if !method.IsValid() {
+			panic(fmt.Errorf("method '%s' not found on fake.ClientBuilder", call.Method))
+		}
+		args := make([]reflect.Value, len(call.Args))
+		for i, arg := range call.Args {
+			args[i] = reflect.ValueOf(arg)
+		}
+		method.Call(args)
+	}
+	return fcb.Build()
+}
+
 // InjectUIDOnObjectCreation returns an interceptor function for Create which injects a random UID into the object, if it does not already have one.
 // If additionalLogic is nil, the object is created regularly afterwards.
 // Otherwise, additionalLogic is called.
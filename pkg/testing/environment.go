@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -38,6 +39,17 @@ func (e *Environment) Reconciler() reconcile.Reconciler {
 	return e.ComplexEnvironment.Reconciler(SimpleEnvironmentDefaultKey)
 }
 
+// EventRecorder returns the fake event recorder.
+// Returns nil if no event recorder has been configured via the builder's WithEventRecorder method.
+func (e *Environment) EventRecorder() *events.FakeRecorder {
+	return e.ComplexEnvironment.EventRecorder(SimpleEnvironmentDefaultKey)
+}
+
+// RecordedEvents returns all events which have been recorded by the fake event recorder since the last call of this method.
+func (e *Environment) RecordedEvents() []string {
+	return e.ComplexEnvironment.RecordedEvents(SimpleEnvironmentDefaultKey)
+}
+
 // ShouldReconcile calls the given reconciler with the given request and expects no error.
 func (e *Environment) ShouldReconcile(req reconcile.Request, optionalDescription ...interface{}) reconcile.Result {
 	return e.shouldReconcile(SimpleEnvironmentDefaultKey, req, optionalDescription...)
@@ -48,6 +60,12 @@ func (e *Environment) ShouldEventuallyReconcile(req reconcile.Request, timeout,
 	return e.shouldEventuallyReconcile(SimpleEnvironmentDefaultKey, req, timeout, poll, optionalDescription...)
 }
 
+// ShouldReconcileUntil calls the reconciler with the given request repeatedly until the given condition is met or the timeout is reached.
+// Each reconciliation is expected to succeed, the condition is evaluated with the environment's client after each successful reconciliation.
+func (e *Environment) ShouldReconcileUntil(req reconcile.Request, cond func(client.Client) (bool, error), timeout, poll time.Duration, optionalDescription ...interface{}) reconcile.Result {
+	return e.shouldReconcileUntil(SimpleEnvironmentDefaultKey, req, cond, timeout, poll, optionalDescription...)
+}
+
 // ShouldNotReconcile calls the given reconciler with the given request and expects an error.
 func (e *Environment) ShouldNotReconcile(req reconcile.Request, optionalDescription ...interface{}) reconcile.Result {
 	return e.shouldNotReconcile(SimpleEnvironmentDefaultKey, req, nil, optionalDescription...)
@@ -68,6 +86,19 @@ func (e *Environment) ShouldEventuallyNotReconcileWithError(req reconcile.Reques
 	return e.shouldEventuallyNotReconcile(SimpleEnvironmentDefaultKey, req, matcher, timeout, poll, optionalDescription...)
 }
 
+// ReconcileConcurrently calls the reconciler for all given requests, using a pool of the given number of workers.
+// The returned outcomes are in the same order as the requests.
+func (e *Environment) ReconcileConcurrently(reqs []reconcile.Request, workers int) []ReconcileOutcome {
+	return e.ComplexEnvironment.ReconcileConcurrently(SimpleEnvironmentDefaultKey, reqs, workers)
+}
+
+// EnableStatusSubresource enables the status subresource for the types of the given objects on the environment's fake client.
+// This re-creates the fake client and, if it has been created from a constructor, the reconciler.
+// See ComplexEnvironment.EnableStatusSubresource for details and limitations.
+func (e *Environment) EnableStatusSubresource(objs ...client.Object) error {
+	return e.ComplexEnvironment.EnableStatusSubresource(SimpleEnvironmentDefaultKey, objs...)
+}
+
 //////////////////////////////////
 /// SIMPLE ENVIRONMENT BUILDER ///
 //////////////////////////////////
@@ -175,6 +206,19 @@ func (eb *EnvironmentBuilder) WithUIDs() *EnvironmentBuilder {
 	return eb
 }
 
+// WithEventRecorder creates a fake event recorder, which buffers up to bufferSize events.
+// Use the builder's EventRecorder method to retrieve the recorder, e.g. to pass it into the reconciler in a ReconcilerConstructor.
+func (eb *EnvironmentBuilder) WithEventRecorder(bufferSize int) *EnvironmentBuilder {
+	eb.ComplexEnvironmentBuilder.WithEventRecorder(SimpleEnvironmentDefaultKey, bufferSize)
+	return eb
+}
+
+// EventRecorder returns the fake event recorder.
+// Returns nil if WithEventRecorder has not been called before.
+func (eb *EnvironmentBuilder) EventRecorder() *events.FakeRecorder {
+	return eb.ComplexEnvironmentBuilder.EventRecorder(SimpleEnvironmentDefaultKey)
+}
+
 // WithFakeClientBuilderCall allows to inject method calls to fake.ClientBuilder when the fake client is created during Build().
 // The fake client is usually created using WithScheme(...).WithObjects(...).WithStatusSubresource(...).Build().
 // This function allows to inject additional method calls. It is only required for advanced use-cases.
@@ -0,0 +1,280 @@
+package testing_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	testutils "github.com/openmcp-project/controller-utils/pkg/testing"
+)
+
+// namespaceReconciler creates the namespace with the name of the request, if it doesn't exist.
+type namespaceReconciler struct {
+	client client.Client
+}
+
+func (r *namespaceReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	ns := &corev1.Namespace{}
+	ns.Name = req.Name
+	if err := r.client.Create(ctx, ns); err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, nil
+}
+
+// eventReconciler records a 'Normal Reconciled <name>' event for the reconciled namespace.
+type eventReconciler struct {
+	client   client.Client
+	recorder events.EventRecorder
+}
+
+func (r *eventReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	ns := &corev1.Namespace{}
+	if err := r.client.Get(ctx, req.NamespacedName, ns); err != nil {
+		return reconcile.Result{}, err
+	}
+	r.recorder.Eventf(ns, nil, corev1.EventTypeNormal, "Reconciled", "Reconcile", "reconciled %s", ns.Name)
+	return reconcile.Result{}, nil
+}
+
+// recordingReconciler records all requests it is called with.
+type recordingReconciler struct {
+	requests []reconcile.Request
+}
+
+func (r *recordingReconciler) Reconcile(_ context.Context, req reconcile.Request) (reconcile.Result, error) {
+	r.requests = append(r.requests, req)
+	return reconcile.Result{}, nil
+}
+
+// labelAfterTwoCallsReconciler sets a label on the reconciled namespace on the second call.
+type labelAfterTwoCallsReconciler struct {
+	client client.Client
+	calls  int
+}
+
+func (r *labelAfterTwoCallsReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	r.calls++
+	if r.calls < 2 {
+		return reconcile.Result{}, nil
+	}
+	ns := &corev1.Namespace{}
+	if err := r.client.Get(ctx, req.NamespacedName, ns); err != nil {
+		return reconcile.Result{}, err
+	}
+	ns.Labels = map[string]string{"reconciled": "true"}
+	return reconcile.Result{}, r.client.Update(ctx, ns)
+}
+
+var _ = Describe("Environment", func() {
+
+	Context("ReconcileConcurrently", func() {
+
+		It("should reconcile all requests and return the outcomes in order", func() {
+			env := testutils.NewEnvironmentBuilder().WithFakeClient(nil).WithReconcilerConstructor(func(c client.Client) reconcile.Reconciler {
+				return &namespaceReconciler{client: c}
+			}).Build()
+
+			reqs := make([]reconcile.Request, 50)
+			for i := range reqs {
+				reqs[i] = testutils.RequestFromStrings(fmt.Sprintf("ns-%d", i))
+			}
+			outcomes := env.ReconcileConcurrently(reqs, 8)
+			Expect(outcomes).To(HaveLen(len(reqs)))
+			for i, o := range outcomes {
+				Expect(o.Request).To(Equal(reqs[i]))
+				Expect(o.Err).ToNot(HaveOccurred())
+			}
+
+			nsList := &corev1.NamespaceList{}
+			Expect(env.Client().List(env.Ctx, nsList)).To(Succeed())
+			Expect(nsList.Items).To(HaveLen(len(reqs)))
+		})
+
+		It("should return the errors of failed reconciliations", func() {
+			env := testutils.NewEnvironmentBuilder().WithFakeClient(nil).WithInitObjects(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-1"}}).WithReconcilerConstructor(func(c client.Client) reconcile.Reconciler {
+				return &namespaceReconciler{client: c}
+			}).Build()
+
+			reqs := []reconcile.Request{
+				testutils.RequestFromStrings("ns-0"),
+				testutils.RequestFromStrings("ns-1"),
+				testutils.RequestFromStrings("ns-2"),
+			}
+			outcomes := env.ReconcileConcurrently(reqs, 0)
+			Expect(outcomes).To(HaveLen(3))
+			Expect(outcomes[0].Err).ToNot(HaveOccurred())
+			Expect(outcomes[1].Err).To(HaveOccurred())
+			Expect(outcomes[2].Err).ToNot(HaveOccurred())
+		})
+
+	})
+
+	Context("ReconcileObject", func() {
+
+		It("should dispatch objects to the reconciler registered for their type", func() {
+			nsRec := &recordingReconciler{}
+			cmRec := &recordingReconciler{}
+			env := testutils.NewComplexEnvironmentBuilder().
+				WithFakeClient("cluster", nil).
+				WithReconciler("namespaces", nsRec).
+				WithReconciler("configmaps", cmRec).
+				WithReconcilerForType("namespaces", &corev1.Namespace{}).
+				WithReconcilerForType("configmaps", &corev1.ConfigMap{}).
+				Build()
+
+			_, err := env.ReconcileObject(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "foo"}})
+			Expect(err).ToNot(HaveOccurred())
+			_, err = env.ReconcileObject(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "bar", Namespace: "foo"}})
+			Expect(err).ToNot(HaveOccurred())
+			// the type is taken from the object itself, if set
+			cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "baz", Namespace: "foo"}}
+			cm.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("ConfigMap"))
+			_, err = env.ReconcileObject(cm)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(nsRec.requests).To(ConsistOf(testutils.RequestFromStrings("foo")))
+			Expect(cmRec.requests).To(ConsistOf(testutils.RequestFromStrings("bar", "foo"), testutils.RequestFromStrings("baz", "foo")))
+		})
+
+		It("should return an error if no reconciler is registered for the object's type", func() {
+			env := testutils.NewComplexEnvironmentBuilder().
+				WithFakeClient("cluster", nil).
+				WithReconciler("namespaces", &recordingReconciler{}).
+				WithReconcilerForType("namespaces", &corev1.Namespace{}).
+				Build()
+
+			_, err := env.ReconcileObject(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "bar", Namespace: "foo"}})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should panic if a type is registered for multiple reconcilers", func() {
+			Expect(func() {
+				testutils.NewComplexEnvironmentBuilder().
+					WithFakeClient("cluster", nil).
+					WithReconciler("a", &recordingReconciler{}).
+					WithReconciler("b", &recordingReconciler{}).
+					WithReconcilerForType("a", &corev1.Namespace{}).
+					WithReconcilerForType("b", &corev1.Namespace{}).
+					Build()
+			}).To(Panic())
+		})
+
+	})
+
+	Context("CallRecorder", func() {
+
+		It("should count the calls per verb", func() {
+			eb := testutils.NewEnvironmentBuilder().WithFakeClient(nil)
+			rec := eb.WithCallRecorder(testutils.SimpleEnvironmentDefaultKey)
+			env := eb.Build()
+
+			Expect(env.Client().Create(env.Ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "foo"}})).To(Succeed())
+			Expect(env.Client().Create(env.Ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "bar"}})).To(Succeed())
+			// failed calls are counted too
+			Expect(env.Client().Create(env.Ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "bar"}})).ToNot(Succeed())
+			ns := &corev1.Namespace{}
+			Expect(env.Client().Get(env.Ctx, client.ObjectKey{Name: "foo"}, ns)).To(Succeed())
+			Expect(env.Client().Status().Update(env.Ctx, ns)).To(Succeed())
+
+			Expect(rec.Count(testutils.CallCreate)).To(Equal(3))
+			Expect(rec.Count(testutils.CallGet)).To(Equal(1))
+			Expect(rec.Count(testutils.CallUpdate)).To(Equal(0))
+			Expect(rec.Count("status/" + testutils.CallUpdate)).To(Equal(1))
+
+			rec.Reset()
+			Expect(rec.Count(testutils.CallCreate)).To(Equal(0))
+		})
+
+	})
+
+	Context("ShouldReconcileUntil", func() {
+
+		It("should reconcile until the condition is met", func() {
+			rec := &labelAfterTwoCallsReconciler{}
+			env := testutils.NewComplexEnvironmentBuilder().
+				WithFakeClient("cluster", nil).
+				WithFakeClient("other", nil).
+				WithInitObjects("cluster", &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "foo"}}).
+				WithReconcilerConstructor("rec", func(c ...client.Client) reconcile.Reconciler {
+					rec.client = c[0]
+					return rec
+				}, "cluster").
+				Build()
+
+			env.ShouldReconcileUntil("rec", testutils.RequestFromStrings("foo"), func(c client.Client) (bool, error) {
+				ns := &corev1.Namespace{}
+				if err := c.Get(env.Ctx, client.ObjectKey{Name: "foo"}, ns); err != nil {
+					return false, err
+				}
+				return ns.Labels["reconciled"] == "true", nil
+			}, time.Second, 10*time.Millisecond)
+			Expect(rec.calls).To(Equal(2))
+		})
+
+	})
+
+	Context("EnableStatusSubresource", func() {
+
+		It("should preserve existing objects and re-create the reconciler with the new client", func() {
+			var recClient client.Client
+			env := testutils.NewEnvironmentBuilder().
+				WithFakeClient(nil).
+				WithInitObjects(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "foo"}}).
+				WithReconcilerConstructor(func(c client.Client) reconcile.Reconciler {
+					recClient = c
+					return &namespaceReconciler{client: c}
+				}).
+				Build()
+			Expect(env.Client().Create(env.Ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "bar", Namespace: "foo"}})).To(Succeed())
+			oldClient := env.Client()
+
+			Expect(env.EnableStatusSubresource(&corev1.ConfigMap{})).To(Succeed())
+			Expect(env.Client()).ToNot(BeIdenticalTo(oldClient))
+			Expect(recClient).To(BeIdenticalTo(env.Client()))
+			Expect(env.Client().Get(env.Ctx, client.ObjectKey{Name: "foo"}, &corev1.Namespace{})).To(Succeed())
+			Expect(env.Client().Get(env.Ctx, client.ObjectKey{Name: "bar", Namespace: "foo"}, &corev1.ConfigMap{})).To(Succeed())
+		})
+
+		It("should return an error for unknown clusters", func() {
+			env := testutils.NewEnvironmentBuilder().WithFakeClient(nil).Build()
+			Expect(env.ComplexEnvironment.EnableStatusSubresource("unknown", &corev1.ConfigMap{})).ToNot(Succeed())
+		})
+
+	})
+
+	Context("EventRecorder", func() {
+
+		It("should surface events recorded by the reconciler", func() {
+			eb := testutils.NewEnvironmentBuilder().
+				WithFakeClient(nil).
+				WithInitObjects(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "foo"}}).
+				WithEventRecorder(10)
+			env := eb.WithReconcilerConstructor(func(c client.Client) reconcile.Reconciler {
+				return &eventReconciler{client: c, recorder: eb.EventRecorder()}
+			}).Build()
+			Expect(env.EventRecorder()).To(BeIdenticalTo(eb.EventRecorder()))
+
+			env.ShouldReconcile(testutils.RequestFromStrings("foo"))
+			Expect(env.RecordedEvents()).To(ConsistOf("Normal Reconciled reconciled foo"))
+			Expect(env.RecordedEvents()).To(BeEmpty(), "events should have been drained")
+		})
+
+		It("should return nil for clusters without an event recorder", func() {
+			env := testutils.NewEnvironmentBuilder().WithFakeClient(nil).Build()
+			Expect(env.EventRecorder()).To(BeNil())
+			Expect(env.RecordedEvents()).To(BeNil())
+		})
+
+	})
+
+})
@@ -0,0 +1,152 @@
+package matchers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/onsi/gomega/types"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	OperationCreate = "create"
+	OperationUpdate = "update"
+	OperationPatch  = "patch"
+	OperationDelete = "delete"
+)
+
+// Operation describes a write operation that has been performed via a k8s client.
+// Use the testing package's OperationRecorder to record operations.
+type Operation struct {
+	// Verb is the kind of operation, e.g. OperationCreate.
+	Verb string
+	// GVK is the GroupVersionKind of the object the operation was performed on.
+	GVK schema.GroupVersionKind
+	// Namespace is the namespace of the object. Empty for cluster-scoped objects.
+	Namespace string
+	// Name is the name of the object.
+	Name string
+	// Subresource is the subresource the operation was performed on, e.g. 'status'. Empty if the operation was performed on the object itself.
+	Subresource string
+}
+
+// OperationsProvider is implemented by types that can return a list of recorded operations, e.g. the testing package's OperationRecorder.
+type OperationsProvider interface {
+	Operations() []Operation
+}
+
+func (o Operation) String() string {
+	sb := strings.Builder{}
+	sb.WriteString(o.Verb)
+	if o.Subresource != "" {
+		sb.WriteString("(")
+		sb.WriteString(o.Subresource)
+		sb.WriteString(")")
+	}
+	sb.WriteString(" ")
+	sb.WriteString(o.GVK.String())
+	sb.WriteString(" ")
+	if o.Namespace != "" {
+		sb.WriteString(o.Namespace)
+		sb.WriteString("/")
+	}
+	sb.WriteString(o.Name)
+	return sb.String()
+}
+
+// Matches checks if the current Operation matches the other Operation.
+// Note that this method is not symmetrical, fields which are empty in the receiver Operation are considered "arbitrary" and will match any value in the other Operation.
+// For the GVK, the group is only compared if the kind is set, because the empty string is a valid group.
+func (o Operation) Matches(other Operation) bool {
+	if o.Verb != "" && o.Verb != other.Verb {
+		return false
+	}
+	if o.GVK.Kind != "" && (o.GVK.Kind != other.GVK.Kind || o.GVK.Group != other.GVK.Group) {
+		return false
+	}
+	if o.GVK.Version != "" && o.GVK.Version != other.GVK.Version {
+		return false
+	}
+	if o.Namespace != "" && o.Namespace != other.Namespace {
+		return false
+	}
+	if o.Name != "" && o.Name != other.Name {
+		return false
+	}
+	if o.Subresource != "" && o.Subresource != other.Subresource {
+		return false
+	}
+	return true
+}
+
+// HavePerformedInOrder returns a Gomega matcher that checks if the expected operations have been performed in the given order.
+// The actual value must either be a []Operation or implement OperationsProvider.
+// Other operations may have been performed in between, the expected operations only have to appear in the given order.
+// Empty fields in the expected operations match any value, see Operation.Matches.
+func HavePerformedInOrder(ops ...Operation) types.GomegaMatcher {
+	return &operationsInOrderMatcher{expected: ops}
+}
+
+type operationsInOrderMatcher struct {
+	expected []Operation
+}
+
+var _ types.GomegaMatcher = &operationsInOrderMatcher{}
+
+func (m *operationsInOrderMatcher) GomegaString() string {
+	return formatOperations(m.expected)
+}
+
+// Match implements types.GomegaMatcher.
+func (m *operationsInOrderMatcher) Match(actualRaw any) (success bool, err error) {
+	actual, err := toOperations(actualRaw)
+	if err != nil {
+		return false, err
+	}
+	next := 0
+	for _, op := range actual {
+		if next >= len(m.expected) {
+			break
+		}
+		if m.expected[next].Matches(op) {
+			next++
+		}
+	}
+	return next >= len(m.expected), nil
+}
+
+// FailureMessage implements types.GomegaMatcher.
+func (m *operationsInOrderMatcher) FailureMessage(actualRaw any) (message string) {
+	actual, _ := toOperations(actualRaw)
+	return fmt.Sprintf("Expected operations\n%s\nto contain in order\n%s", formatOperations(actual), formatOperations(m.expected))
+}
+
+// NegatedFailureMessage implements types.GomegaMatcher.
+func (m *operationsInOrderMatcher) NegatedFailureMessage(actualRaw any) (message string) {
+	actual, _ := toOperations(actualRaw)
+	return fmt.Sprintf("Expected operations\n%s\nto not contain in order\n%s", formatOperations(actual), formatOperations(m.expected))
+}
+
+func toOperations(actualRaw any) ([]Operation, error) {
+	switch actual := actualRaw.(type) {
+	case []Operation:
+		return actual, nil
+	case OperationsProvider:
+		return actual.Operations(), nil
+	default:
+		return nil, fmt.Errorf("expected actual to be of type []Operation or to implement OperationsProvider, got %T", actualRaw)
+	}
+}
+
+func formatOperations(ops []Operation) string {
+	sb := strings.Builder{}
+	for i, op := range ops {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\t")
+		sb.WriteString(op.String())
+	}
+	return sb.String()
+}
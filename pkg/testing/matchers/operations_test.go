@@ -0,0 +1,98 @@
+package matchers_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	testutils "github.com/openmcp-project/controller-utils/pkg/testing"
+	. "github.com/openmcp-project/controller-utils/pkg/testing/matchers"
+)
+
+var _ = Describe("HavePerformedInOrder", func() {
+
+	nsGVK := corev1.SchemeGroupVersion.WithKind("Namespace")
+	saGVK := corev1.SchemeGroupVersion.WithKind("ServiceAccount")
+	roleGVK := rbacv1.SchemeGroupVersion.WithKind("Role")
+
+	var rec *testutils.OperationRecorder
+	var c client.Client
+	var env *testutils.Environment
+
+	BeforeEach(func() {
+		env = testutils.NewEnvironmentBuilder().WithFakeClient(nil).Build()
+		rec = testutils.NewOperationRecorder()
+		c = rec.Wrap(env.Client().(client.WithWatch))
+
+		ns := &corev1.Namespace{}
+		ns.SetName("test")
+		Expect(c.Create(env.Ctx, ns)).To(Succeed())
+		sa := &corev1.ServiceAccount{}
+		sa.SetName("test-sa")
+		sa.SetNamespace("test")
+		Expect(c.Create(env.Ctx, sa)).To(Succeed())
+		role := &rbacv1.Role{}
+		role.SetName("test-role")
+		role.SetNamespace("test")
+		Expect(c.Create(env.Ctx, role)).To(Succeed())
+		sa.SetLabels(map[string]string{"foo": "bar"})
+		Expect(c.Update(env.Ctx, sa)).To(Succeed())
+		Expect(c.Delete(env.Ctx, role)).To(Succeed())
+	})
+
+	It("should record the operations in the order in which they were performed", func() {
+		Expect(rec.Operations()).To(Equal([]Operation{
+			{Verb: OperationCreate, GVK: nsGVK, Name: "test"},
+			{Verb: OperationCreate, GVK: saGVK, Namespace: "test", Name: "test-sa"},
+			{Verb: OperationCreate, GVK: roleGVK, Namespace: "test", Name: "test-role"},
+			{Verb: OperationUpdate, GVK: saGVK, Namespace: "test", Name: "test-sa"},
+			{Verb: OperationDelete, GVK: roleGVK, Namespace: "test", Name: "test-role"},
+		}))
+	})
+
+	It("should not record failed operations", func() {
+		ns := &corev1.Namespace{}
+		ns.SetName("test")
+		Expect(c.Create(env.Ctx, ns)).ToNot(Succeed())
+		Expect(rec.Operations()).To(HaveLen(5))
+		rec.Reset()
+		Expect(rec.Operations()).To(BeEmpty())
+	})
+
+	It("should match operations that were performed in the given order", func() {
+		Expect(rec).To(HavePerformedInOrder(
+			Operation{Verb: OperationCreate, GVK: nsGVK, Name: "test"},
+			Operation{Verb: OperationCreate, GVK: saGVK, Namespace: "test", Name: "test-sa"},
+			Operation{Verb: OperationCreate, GVK: roleGVK, Namespace: "test", Name: "test-role"},
+		))
+		// operations in between are allowed and empty fields match anything
+		Expect(rec).To(HavePerformedInOrder(
+			Operation{Verb: OperationCreate, GVK: nsGVK},
+			Operation{Verb: OperationUpdate, Name: "test-sa"},
+			Operation{Verb: OperationDelete},
+		))
+		Expect(rec.Operations()).To(HavePerformedInOrder())
+	})
+
+	It("should not match operations that were performed in a different order or not at all", func() {
+		Expect(rec).ToNot(HavePerformedInOrder(
+			Operation{Verb: OperationCreate, GVK: roleGVK},
+			Operation{Verb: OperationCreate, GVK: nsGVK},
+		))
+		Expect(rec).ToNot(HavePerformedInOrder(
+			Operation{Verb: OperationPatch, GVK: saGVK},
+		))
+		Expect(rec).ToNot(HavePerformedInOrder(
+			Operation{Verb: OperationCreate, GVK: rbacv1.SchemeGroupVersion.WithKind("Namespace")},
+		))
+	})
+
+	It("should return an error for unsupported actual values", func() {
+		_, err := HavePerformedInOrder().Match("foo")
+		Expect(err).To(HaveOccurred())
+	})
+
+})
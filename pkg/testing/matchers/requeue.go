@@ -0,0 +1,106 @@
+package matchers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/onsi/gomega/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// RequeueAfter returns a Gomega matcher that checks if a reconcile.Result requeues after exactly the given duration.
+// The duration must be positive, use NoRequeue to check that a result does not requeue.
+// If the passed in 'actual' is not a reconcile.Result, the matcher will fail.
+func RequeueAfter(d time.Duration) types.GomegaMatcher {
+	return &requeueMatcher{expected: d}
+}
+
+// RequeueAfterApprox returns a Gomega matcher that checks if a reconcile.Result requeues after the given duration, with a deviation of at most the given tolerance.
+// This is useful if the requeue interval is computed from the current time or randomized.
+// The duration must be positive, use NoRequeue to check that a result does not requeue.
+// If the passed in 'actual' is not a reconcile.Result, the matcher will fail.
+func RequeueAfterApprox(d, tolerance time.Duration) types.GomegaMatcher {
+	return &requeueMatcher{expected: d, tolerance: tolerance}
+}
+
+// NoRequeue returns a Gomega matcher that checks if a reconcile.Result does not cause a requeue.
+// If the passed in 'actual' is not a reconcile.Result, the matcher will fail.
+func NoRequeue() types.GomegaMatcher {
+	return &requeueMatcher{noRequeue: true}
+}
+
+// requeueMatcher matches reconcile.Results.
+// If noRequeue is true, it matches results which don't requeue and expected and tolerance are ignored.
+type requeueMatcher struct {
+	noRequeue bool
+	expected  time.Duration
+	tolerance time.Duration
+}
+
+var _ types.GomegaMatcher = &requeueMatcher{}
+
+func (m *requeueMatcher) GomegaString() string {
+	if m.noRequeue {
+		return "no requeue"
+	}
+	if m.tolerance == 0 {
+		return fmt.Sprintf("requeue after %s", m.expected)
+	}
+	return fmt.Sprintf("requeue after %s (+/- %s)", m.expected, m.tolerance)
+}
+
+// Match implements types.GomegaMatcher.
+func (m *requeueMatcher) Match(actualRaw any) (success bool, err error) {
+	var actual reconcile.Result
+	switch res := actualRaw.(type) {
+	case reconcile.Result:
+		actual = res
+	case *reconcile.Result:
+		if res == nil {
+			return false, fmt.Errorf("expected actual to be a reconcile.Result, got nil")
+		}
+		actual = *res
+	default:
+		return false, fmt.Errorf("expected actual (or &actual) to be of type reconcile.Result, got %T", actualRaw)
+	}
+
+	if m.noRequeue {
+		return !actual.Requeue && actual.RequeueAfter == 0, nil //nolint:staticcheck
+	}
+	if m.expected <= 0 {
+		return false, fmt.Errorf("expected requeue duration must be positive, got %s (use NoRequeue to check that a result does not requeue)", m.expected)
+	}
+	diff := actual.RequeueAfter - m.expected
+	return diff <= m.tolerance && diff >= -m.tolerance, nil
+}
+
+// FailureMessage implements types.GomegaMatcher.
+func (m *requeueMatcher) FailureMessage(actual any) (message string) {
+	return fmt.Sprintf("Expected\n\t%s\nto %s", resultString(actual), m.GomegaString())
+}
+
+// NegatedFailureMessage implements types.GomegaMatcher.
+func (m *requeueMatcher) NegatedFailureMessage(actual any) (message string) {
+	if m.noRequeue {
+		return fmt.Sprintf("Expected\n\t%s\nto requeue", resultString(actual))
+	}
+	return fmt.Sprintf("Expected\n\t%s\nto not %s", resultString(actual), m.GomegaString())
+}
+
+// resultString returns a readable representation of the given reconcile.Result.
+func resultString(actualRaw any) string {
+	var actual reconcile.Result
+	switch res := actualRaw.(type) {
+	case reconcile.Result:
+		actual = res
+	case *reconcile.Result:
+		if res == nil {
+			return "<nil>"
+		}
+		actual = *res
+	default:
+		return fmt.Sprintf("%#v", actualRaw)
+	}
+	return fmt.Sprintf("Result{RequeueAfter: %s, Requeue: %t}", actual.RequeueAfter, actual.Requeue) //nolint:staticcheck
+}
@@ -0,0 +1,98 @@
+package matchers_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/openmcp-project/controller-utils/pkg/testing/matchers"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("Requeue Matchers", func() {
+
+	withRequeue := reconcile.Result{RequeueAfter: 30 * time.Second}
+	withoutRequeue := reconcile.Result{}
+
+	Context("RequeueAfter", func() {
+
+		It("should match results which requeue after exactly the expected duration", func() {
+			Expect(withRequeue).To(RequeueAfter(30 * time.Second))
+			Expect(&withRequeue).To(RequeueAfter(30 * time.Second))
+			Expect(withRequeue).ToNot(RequeueAfter(29 * time.Second))
+		})
+
+		It("should not match results without requeue", func() {
+			Expect(withoutRequeue).ToNot(RequeueAfter(30 * time.Second))
+		})
+
+		It("should produce a readable failure message", func() {
+			m := RequeueAfter(10 * time.Second)
+			Expect(m.Match(withRequeue)).To(BeFalse())
+			Expect(m.FailureMessage(withRequeue)).To(And(ContainSubstring("RequeueAfter: 30s"), ContainSubstring("requeue after 10s")))
+		})
+
+		It("should fail for other types", func() {
+			_, err := RequeueAfter(time.Second).Match(time.Second)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should reject non-positive durations instead of checking for no requeue", func() {
+			_, err := RequeueAfter(0).Match(withoutRequeue)
+			Expect(err).To(MatchError(ContainSubstring("must be positive")))
+			_, err = RequeueAfter(-time.Second).Match(withoutRequeue)
+			Expect(err).To(MatchError(ContainSubstring("must be positive")))
+		})
+
+	})
+
+	Context("RequeueAfterApprox", func() {
+
+		It("should match results within the tolerance", func() {
+			Expect(withRequeue).To(RequeueAfterApprox(28*time.Second, 2*time.Second))
+			Expect(withRequeue).To(RequeueAfterApprox(32*time.Second, 2*time.Second))
+			Expect(withRequeue).ToNot(RequeueAfterApprox(27*time.Second, 2*time.Second))
+			Expect(withRequeue).ToNot(RequeueAfterApprox(33*time.Second, 2*time.Second))
+		})
+
+		It("should not match results without requeue", func() {
+			Expect(withoutRequeue).ToNot(RequeueAfterApprox(30*time.Second, 5*time.Second))
+		})
+
+		It("should produce a readable failure message", func() {
+			m := RequeueAfterApprox(10*time.Second, time.Second)
+			Expect(m.Match(withRequeue)).To(BeFalse())
+			Expect(m.FailureMessage(withRequeue)).To(And(ContainSubstring("RequeueAfter: 30s"), ContainSubstring("requeue after 10s (+/- 1s)")))
+		})
+
+		It("should reject non-positive durations instead of checking for no requeue", func() {
+			_, err := RequeueAfterApprox(0, time.Second).Match(withoutRequeue)
+			Expect(err).To(MatchError(ContainSubstring("must be positive")))
+		})
+
+	})
+
+	Context("NoRequeue", func() {
+
+		It("should match results without requeue", func() {
+			Expect(withoutRequeue).To(NoRequeue())
+			Expect(&withoutRequeue).To(NoRequeue())
+		})
+
+		It("should not match results with requeue", func() {
+			Expect(withRequeue).ToNot(NoRequeue())
+			Expect(reconcile.Result{Requeue: true}).ToNot(NoRequeue()) //nolint:staticcheck
+		})
+
+		It("should produce a readable failure message", func() {
+			m := NoRequeue()
+			Expect(m.Match(withRequeue)).To(BeFalse())
+			Expect(m.FailureMessage(withRequeue)).To(And(ContainSubstring("RequeueAfter: 30s"), ContainSubstring("no requeue")))
+			Expect(m.NegatedFailureMessage(withoutRequeue)).To(And(ContainSubstring("RequeueAfter: 0s"), ContainSubstring("to requeue")))
+		})
+
+	})
+
+})
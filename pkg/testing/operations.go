@@ -0,0 +1,102 @@
+package testing
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/openmcp-project/controller-utils/pkg/testing/matchers"
+)
+
+// OperationRecorder records the write operations (create, update, patch, delete, and their subresource variants) that are performed via a client.
+// Only successful operations are recorded, in the order in which they occurred.
+// Use it together with the matchers.HavePerformedInOrder matcher:
+//
+//	rec := testing.NewOperationRecorder()
+//	c := rec.Wrap(env.Client().(client.WithWatch))
+//	// do something with c
+//	Expect(rec).To(matchers.HavePerformedInOrder(...))
+//
+// Alternatively, pass the result of Funcs() to 'WithFakeClientBuilderCall("WithInterceptorFuncs", ...)' of the environment builder.
+type OperationRecorder struct {
+	lock sync.Mutex
+	ops  []matchers.Operation
+}
+
+var _ matchers.OperationsProvider = &OperationRecorder{}
+
+// NewOperationRecorder creates a new, empty OperationRecorder.
+func NewOperationRecorder() *OperationRecorder {
+	return &OperationRecorder{}
+}
+
+// Operations returns a copy of the recorded operations.
+func (r *OperationRecorder) Operations() []matchers.Operation {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return slices.Clone(r.ops)
+}
+
+// Reset removes all recorded operations.
+func (r *OperationRecorder) Reset() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.ops = nil
+}
+
+// Wrap returns a client that records all write operations performed via it and forwards them to the given client.
+func (r *OperationRecorder) Wrap(c client.WithWatch) client.WithWatch {
+	return interceptor.NewClient(c, r.Funcs())
+}
+
+// Funcs returns interceptor functions which record the write operations.
+func (r *OperationRecorder) Funcs() interceptor.Funcs {
+	return interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			return r.record(c, matchers.OperationCreate, "", obj, c.Create(ctx, obj, opts...))
+		},
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			return r.record(c, matchers.OperationUpdate, "", obj, c.Update(ctx, obj, opts...))
+		},
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			return r.record(c, matchers.OperationPatch, "", obj, c.Patch(ctx, obj, patch, opts...))
+		},
+		Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+			return r.record(c, matchers.OperationDelete, "", obj, c.Delete(ctx, obj, opts...))
+		},
+		SubResourceCreate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+			return r.record(c, matchers.OperationCreate, subResourceName, obj, c.SubResource(subResourceName).Create(ctx, obj, subResource, opts...))
+		},
+		SubResourceUpdate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+			return r.record(c, matchers.OperationUpdate, subResourceName, obj, c.SubResource(subResourceName).Update(ctx, obj, opts...))
+		},
+		SubResourcePatch: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+			return r.record(c, matchers.OperationPatch, subResourceName, obj, c.SubResource(subResourceName).Patch(ctx, obj, patch, opts...))
+		},
+	}
+}
+
+// record adds the operation to the list, if err is nil.
+// It returns err unchanged.
+func (r *OperationRecorder) record(c client.Client, verb, subresource string, obj client.Object, err error) error {
+	if err != nil {
+		return err
+	}
+	op := matchers.Operation{
+		Verb:        verb,
+		Namespace:   obj.GetNamespace(),
+		Name:        obj.GetName(),
+		Subresource: subresource,
+	}
+	if gvk, gvkErr := apiutil.GVKForObject(obj, c.Scheme()); gvkErr == nil {
+		op.GVK = gvk
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.ops = append(r.ops, op)
+	return nil
+}
@@ -2,11 +2,15 @@ package threads
 
 import (
 	"context"
+	"errors"
+	"maps"
 	"os"
 	"os/signal"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"syscall"
+	"time"
 
 	"github.com/openmcp-project/controller-utils/pkg/logging"
 )
@@ -40,22 +44,32 @@ func NewThreadManager(mgrCtx context.Context, onFinish OnFinishFunc) *ThreadMana
 		runOnStart:        map[string]*Thread{},
 		mgrStop:           mgrCtx.Done(),
 		threadCancelFuncs: map[string]context.CancelFunc{},
+		threadStats:       map[string]*ThreadStats{},
+		threadWaiters:     map[string][]chan ThreadReturn{},
 		notifyOnStop:      make(chan struct{}),
+		readiness:         map[string]*readiness{},
+		readyGracePeriod:  DefaultReadyGracePeriod,
 	}
 }
 
 type ThreadManager struct {
-	lock              sync.Mutex                    // generic lock for the ThreadManager
-	lockThreadMap     sync.Mutex                    // lock specifically for the threadCancelFuncs map
-	returns           chan ThreadReturn             // channel to receive thread returns
-	onFinish          OnFinishFunc                  // function to call when a thread finishes
-	log               logging.Logger                // logger for the ThreadManager
-	runOnStart        map[string]*Thread            // is filled if threads are added before the ThreadManager is started
-	mgrStop           <-chan struct{}               // channel to stop the ThreadManager
-	stopped           atomic.Bool                   // indicates if the ThreadManager is stopped
-	waitForThreads    sync.WaitGroup                // used to wait for threads to finish when stopping the ThreadManager
-	threadCancelFuncs map[string]context.CancelFunc // map of thread ids to cancel functions
-	notifyOnStop      chan struct{}                 // channel is closed when the ThreadManager is stopped, used for Wait()
+	lock              sync.Mutex                     // generic lock for the ThreadManager
+	lockThreadMap     sync.Mutex                     // lock specifically for the threadCancelFuncs map
+	returns           chan ThreadReturn              // channel to receive thread returns
+	onFinish          OnFinishFunc                   // function to call when a thread finishes
+	log               logging.Logger                 // logger for the ThreadManager
+	runOnStart        map[string]*Thread             // is filled if threads are added before the ThreadManager is started
+	mgrStop           <-chan struct{}                // channel to stop the ThreadManager
+	stopped           atomic.Bool                    // indicates if the ThreadManager is stopped
+	draining          atomic.Bool                    // indicates if the ThreadManager is draining, new threads are rejected
+	waitForThreads    sync.WaitGroup                 // used to wait for threads to finish when stopping the ThreadManager
+	threadCancelFuncs map[string]context.CancelFunc  // map of thread ids to cancel functions
+	threadStats       map[string]*ThreadStats        // map of thread ids to their statistics, shares the lock with threadCancelFuncs
+	threadWaiters     map[string][]chan ThreadReturn // map of thread ids to one-time waiters, used for WaitForThread, shares the lock with threadCancelFuncs
+	notifyOnStop      chan struct{}                  // channel is closed when the ThreadManager is stopped, used for Wait()
+	lockReadiness     sync.Mutex                     // lock specifically for the readiness map and the grace period
+	readiness         map[string]*readiness          // map of thread ids to their readiness, used for RunAfter
+	readyGracePeriod  time.Duration                  // duration after which a running thread is considered ready
 }
 
 // Start starts the ThreadManager.
@@ -128,6 +142,41 @@ func (tm *ThreadManager) Stop() {
 	tm.stop()
 }
 
+// Drain stops the ThreadManager gracefully.
+// Panics if the ThreadManager has not been started yet.
+// The ThreadManager is marked as draining, which means that new threads are not run anymore, and Drain waits for the already running threads to finish.
+// In contrast to Stop, the contexts of the running threads are not cancelled.
+// If the given context is cancelled or its deadline expires before all threads have finished, Drain falls back to Stop, cancelling the remaining threads.
+// Calling Drain on an already stopped ThreadManager is a no-op.
+// Note that threads which restart themselves via their onFinish function (e.g. by using Restart) are not restarted while draining.
+func (tm *ThreadManager) Drain(ctx context.Context) {
+	if tm.IsStopped() {
+		tm.log.Debug("Drain called, but ThreadManager is already stopped, nothing to do")
+		return
+	}
+	tm.lock.Lock()
+	if !tm.isStarted() {
+		tm.lock.Unlock()
+		panic("Drain called on a ThreadManager that has not been started yet")
+	}
+	tm.log.Info("Draining ThreadManager, waiting for remaining threads to finish")
+	tm.draining.Store(true)
+	tm.lock.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		tm.waitForThreads.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		tm.log.Info("All threads finished while draining ThreadManager")
+	case <-ctx.Done():
+		tm.log.Info("Context expired while draining ThreadManager, cancelling remaining threads", "reason", ctx.Err().Error())
+	}
+	tm.Stop()
+}
+
 func (tm *ThreadManager) stop() {
 	if tm.IsStopped() {
 		tm.log.Debug("Stop called, but ThreadManager is already stopped, nothing to do")
@@ -161,6 +210,34 @@ func (tm *ThreadManager) Run(ctx context.Context, id string, work func(context.C
 	tm.RunThread(NewThread(ctx, id, work, onFinish))
 }
 
+var (
+	// ErrManagerNotStarted is returned by TryRun if the ThreadManager has not been started yet.
+	ErrManagerNotStarted = errors.New("ThreadManager has not been started yet")
+	// ErrManagerDraining is returned by TryRun if the ThreadManager is draining.
+	ErrManagerDraining = errors.New("ThreadManager is draining")
+	// ErrManagerStopped is returned by TryRun and WaitForThread if the ThreadManager has already been stopped.
+	ErrManagerStopped = errors.New("ThreadManager has already been stopped")
+)
+
+// TryRun works like Run, but instead of enqueuing the thread if the ThreadManager has not been started yet
+// or discarding it if the ThreadManager is draining or stopped, it returns ErrManagerNotStarted, ErrManagerDraining, or ErrManagerStopped, respectively.
+// In these cases, the thread is not run.
+func (tm *ThreadManager) TryRun(ctx context.Context, id string, work func(context.Context) error, onFinish OnFinishFunc) error {
+	tm.lock.Lock()
+	defer tm.lock.Unlock()
+	switch {
+	case tm.stopped.Load():
+		return ErrManagerStopped
+	case tm.draining.Load():
+		return ErrManagerDraining
+	case !tm.isStarted():
+		return ErrManagerNotStarted
+	}
+	t := NewThread(ctx, id, work, onFinish)
+	tm.run(&t)
+	return nil
+}
+
 // RunThread is the same as Run, but takes a Thread struct instead of the individual parameters.
 func (tm *ThreadManager) RunThread(t Thread) {
 	tm.lock.Lock()
@@ -177,6 +254,10 @@ func (tm *ThreadManager) run(t *Thread) {
 		tm.log.Info("Skipping thread run because ThreadManager is already stopped", "thread", t.id)
 		return
 	}
+	if tm.draining.Load() {
+		tm.log.Info("Skipping thread run because ThreadManager is draining", "thread", t.id)
+		return
+	}
 	if !tm.isStarted() {
 		tm.log.Debug("ThreadManager has not been started yet, enqueuing thread to run on start", "thread", t.ID())
 		_, ok := tm.runOnStart[t.id]
@@ -193,11 +274,23 @@ func (tm *ThreadManager) run(t *Thread) {
 		cancel()
 	}
 	tm.threadCancelFuncs[t.id] = t.cancel
+	stats := tm.threadStats[t.id]
+	if stats == nil {
+		stats = &ThreadStats{}
+		tm.threadStats[t.id] = stats
+	}
+	stats.StartedAt = time.Now()
+	if t.restarted {
+		// counted here instead of in Restart, because the restarted thread might still be discarded if the ThreadManager is stopped or draining
+		stats.Restarts++
+	}
 	tm.lockThreadMap.Unlock()
 	tm.waitForThreads.Go(func() {
 		var err error
 		if t.work != nil {
-			err = t.work(t.ctx)
+			ctx, r := tm.startReadiness(t)
+			err = t.work(ctx)
+			tm.finishReadiness(t.id, r)
 		} else {
 			tm.log.Debug("Thread has no work function", "thread", t.id)
 		}
@@ -219,6 +312,14 @@ func (tm *ThreadManager) run(t *Thread) {
 			tm.log.Debug("Calling the thread manager's onFinish function", "thread", tr.Thread.id)
 			tm.onFinish(t.ctx, tr)
 		}
+		tm.lockThreadMap.Lock()
+		waiters := tm.threadWaiters[t.id]
+		delete(tm.threadWaiters, t.id)
+		tm.lockThreadMap.Unlock()
+		for _, w := range waiters {
+			// waiter channels are buffered, so this doesn't block
+			w <- tr
+		}
 		tm.returns <- tr
 		tm.log.Debug("Thread finished", "thread", t.id)
 	})
@@ -242,6 +343,13 @@ func (tm *ThreadManager) IsStopped() bool {
 	return tm.stopped.Load()
 }
 
+// IsDraining returns true if Drain has been called on the ThreadManager and it has not been stopped yet.
+// This allows to distinguish a draining ThreadManager from a running or stopped one, e.g. for health endpoints.
+// Note that IsRunning also returns true while the ThreadManager is draining.
+func (tm *ThreadManager) IsDraining() bool {
+	return tm.draining.Load() && !tm.stopped.Load()
+}
+
 // IsRunning returns true if the ThreadManager is currently running,
 // meaning it has been started and not yet been stopped.
 // This is a convenience function that is equivalent to calling IsStarted() && !IsStopped().
@@ -249,6 +357,108 @@ func (tm *ThreadManager) IsRunning() bool {
 	return tm.IsStarted() && !tm.IsStopped()
 }
 
+// ManagerSnapshot describes the state of a ThreadManager at a specific point in time.
+// It is safe to marshal, e.g. for exposing it via a debug endpoint.
+type ManagerSnapshot struct {
+	// Started is true if the ThreadManager has been started, see IsStarted.
+	Started bool `json:"started"`
+	// Stopped is true if the ThreadManager has been stopped, see IsStopped.
+	Stopped bool `json:"stopped"`
+	// Draining is true if the ThreadManager is currently draining, see IsDraining.
+	Draining bool `json:"draining"`
+	// RunningThreadIDs contains the ids of all threads that are currently running, sorted alphabetically.
+	RunningThreadIDs []string `json:"runningThreadIDs"`
+	// PendingOnStartIDs contains the ids of all threads that have been added before the ThreadManager was started, sorted alphabetically.
+	// They will be run when the ThreadManager is started.
+	PendingOnStartIDs []string `json:"pendingOnStartIDs"`
+}
+
+// Snapshot returns the current state of the ThreadManager.
+// Note that the state may already have changed when the snapshot is returned, so it should only be used for informational purposes.
+func (tm *ThreadManager) Snapshot() ManagerSnapshot {
+	tm.lock.Lock()
+	res := ManagerSnapshot{
+		Started:           tm.isStarted(),
+		Stopped:           tm.stopped.Load(),
+		PendingOnStartIDs: slices.Sorted(maps.Keys(tm.runOnStart)),
+	}
+	tm.lock.Unlock()
+	res.Draining = tm.draining.Load() && !res.Stopped
+	tm.lockThreadMap.Lock()
+	res.RunningThreadIDs = slices.Sorted(maps.Keys(tm.threadCancelFuncs))
+	tm.lockThreadMap.Unlock()
+	if res.PendingOnStartIDs == nil {
+		res.PendingOnStartIDs = []string{}
+	}
+	if res.RunningThreadIDs == nil {
+		res.RunningThreadIDs = []string{}
+	}
+	return res
+}
+
+// ThreadStats contains statistics about a thread, see ThreadInfo.
+type ThreadStats struct {
+	// StartedAt is the time at which the thread has been started most recently.
+	StartedAt time.Time `json:"startedAt"`
+	// Restarts is the number of times the thread has been restarted via one of the ThreadManager's Restart... methods.
+	Restarts int `json:"restarts"`
+	// Running is true if the thread is currently running.
+	Running bool `json:"running"`
+}
+
+// ThreadInfo returns statistics about the thread with the given id.
+// The second return value is false if no thread with the given id has been run by this ThreadManager yet.
+// Threads which have been added before the ThreadManager was started are only known after it has been started.
+// Note that the statistics are kept after a thread has finished, so that they are preserved across restarts.
+// They are never removed for the lifetime of the ThreadManager, so threads with dynamic ids (e.g. one per reconciled object) accumulate one entry per id.
+func (tm *ThreadManager) ThreadInfo(id string) (ThreadStats, bool) {
+	tm.lockThreadMap.Lock()
+	defer tm.lockThreadMap.Unlock()
+	stats, ok := tm.threadStats[id]
+	if !ok {
+		return ThreadStats{}, false
+	}
+	res := *stats
+	_, res.Running = tm.threadCancelFuncs[id]
+	return res, true
+}
+
+// WaitForThread blocks until the thread with the given id finishes the next time and returns its ThreadReturn.
+// Only completions after WaitForThread has been called are taken into account, so for a thread which has already finished, it blocks until the thread is run and finishes again.
+// The waiter is notified after the thread's onFinish functions have been called.
+// Returns the context's error if the context is cancelled before the thread finishes and ErrManagerStopped if the ThreadManager is stopped.
+func (tm *ThreadManager) WaitForThread(ctx context.Context, id string) (ThreadReturn, error) {
+	w := make(chan ThreadReturn, 1)
+	tm.lockThreadMap.Lock()
+	tm.threadWaiters[id] = append(tm.threadWaiters[id], w)
+	tm.lockThreadMap.Unlock()
+	removeWaiter := func() {
+		tm.lockThreadMap.Lock()
+		defer tm.lockThreadMap.Unlock()
+		tm.threadWaiters[id] = slices.DeleteFunc(tm.threadWaiters[id], func(x chan ThreadReturn) bool { return x == w })
+		if len(tm.threadWaiters[id]) == 0 {
+			delete(tm.threadWaiters, id)
+		}
+	}
+
+	select {
+	case tr := <-w:
+		return tr, nil
+	case <-ctx.Done():
+		removeWaiter()
+		return ThreadReturn{}, ctx.Err()
+	case <-tm.notifyOnStop:
+		removeWaiter()
+		// the thread might have finished while the ThreadManager was being stopped
+		select {
+		case tr := <-w:
+			return tr, nil
+		default:
+			return ThreadReturn{}, ErrManagerStopped
+		}
+	}
+}
+
 // Wait blocks until the ThreadManager has been stopped and all threads have finished.
 // Returns immediately if the ThreadManager has not been started yet.
 func (tm *ThreadManager) Wait() {
@@ -265,10 +475,16 @@ var _ OnFinishFunc = (*ThreadManager)(nil).Restart
 //
 //	tm.Run(ctx, "myThread", myWorkFunc, tm.Restart)
 func (tm *ThreadManager) Restart(_ context.Context, tr ThreadReturn) {
-	if tm.stopped.Load() {
+	if tm.stopped.Load() || tm.draining.Load() {
 		return
 	}
-	tm.RunThread(*tr.Thread)
+	t := *tr.Thread
+	t.restarted = true
+	if t.parent != nil {
+		// the context of the finished run has been cancelled, derive a new one for the restarted run
+		t.ctx, t.cancel = context.WithCancel(t.parent)
+	}
+	tm.RunThread(t)
 }
 
 var _ OnFinishFunc = (*ThreadManager)(nil).RestartOnError
@@ -302,8 +518,10 @@ func (tm *ThreadManager) RestartOnSuccess(ctx context.Context, tr ThreadReturn)
 // A new context with a cancel function is derived from the context passed to the constructor.
 // The Thread's fields are considered immutable after creation.
 func NewThread(ctx context.Context, id string, work WorkFunc, onFinish OnFinishFunc) Thread {
+	parent := ctx
 	ctx, cancel := context.WithCancel(ctx)
 	return Thread{
+		parent:   parent,
 		ctx:      ctx,
 		cancel:   cancel,
 		id:       id,
@@ -314,11 +532,14 @@ func NewThread(ctx context.Context, id string, work WorkFunc, onFinish OnFinishF
 
 // Thread represents a thread that can be run by the ThreadManager.
 type Thread struct {
-	ctx      context.Context
-	cancel   context.CancelFunc
-	id       string
-	work     WorkFunc
-	onFinish OnFinishFunc
+	parent     context.Context // the context the thread's context has been derived from, used for restarting the thread
+	ctx        context.Context
+	cancel     context.CancelFunc
+	id         string
+	work       WorkFunc
+	onFinish   OnFinishFunc
+	deferReady bool // if true, the readiness grace period is started by the work function itself (used by RunAfter)
+	restarted  bool // if true, the thread has been restarted via Restart and is counted as restart when it is run
 }
 
 // Context returns the context of the thread.
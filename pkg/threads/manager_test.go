@@ -2,6 +2,8 @@ package threads_test
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"strconv"
 	"sync/atomic"
 	"time"
@@ -212,6 +214,361 @@ var _ = Describe("ThreadManager", func() {
 			Expect(t.Value()).To(BeNumerically("==", 2*threadCount*addPerThread))
 		})
 
+		It("should let running threads finish without cancelling them when draining", func() {
+			mgr := threads.NewThreadManager(context.Background(), nil)
+			var cancelled atomic.Bool
+			var finished atomic.Bool
+			release := make(chan struct{})
+			mgr.Run(context.Background(), "oneshot", func(ctx context.Context) error {
+				select {
+				case <-ctx.Done():
+					cancelled.Store(true)
+				case <-release:
+					finished.Store(true)
+				}
+				return nil
+			}, nil)
+			mgr.Start()
+			go func() {
+				time.Sleep(500 * time.Millisecond)
+				close(release)
+			}()
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			mgr.Drain(ctx)
+			Expect(finished.Load()).To(BeTrue())
+			Expect(cancelled.Load()).To(BeFalse())
+			Expect(mgr.IsStopped()).To(BeTrue())
+		})
+
+		It("should correctly return whether the manager is draining", func() {
+			mgr := threads.NewThreadManager(context.Background(), nil)
+			release := make(chan struct{})
+			mgr.Run(context.Background(), "oneshot", func(ctx context.Context) error {
+				<-release
+				return nil
+			}, nil)
+			Expect(mgr.IsDraining()).To(BeFalse())
+			mgr.Start()
+			Expect(mgr.IsRunning()).To(BeTrue())
+			Expect(mgr.IsDraining()).To(BeFalse())
+			drained := make(chan struct{})
+			go func() {
+				defer close(drained)
+				mgr.Drain(context.Background())
+			}()
+			Eventually(mgr.IsDraining).Should(BeTrue())
+			Expect(mgr.IsStopped()).To(BeFalse())
+			close(release)
+			Eventually(drained).Should(BeClosed())
+			Expect(mgr.IsStopped()).To(BeTrue())
+			Expect(mgr.IsDraining()).To(BeFalse())
+		})
+
+		It("should reject new threads while draining", func() {
+			t := &testValue{}
+			mgr := threads.NewThreadManager(context.Background(), nil)
+			release := make(chan struct{})
+			mgr.Run(context.Background(), "oneshot", func(ctx context.Context) error {
+				<-release
+				return nil
+			}, nil)
+			mgr.Start()
+			drained := make(chan struct{})
+			go func() {
+				defer close(drained)
+				mgr.Drain(context.Background())
+			}()
+			Eventually(mgr.IsDraining).Should(BeTrue())
+			mgr.Run(context.Background(), "new", t.AddFuncRun(1), nil)
+			close(release)
+			Eventually(drained).Should(BeClosed())
+			Expect(t.Value()).To(BeZero())
+		})
+
+		It("should fall back to stopping and cancel running threads if the drain context expires", func() {
+			mgr := threads.NewThreadManager(context.Background(), nil)
+			var cancelled atomic.Bool
+			mgr.Run(context.Background(), "sleep", func(ctx context.Context) error {
+				select {
+				case <-ctx.Done():
+					cancelled.Store(true)
+				case <-time.After(10 * time.Second):
+				}
+				return nil
+			}, nil)
+			mgr.Start()
+			now := time.Now()
+			ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+			defer cancel()
+			mgr.Drain(ctx)
+			Expect(time.Now()).To(BeTemporally("<", now.Add(3*time.Second)))
+			Expect(cancelled.Load()).To(BeTrue())
+			Expect(mgr.IsStopped()).To(BeTrue())
+		})
+
+		It("should panic if Drain() is called before Start()", func() {
+			mgr := threads.NewThreadManager(context.Background(), nil)
+			Expect(func() { mgr.Drain(context.Background()) }).To(Panic())
+		})
+
+		It("should start a dependent thread only after its dependency signaled readiness", func() {
+			mgr := threads.NewThreadManager(context.Background(), nil)
+			var depReady atomic.Bool
+			var startedBeforeReady atomic.Bool
+			dependentStarted := make(chan struct{})
+			mgr.RunAfter(context.Background(), "dependent", []string{"dependency"}, func(ctx context.Context) error {
+				startedBeforeReady.Store(!depReady.Load())
+				close(dependentStarted)
+				return nil
+			}, nil)
+			mgr.Run(context.Background(), "dependency", func(ctx context.Context) error {
+				time.Sleep(300 * time.Millisecond)
+				depReady.Store(true)
+				threads.SignalReady(ctx)
+				<-ctx.Done()
+				return nil
+			}, nil)
+			mgr.Start()
+			Consistently(dependentStarted, 200*time.Millisecond).ShouldNot(BeClosed())
+			Eventually(dependentStarted, 2*time.Second).Should(BeClosed())
+			Expect(startedBeforeReady.Load()).To(BeFalse())
+			mgr.Stop()
+		})
+
+		It("should consider a dependency ready after the grace period", func() {
+			mgr := threads.NewThreadManager(context.Background(), nil).WithReadyGracePeriod(300 * time.Millisecond)
+			dependentStarted := make(chan struct{})
+			mgr.Run(context.Background(), "dependency", func(ctx context.Context) error {
+				<-ctx.Done()
+				return nil
+			}, nil)
+			mgr.RunAfter(context.Background(), "dependent", []string{"dependency"}, func(ctx context.Context) error {
+				close(dependentStarted)
+				return nil
+			}, nil)
+			mgr.Start()
+			Consistently(dependentStarted, 150*time.Millisecond).ShouldNot(BeClosed())
+			Eventually(dependentStarted, 2*time.Second).Should(BeClosed())
+			mgr.Stop()
+		})
+
+		It("should not consider a dependency ready if it finished before the grace period", func() {
+			mgr := threads.NewThreadManager(context.Background(), nil).WithReadyGracePeriod(200 * time.Millisecond)
+			dependentStarted := make(chan struct{})
+			mgr.Run(context.Background(), "dependency", func(ctx context.Context) error {
+				return nil
+			}, nil)
+			mgr.RunAfter(context.Background(), "dependent", []string{"dependency"}, func(ctx context.Context) error {
+				close(dependentStarted)
+				return nil
+			}, nil)
+			mgr.Start()
+			Consistently(dependentStarted, 500*time.Millisecond).ShouldNot(BeClosed())
+			mgr.Stop()
+		})
+
+		It("should restart the grace period if a dependency is restarted before it became ready", func() {
+			mgr := threads.NewThreadManager(context.Background(), nil).WithReadyGracePeriod(400 * time.Millisecond)
+			dependentStarted := make(chan struct{})
+			var runs atomic.Int32
+			mgr.Run(context.Background(), "dependency", func(ctx context.Context) error {
+				if runs.Add(1) == 1 {
+					time.Sleep(200 * time.Millisecond)
+					return errors.New("error")
+				}
+				<-ctx.Done()
+				return nil
+			}, mgr.RestartOnError)
+			mgr.RunAfter(context.Background(), "dependent", []string{"dependency"}, func(ctx context.Context) error {
+				close(dependentStarted)
+				return nil
+			}, nil)
+			mgr.Start()
+			// the grace period of the first run would have expired after 400ms, the one of the second run expires after ~600ms
+			Consistently(dependentStarted, 500*time.Millisecond).ShouldNot(BeClosed())
+			Eventually(dependentStarted, 2*time.Second).Should(BeClosed())
+			Expect(runs.Load()).To(BeEquivalentTo(2))
+			mgr.Stop()
+		})
+
+		It("should not run the work function of a dependent thread if the manager is stopped while waiting", func() {
+			mgr := threads.NewThreadManager(context.Background(), nil).WithReadyGracePeriod(0)
+			var executed atomic.Bool
+			var waitErr atomic.Value
+			mgr.RunAfter(context.Background(), "dependent", []string{"missing"}, func(ctx context.Context) error {
+				executed.Store(true)
+				return nil
+			}, func(ctx context.Context, tr threads.ThreadReturn) {
+				waitErr.Store(tr.Err)
+			})
+			mgr.Start()
+			time.Sleep(100 * time.Millisecond)
+			mgr.Stop()
+			Expect(executed.Load()).To(BeFalse())
+			Expect(waitErr.Load()).To(MatchError(context.Canceled))
+		})
+
+		It("should reflect added, running, and pending threads in the snapshot", func() {
+			mgr := threads.NewThreadManager(context.Background(), nil)
+			release := make(chan struct{})
+			blocking := func(ctx context.Context) error {
+				<-release
+				return nil
+			}
+			mgr.Run(context.Background(), "b", blocking, nil)
+			mgr.Run(context.Background(), "a", blocking, nil)
+			snap := mgr.Snapshot()
+			Expect(snap.Started).To(BeFalse())
+			Expect(snap.Stopped).To(BeFalse())
+			Expect(snap.Draining).To(BeFalse())
+			Expect(snap.PendingOnStartIDs).To(Equal([]string{"a", "b"}))
+			Expect(snap.RunningThreadIDs).To(BeEmpty())
+
+			mgr.Start()
+			mgr.Run(context.Background(), "c", blocking, nil)
+			snap = mgr.Snapshot()
+			Expect(snap.Started).To(BeTrue())
+			Expect(snap.PendingOnStartIDs).To(BeEmpty())
+			Expect(snap.RunningThreadIDs).To(Equal([]string{"a", "b", "c"}))
+
+			data, err := json.Marshal(snap)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(data)).To(Equal(`{"started":true,"stopped":false,"draining":false,"runningThreadIDs":["a","b","c"],"pendingOnStartIDs":[]}`))
+
+			close(release)
+			Eventually(func() []string { return mgr.Snapshot().RunningThreadIDs }).Should(BeEmpty())
+			mgr.Stop()
+			snap = mgr.Snapshot()
+			Expect(snap.Stopped).To(BeTrue())
+			Expect(snap.Draining).To(BeFalse())
+		})
+
+		It("should track the start time and restart count of threads", func() {
+			mgr := threads.NewThreadManager(context.Background(), nil)
+			_, ok := mgr.ThreadInfo("restarting")
+			Expect(ok).To(BeFalse())
+			mgr.Start()
+			defer mgr.Stop()
+
+			runs := atomic.Int32{}
+			done := make(chan struct{})
+			before := time.Now()
+			mgr.Run(context.Background(), "restarting", func(ctx context.Context) error {
+				if runs.Add(1) <= 2 {
+					return errors.New("error")
+				}
+				close(done)
+				return nil
+			}, mgr.RestartOnError)
+			Eventually(done).Should(BeClosed())
+			Eventually(func() bool {
+				stats, _ := mgr.ThreadInfo("restarting")
+				return stats.Running
+			}).Should(BeFalse())
+
+			stats, ok := mgr.ThreadInfo("restarting")
+			Expect(ok).To(BeTrue())
+			Expect(stats.Restarts).To(Equal(2))
+			Expect(stats.StartedAt).To(BeTemporally(">=", before))
+			Expect(runs.Load()).To(BeEquivalentTo(3))
+		})
+
+		It("should pass a context which is not cancelled to restarted threads", func() {
+			mgr := threads.NewThreadManager(context.Background(), nil)
+			mgr.Start()
+			defer mgr.Stop()
+
+			runs := atomic.Int32{}
+			ctxErrs := make(chan error, 1)
+			mgr.Run(context.Background(), "restarting", func(ctx context.Context) error {
+				if runs.Add(1) == 1 {
+					return errors.New("error")
+				}
+				ctxErrs <- ctx.Err()
+				return nil
+			}, mgr.RestartOnError)
+			var ctxErr error
+			Eventually(ctxErrs).Should(Receive(&ctxErr))
+			Expect(ctxErr).ToNot(HaveOccurred())
+			Expect(runs.Load()).To(BeEquivalentTo(2))
+		})
+
+		It("should return an error from TryRun if the manager is not running", func() {
+			mgr := threads.NewThreadManager(context.Background(), nil)
+			tv := &testValue{}
+			Expect(mgr.TryRun(context.Background(), "test", tv.AddFuncRun(1), nil)).To(MatchError(threads.ErrManagerNotStarted))
+			Expect(mgr.Snapshot().PendingOnStartIDs).To(BeEmpty())
+
+			mgr.Start()
+			Expect(tv.Value()).To(BeEquivalentTo(0))
+			Expect(mgr.TryRun(context.Background(), "test", tv.AddFuncRun(1), nil)).To(Succeed())
+			Eventually(tv.Value).Should(BeEquivalentTo(1))
+
+			mgr.Stop()
+			Expect(mgr.TryRun(context.Background(), "test", tv.AddFuncRun(1), nil)).To(MatchError(threads.ErrManagerStopped))
+			Consistently(tv.Value, 100*time.Millisecond).Should(BeEquivalentTo(1))
+		})
+
+		It("should return an error from TryRun if the manager is draining", func() {
+			mgr := threads.NewThreadManager(context.Background(), nil)
+			mgr.Start()
+			release := make(chan struct{})
+			mgr.Run(context.Background(), "blocking", func(ctx context.Context) error {
+				<-release
+				return nil
+			}, nil)
+			drained := make(chan struct{})
+			go func() {
+				defer close(drained)
+				mgr.Drain(context.Background())
+			}()
+			Eventually(mgr.IsDraining).Should(BeTrue())
+			Expect(mgr.TryRun(context.Background(), "test", nil, nil)).To(MatchError(threads.ErrManagerDraining))
+			close(release)
+			Eventually(drained).Should(BeClosed())
+		})
+
+		It("should return the result of the next completion of a thread from WaitForThread", func() {
+			mgr := threads.NewThreadManager(context.Background(), nil)
+			mgr.Start()
+			defer mgr.Stop()
+
+			errThread := errors.New("thread error")
+			release := make(chan struct{})
+			mgr.Run(context.Background(), "failing", func(ctx context.Context) error {
+				<-release
+				return errThread
+			}, nil)
+			var tr threads.ThreadReturn
+			var err error
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				tr, err = mgr.WaitForThread(context.Background(), "failing")
+			}()
+			Consistently(done, 100*time.Millisecond).ShouldNot(BeClosed())
+			close(release)
+			Eventually(done).Should(BeClosed())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tr.Err).To(MatchError(errThread))
+			Expect(tr.Thread.ID()).To(Equal("failing"))
+		})
+
+		It("should stop waiting for a thread if the context is cancelled or the manager is stopped", func() {
+			mgr := threads.NewThreadManager(context.Background(), nil)
+			mgr.Start()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			_, err := mgr.WaitForThread(ctx, "missing")
+			Expect(err).To(MatchError(context.DeadlineExceeded))
+
+			go mgr.Stop()
+			_, err = mgr.WaitForThread(context.Background(), "missing")
+			Expect(err).To(MatchError(threads.ErrManagerStopped))
+		})
+
 		It("should panic if Start() is called after Stop()", func() {
 			mgr := threads.NewThreadManager(context.Background(), nil)
 			mgr.Start()
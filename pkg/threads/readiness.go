@@ -0,0 +1,192 @@
+package threads
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultReadyGracePeriod is the default duration after which a running thread is considered ready, even if it did not signal readiness.
+const DefaultReadyGracePeriod = 30 * time.Second
+
+type readyContextKey struct{}
+
+// readiness tracks whether a single run of a thread has signaled readiness.
+// Each run of a thread gets its own tracker, which is finished when the run returns.
+type readiness struct {
+	readyCh  chan struct{} // closed when the thread is ready
+	doneCh   chan struct{} // closed when the run this tracker belongs to has finished
+	claimed  bool          // true if the tracker belongs to a run, false if it has only been created by a waiting dependent, guarded by the ThreadManager's lockReadiness
+	lock     sync.Mutex    // lock for the fields below
+	ready    bool
+	finished bool
+	timer    *time.Timer // timer for the grace period, if any
+}
+
+func newReadiness() *readiness {
+	return &readiness{
+		readyCh: make(chan struct{}),
+		doneCh:  make(chan struct{}),
+	}
+}
+
+// signal marks the thread as ready. Calling it multiple times is safe.
+// It is a no-op if the run the tracker belongs to has already finished.
+func (r *readiness) signal() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.ready || r.finished {
+		return
+	}
+	r.ready = true
+	close(r.readyCh)
+}
+
+// startTimer marks the thread as ready after the given grace period, unless the given context is done by then or the run has finished.
+func (r *readiness) startTimer(ctx context.Context, gracePeriod time.Duration) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.finished || ctx.Err() != nil {
+		return
+	}
+	if r.timer != nil {
+		r.timer.Stop()
+	}
+	timer := time.AfterFunc(gracePeriod, func() {
+		if ctx.Err() == nil {
+			r.signal()
+		}
+	})
+	r.timer = timer
+	// stop the timer when the thread's context is done, the check above covers the case that both happen at the same time
+	context.AfterFunc(ctx, func() { timer.Stop() })
+}
+
+// finish marks the run the tracker belongs to as finished and stops the grace period timer.
+// Calling it multiple times is safe.
+func (r *readiness) finish() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.finished {
+		return
+	}
+	r.finished = true
+	if r.timer != nil {
+		r.timer.Stop()
+	}
+	close(r.doneCh)
+}
+
+// SignalReady marks the thread the given context belongs to as ready.
+// Threads which have been started via RunAfter and depend on this thread are started once all of their dependencies are ready.
+// The context must be the one that was passed into the thread's work function, otherwise this is a no-op.
+// Calling this multiple times is safe.
+func SignalReady(ctx context.Context) {
+	if r, ok := ctx.Value(readyContextKey{}).(*readiness); ok && r != nil {
+		r.signal()
+	}
+}
+
+// WithReadyGracePeriod sets the duration after which a running thread is considered ready, even if it did not call SignalReady.
+// This affects threads which are started via RunAfter and depend on threads which do not signal readiness explicitly.
+// A value less than or equal to zero disables the grace period, so dependencies have to signal readiness explicitly.
+// Defaults to DefaultReadyGracePeriod.
+// Returns the ThreadManager for chaining.
+func (tm *ThreadManager) WithReadyGracePeriod(gracePeriod time.Duration) *ThreadManager {
+	tm.lockReadiness.Lock()
+	defer tm.lockReadiness.Unlock()
+	tm.readyGracePeriod = gracePeriod
+	return tm
+}
+
+// RunAfter works like Run, but the thread's work function is only executed after all threads with the ids specified in dependsOn are ready.
+// A thread is ready if it called SignalReady with the context passed into its work function, or if it has been running for the grace period configured via WithReadyGracePeriod.
+// Readiness is tracked per run: a thread which has finished is not considered ready anymore, and a restarted thread has to become ready again.
+// Dependencies which have not been run yet are waited for, too.
+// If the thread's context is cancelled while waiting, e.g. because the ThreadManager is being stopped, the thread finishes with the context's error without executing the work function.
+// Note that the thread counts as running while waiting for its dependencies, so starting another thread with the same id cancels the waiting one.
+// The grace period of a thread started via RunAfter only starts after all of its dependencies are ready.
+func (tm *ThreadManager) RunAfter(ctx context.Context, id string, dependsOn []string, work WorkFunc, onFinish OnFinishFunc) {
+	t := NewThread(ctx, id, func(ctx context.Context) error {
+		for _, dep := range dependsOn {
+			for waiting := true; waiting; {
+				r := tm.readinessFor(dep, false)
+				select {
+				case <-r.readyCh:
+					waiting = false
+				case <-r.doneCh:
+					// the dependency finished without becoming ready, wait for its next run
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+		}
+		tm.startGracePeriod(ctx)
+		if work == nil {
+			return nil
+		}
+		return work(ctx)
+	}, onFinish)
+	t.deferReady = true
+	tm.RunThread(t)
+}
+
+// readinessFor returns the current readiness tracker for the thread with the given id.
+// It is created, if it does not exist yet.
+// If claim is true, the tracker is claimed for a new run of the thread. This is used when a thread is (re-)started, so that each run's readiness is tracked anew.
+// A tracker which has only been created by a waiting dependent is reused, while a tracker which already belongs to another run is finished and replaced.
+func (tm *ThreadManager) readinessFor(id string, claim bool) *readiness {
+	tm.lockReadiness.Lock()
+	defer tm.lockReadiness.Unlock()
+	r, ok := tm.readiness[id]
+	if !ok || (claim && r.claimed) {
+		if ok {
+			// the previous run is being replaced, dependents waiting for it will fetch the new tracker
+			r.finish()
+		}
+		r = newReadiness()
+		tm.readiness[id] = r
+	}
+	if claim {
+		r.claimed = true
+	}
+	return r
+}
+
+// startReadiness prepares the readiness tracking for the given thread, which is about to be run.
+// It returns the context to be passed into the thread's work function and the tracker of this run, which has to be passed to finishReadiness when the run has finished.
+// Unless the thread defers its readiness (because it has been started via RunAfter), the grace period is started immediately.
+func (tm *ThreadManager) startReadiness(t *Thread) (context.Context, *readiness) {
+	r := tm.readinessFor(t.id, true)
+	ctx := context.WithValue(t.ctx, readyContextKey{}, r)
+	if !t.deferReady {
+		tm.startGracePeriod(ctx)
+	}
+	return ctx, r
+}
+
+// finishReadiness marks the given run of the thread with the given id as finished.
+// Its grace period is stopped and the tracker is removed, so that the thread is not considered ready anymore until it is run again.
+func (tm *ThreadManager) finishReadiness(id string, r *readiness) {
+	tm.lockReadiness.Lock()
+	if tm.readiness[id] == r {
+		delete(tm.readiness, id)
+	}
+	tm.lockReadiness.Unlock()
+	r.finish()
+}
+
+// startGracePeriod starts the grace period for the thread the given context belongs to.
+// After the grace period has passed, the thread is considered ready, unless the context is done or the run has finished by then.
+func (tm *ThreadManager) startGracePeriod(ctx context.Context) {
+	r, ok := ctx.Value(readyContextKey{}).(*readiness)
+	if !ok || r == nil {
+		return
+	}
+	tm.lockReadiness.Lock()
+	gracePeriod := tm.readyGracePeriod
+	tm.lockReadiness.Unlock()
+	if gracePeriod > 0 {
+		r.startTimer(ctx, gracePeriod)
+	}
+}